@@ -1,7 +1,15 @@
 package main
 
-import "github.com/adanyl0v/pocket-ideas/internal/app"
+import (
+	"github.com/adanyl0v/pocket-ideas/internal/app"
+	"os"
+)
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		app.Migrate(os.Args[2:])
+		return
+	}
+
 	app.Run()
 }
@@ -5,17 +5,28 @@ import (
 	"fmt"
 	stdslog "log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
-	"time"
+	"syscall"
 
 	"github.com/adanyl0v/pocket-ideas/internal/config"
 	pgrepo "github.com/adanyl0v/pocket-ideas/internal/repository/postgres"
 	redisrepo "github.com/adanyl0v/pocket-ideas/internal/repository/redis"
+	"github.com/adanyl0v/pocket-ideas/internal/service/auth"
+	httpserver "github.com/adanyl0v/pocket-ideas/internal/transport/http"
 	"github.com/adanyl0v/pocket-ideas/pkg/cache"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/lock"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/memory"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/ratelimit"
 	rediscache "github.com/adanyl0v/pocket-ideas/pkg/cache/redis"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
 	postgresdb "github.com/adanyl0v/pocket-ideas/pkg/database/postgres/pgx"
+	"github.com/adanyl0v/pocket-ideas/pkg/jwt"
 	"github.com/adanyl0v/pocket-ideas/pkg/log"
 	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/adanyl0v/pocket-ideas/pkg/password"
+	"github.com/adanyl0v/pocket-ideas/pkg/password/argon2"
+	"github.com/adanyl0v/pocket-ideas/pkg/password/bcrypt"
 	googleuuidgen "github.com/adanyl0v/pocket-ideas/pkg/uuid/google"
 	slogzap "github.com/samber/slog-zap/v2"
 	"go.uber.org/zap"
@@ -27,19 +38,103 @@ func Run() {
 	logger := mustSetupLogger(cfg.Env, &cfg.Log)
 	logger.With(log.Fields{"env": cfg.Env}).Info("read config")
 
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	lc := NewLifecycle(logger, cfg.ShutdownTimeout)
+
 	postgresDb := mustConnectToPostgres(logger, &cfg.PostgresConfig)
-	defer postgresDb.Close()
+	postgresRouter := mustSetupPostgresRouter(logger, postgresDb, &cfg.PostgresConfig)
+	lc.Register(Component{
+		Name: "postgres",
+		Run:  postgresRouter.Run,
+		Stop: func(context.Context) error {
+			postgresRouter.Close()
+			return nil
+		},
+	})
 
-	redisCache := mustConnectToRedis(logger, &cfg.RedisConfig)
-	defer func() { _ = redisCache.Close() }()
+	migrator := mustSetupMigrator(logger, postgresDb, &cfg.MigrateConfig)
+	if cfg.MigrateConfig.AutoMigrate {
+		lc.Register(Component{
+			Name:  "migrations",
+			Start: migrator.Up,
+		})
+	}
+	lc.Register(Component{
+		Name:  "schema check",
+		Start: newSchemaCheck(logger, migrator, cfg.MigrateConfig.SchemaCheck),
+	})
+
+	cacheClient := mustSetupCache(logger, lc, &cfg.CacheConfig, &cfg.RedisConfig)
 
-	userRepo := pgrepo.NewUserRepository(postgresDb, logger, googleuuidgen.New())
+	userRepo := pgrepo.NewUserRepository(postgresRouter, logger, googleuuidgen.New())
 	logger.Info("created a user repository")
-	_ = userRepo
 
-	authRepo := redisrepo.NewAuthRepository(redisCache, redisCache, redisCache, logger, googleuuidgen.New())
+	ideaRepo := pgrepo.NewIdeaRepository(postgresRouter, logger, googleuuidgen.New())
+	logger.Info("created an idea repository")
+
+	txRunner := database.NewTxRunner(postgresRouter.Primary(), logger)
+	logger.Info("created a transaction runner")
+
+	authRepo := redisrepo.NewAuthRepository(cacheClient, cacheClient, cacheClient, logger, googleuuidgen.New())
 	logger.Info("created an auth repository")
-	_ = authRepo
+
+	hasher := mustSetupPasswordHasher(&cfg.PasswordConfig)
+	logger.With(log.Fields{"algorithm": cfg.PasswordConfig.Algorithm}).Info("created a password hasher")
+
+	jwtManager := mustSetupJWTManager(&cfg.JWTConfig)
+	logger.With(log.Fields{"signing_key_id": cfg.JWTConfig.SigningKeyID}).Info("created a jwt manager")
+
+	locker := lock.NewLocker(cacheClient, logger, nil)
+	logger.Info("created a locker")
+
+	authService := auth.NewService(userRepo, authRepo, hasher, auth.NewJWTManager(jwtManager), locker, logger, &auth.Config{
+		AccessTokenTTL:     cfg.AuthConfig.AccessTokenTTL,
+		RefreshTokenTTL:    cfg.AuthConfig.RefreshTokenTTL,
+		MaxSessionsPerUser: cfg.AuthConfig.MaxSessionsPerUser,
+	})
+	logger.Info("created an auth service")
+
+	loginLimiter := mustSetupRateLimiter(logger, cacheClient, "login", &ratelimit.Config{
+		Algorithm: cfg.AuthConfig.LoginRateLimitAlgorithm,
+		Limit:     cfg.AuthConfig.LoginRateLimit,
+		Window:    cfg.AuthConfig.LoginRateLimitWindow,
+	})
+	refreshLimiter := mustSetupRateLimiter(logger, cacheClient, "refresh", &ratelimit.Config{
+		Algorithm: cfg.AuthConfig.RefreshRateLimitAlgorithm,
+		Limit:     cfg.AuthConfig.RefreshRateLimit,
+		Window:    cfg.AuthConfig.RefreshRateLimitWindow,
+	})
+
+	router := httpserver.NewRouter(logger,
+		httpserver.NewAuthHandler(authService, loginLimiter, refreshLimiter, logger),
+		httpserver.NewUserHandler(userRepo, ideaRepo, txRunner, hasher, authService, logger),
+		httpserver.NewIdeaHandler(ideaRepo, authService, logger),
+		httpserver.NewSessionHandler(authRepo, authService, logger),
+	)
+
+	server := httpserver.NewServer(logger, &httpserver.Config{
+		Address:      cfg.HTTPConfig.Address,
+		ReadTimeout:  cfg.HTTPConfig.ReadTimeout,
+		WriteTimeout: cfg.HTTPConfig.WriteTimeout,
+		IdleTimeout:  cfg.HTTPConfig.IdleTimeout,
+	}, router)
+
+	lc.Register(Component{
+		Name: "http server",
+		Run: func(context.Context) error {
+			return server.ListenAndServe()
+		},
+		Stop: server.Shutdown,
+	})
+
+	if err := lc.Run(ctx); err != nil {
+		logger.WithError(err).Error("the application stopped with an error")
+		os.Exit(1)
+	}
+
+	logger.Info("the application stopped")
 }
 
 func mustSetupLogger(env string, cfg *config.LogConfig) log.Logger {
@@ -127,25 +222,66 @@ func mustSetupLogger(env string, cfg *config.LogConfig) log.Logger {
 	return l
 }
 
-func mustConnectToPostgres(logger log.Logger, cfg *config.PostgresConfig) *postgresdb.Client {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// mustSetupPasswordHasher returns a hasher that computes new hashes with the
+// configured algorithm, but still verifies the ones computed with the other
+func mustSetupPasswordHasher(cfg *config.PasswordConfig) password.Hasher {
+	argon2Params := argon2.DefaultParams()
+	argon2Params.Memory = cfg.Argon2Memory
+	argon2Params.Iterations = cfg.Argon2Iterations
+	argon2Params.Parallelism = cfg.Argon2Parallelism
 
-	pgConf := postgresdb.Config{
-		Host:              cfg.Host,
-		Port:              cfg.Port,
-		User:              cfg.User,
-		Password:          cfg.Password,
-		Database:          cfg.Database,
-		SSLMode:           cfg.SSLMode,
-		MaxConns:          cfg.MaxConns,
-		MinConns:          cfg.MinConns,
-		MaxConnIdleTime:   cfg.MaxConnIdleTime,
-		MaxConnLifetime:   cfg.MaxConnLifetime,
-		HealthCheckPeriod: cfg.HealthCheckPeriod,
+	if err := argon2Params.Validate(); err != nil {
+		panic(err)
+	}
+
+	if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+		panic(fmt.Errorf("invalid bcrypt cost: %d", cfg.BcryptCost))
 	}
 
-	client, err := postgresdb.Connect(ctx, logger, &pgConf)
+	argon2Hasher := argon2.New(argon2Params)
+	bcryptHasher := bcrypt.New(cfg.BcryptCost)
+
+	switch cfg.Algorithm {
+	case config.PasswordAlgorithmArgon2id:
+		return password.NewChain(argon2Hasher, bcryptHasher)
+	case config.PasswordAlgorithmBcrypt:
+		return password.NewChain(bcryptHasher, argon2Hasher)
+	default:
+		panic(fmt.Errorf("invalid password algorithm: %s", cfg.Algorithm))
+	}
+}
+
+func mustSetupJWTManager(cfg *config.JWTConfig) *jwt.Manager {
+	keys, err := jwt.LoadKeySet(cfg.KeysDir, cfg.SigningKeyID)
+	if err != nil {
+		panic(err)
+	}
+
+	return jwt.NewManager(keys, &jwt.Config{
+		Issuer:   cfg.Issuer,
+		Audience: cfg.Audience,
+		Leeway:   cfg.Leeway,
+	})
+}
+
+func mustSetupRateLimiter(logger log.Logger, store ratelimit.Store, name string, cfg *ratelimit.Config) *ratelimit.Limiter {
+	limiter, err := ratelimit.NewLimiter(store, logger, name, cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	logger.With(log.Fields{
+		"rate_limit": name,
+		"algorithm":  cfg.Algorithm,
+	}).Info("created a rate limiter")
+	return limiter
+}
+
+func mustConnectToPostgres(logger log.Logger, cfg *config.PostgresConfig) *postgresdb.Client {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnTimout)
+	defer cancel()
+
+	client, err := postgresdb.Connect(ctx, logger, newPostgresConfig(cfg))
 	if err != nil {
 		panic(err)
 	}
@@ -154,6 +290,85 @@ func mustConnectToPostgres(logger log.Logger, cfg *config.PostgresConfig) *postg
 	return client
 }
 
+// mustSetupPostgresRouter creates the replicas, which share all the settings
+// with the primary, except for the address. A replica, which is down, doesn't
+// stop the start, as the router stops sending the reads to it once it's found
+// unhealthy and until it recovers
+func mustSetupPostgresRouter(logger log.Logger, primary *postgresdb.Client, cfg *config.PostgresConfig) *postgresdb.Router {
+	replicas := make([]postgresdb.RouterConn, len(cfg.Replicas))
+	for i, replica := range cfg.Replicas {
+		replicaCfg := *cfg
+		replicaCfg.Host = replica.Host
+		replicaCfg.Port = replica.Port
+
+		replicaLogger := logger.With(log.Fields{"replica": i})
+		client, err := postgresdb.New(context.Background(), replicaLogger, newPostgresConfig(&replicaCfg))
+		if err != nil {
+			panic(err)
+		}
+
+		replicaLogger.Info("created a postgres replica client")
+		replicas[i] = client
+	}
+
+	return postgresdb.NewRouter(primary, replicas, logger, &postgresdb.RouterConfig{
+		HealthCheckPeriod: cfg.ReplicaHealthCheckPeriod,
+	})
+}
+
+func newPostgresConfig(cfg *config.PostgresConfig) *postgresdb.Config {
+	return &postgresdb.Config{
+		Host:                   cfg.Host,
+		Port:                   cfg.Port,
+		User:                   cfg.User,
+		Password:               cfg.Password,
+		Database:               cfg.Database,
+		SSLMode:                cfg.SSLMode,
+		MaxConns:               cfg.MaxConns,
+		MinConns:               cfg.MinConns,
+		MaxConnIdleTime:        cfg.MaxConnIdleTime,
+		MaxConnLifetime:        cfg.MaxConnLifetime,
+		HealthCheckPeriod:      cfg.HealthCheckPeriod,
+		ConnTimeout:            cfg.ConnTimout,
+		StatementTimeout:       cfg.StatementTimeout,
+		IdleInTxSessionTimeout: cfg.IdleInTxSessionTimeout,
+		QueryTimeout:           cfg.QueryTimeout,
+		QueryExecMode:          cfg.QueryExecMode,
+		PgBouncer:              cfg.PgBouncer,
+	}
+}
+
+// cacheStore is implemented by the clients of all the cache drivers
+type cacheStore interface {
+	cache.Watcher
+	lock.Store
+	ratelimit.Store
+}
+
+func mustSetupCache(logger log.Logger, lc *Lifecycle, cfg *config.CacheConfig, redisCfg *config.RedisConfig) cacheStore {
+	switch cfg.Driver {
+	case config.CacheDriverRedis:
+		client := mustConnectToRedis(logger, redisCfg)
+		lc.Register(Component{
+			Name: "redis",
+			Stop: func(context.Context) error {
+				return client.Close()
+			},
+		})
+		return client
+	case config.CacheDriverMemory:
+		client := memory.New(logger, &memory.Config{CleanupPeriod: cfg.CleanupPeriod})
+		lc.Register(Component{
+			Name: "memory cache",
+			Run:  client.Run,
+		})
+		logger.Info("created a memory cache")
+		return client
+	default:
+		panic(fmt.Errorf("invalid cache driver: %s", cfg.Driver))
+	}
+}
+
 func mustConnectToRedis(logger log.Logger, cfg *config.RedisConfig) *rediscache.Client {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
 	defer cancel()
@@ -178,11 +393,6 @@ func mustConnectToRedis(logger log.Logger, cfg *config.RedisConfig) *rediscache.
 		panic(err)
 	}
 
-	cache.DefaultScanner = rediscache.NewCursorScanner(client.DriverConn().Scan)
-	cache.DefaultSetScanner = rediscache.NewKeyCursorScanner(client.DriverConn().SScan)
-	cache.DefaultHashScanner = rediscache.NewKeyCursorScanner(client.DriverConn().HScan)
-	cache.DefaultSortedSetScanner = rediscache.NewKeyCursorScanner(client.DriverConn().ZScan)
-
 	logger.Info("connected to redis")
 	return client
 }
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"time"
+)
+
+// Component describes how the [Lifecycle] manages an application part.
+// All the hooks are optional.
+type Component struct {
+	Name string
+
+	// Start prepares the component and must not block
+	Start func(ctx context.Context) error
+
+	// Run blocks until the component is done. It is called after all the
+	// components were started. The context is canceled when the component
+	// is being stopped, so Run must return as soon as possible after that
+	Run func(ctx context.Context) error
+
+	// Stop releases the component resources. The context carries
+	// the shutdown deadline
+	Stop func(ctx context.Context) error
+}
+
+type lifecycleEntry struct {
+	Component
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Lifecycle starts the components in the order of registration and stops
+// them in the reverse order, so that a component is always stopped before
+// the ones it depends on
+type Lifecycle struct {
+	entries         []*lifecycleEntry
+	logger          log.Logger
+	shutdownTimeout time.Duration
+}
+
+func NewLifecycle(logger log.Logger, shutdownTimeout time.Duration) *Lifecycle {
+	return &Lifecycle{
+		logger:          logger,
+		shutdownTimeout: shutdownTimeout,
+	}
+}
+
+func (l *Lifecycle) Register(c Component) {
+	l.entries = append(l.entries, &lifecycleEntry{Component: c})
+}
+
+// Run starts all the components and blocks until the context is done or
+// any of the running components returns. Then it stops the components
+// within the shutdown timeout
+func (l *Lifecycle) Run(ctx context.Context) error {
+	for i, e := range l.entries {
+		if e.Start == nil {
+			continue
+		}
+
+		logger := l.logger.With(log.Fields{"component": e.Name})
+		if err := e.Start(ctx); err != nil {
+			logger.WithError(err).Error("failed to start a component")
+			return errors.Join(fmt.Errorf("start %s: %w", e.Name, err), l.stop(l.entries[:i]))
+		}
+
+		logger.Info("started a component")
+	}
+
+	runErrs := make(chan error, len(l.entries))
+	for _, e := range l.entries {
+		if e.Run == nil {
+			continue
+		}
+
+		var runCtx context.Context
+		runCtx, e.cancel = context.WithCancel(context.Background())
+		e.done = make(chan struct{})
+
+		go func(e *lifecycleEntry) {
+			defer close(e.done)
+
+			err := e.Run(runCtx)
+			if err != nil {
+				err = fmt.Errorf("run %s: %w", e.Name, err)
+			}
+
+			runErrs <- err
+		}(e)
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		l.logger.Info("received a shutdown signal")
+	case runErr = <-runErrs:
+		if runErr != nil {
+			l.logger.WithError(runErr).Error("a component failed, shutting down")
+		} else {
+			l.logger.Info("a component finished, shutting down")
+		}
+	}
+
+	return errors.Join(runErr, l.stop(l.entries))
+}
+
+// stop stops the entries in the reverse order
+func (l *Lifecycle) stop(entries []*lifecycleEntry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout)
+	defer cancel()
+
+	now := time.Now()
+	var errs []error
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		logger := l.logger.With(log.Fields{"component": e.Name})
+
+		if e.Stop != nil {
+			if err := e.Stop(ctx); err != nil {
+				logger.WithError(err).Error("failed to stop a component")
+				errs = append(errs, fmt.Errorf("stop %s: %w", e.Name, err))
+			}
+		}
+
+		if e.cancel != nil {
+			e.cancel()
+
+			select {
+			case <-e.done:
+			case <-ctx.Done():
+				logger.Error("the component didn't finish in time")
+				errs = append(errs, fmt.Errorf("stop %s: %w", e.Name, ctx.Err()))
+			}
+		}
+
+		logger.Info("stopped a component")
+	}
+
+	l.logger.With(log.Fields{"duration": time.Since(now)}).Info("stopped all components")
+	return errors.Join(errs...)
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	"testing"
+	"time"
+)
+
+func TestLifecycle_Run(t *testing.T) {
+	type lifecycleTestCase struct {
+		reg func(lc *Lifecycle, calls *[]string)
+		ctx func() context.Context
+		exp func(err error, calls []string)
+	}
+
+	record := func(calls *[]string, call string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			*calls = append(*calls, call)
+			return err
+		}
+	}
+
+	canceled := func() context.Context {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx
+	}
+
+	tcs := map[string]lifecycleTestCase{
+		"SUCCESS stops in reverse order on signal": {
+			reg: func(lc *Lifecycle, calls *[]string) {
+				lc.Register(Component{Name: "a", Start: record(calls, "start a", nil), Stop: record(calls, "stop a", nil)})
+				lc.Register(Component{Name: "b", Start: record(calls, "start b", nil), Stop: record(calls, "stop b", nil)})
+			},
+			ctx: canceled,
+			exp: func(err error, calls []string) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"start a", "start b", "stop b", "stop a"}, calls)
+			},
+		},
+		"SUCCESS cancels running components": {
+			reg: func(lc *Lifecycle, calls *[]string) {
+				lc.Register(Component{Name: "a", Stop: record(calls, "stop a", nil)})
+				lc.Register(Component{
+					Name: "worker",
+					Run: func(ctx context.Context) error {
+						<-ctx.Done()
+						*calls = append(*calls, "worker done")
+						return nil
+					},
+				})
+			},
+			ctx: canceled,
+			exp: func(err error, calls []string) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"worker done", "stop a"}, calls)
+			},
+		},
+		"FAILED to start a component": {
+			reg: func(lc *Lifecycle, calls *[]string) {
+				lc.Register(Component{Name: "a", Start: record(calls, "start a", nil), Stop: record(calls, "stop a", nil)})
+				lc.Register(Component{Name: "b", Start: record(calls, "start b", errors.New("")), Stop: record(calls, "stop b", nil)})
+				lc.Register(Component{Name: "c", Start: record(calls, "start c", nil), Stop: record(calls, "stop c", nil)})
+			},
+			ctx: context.Background,
+			exp: func(err error, calls []string) {
+				require.Error(t, err)
+				require.Equal(t, []string{"start a", "start b", "stop a"}, calls)
+			},
+		},
+		"FAILED running component": {
+			reg: func(lc *Lifecycle, calls *[]string) {
+				lc.Register(Component{Name: "a", Stop: record(calls, "stop a", nil)})
+				lc.Register(Component{Name: "server", Run: record(calls, "run server", errors.New(""))})
+			},
+			ctx: context.Background,
+			exp: func(err error, calls []string) {
+				require.Error(t, err)
+				require.Equal(t, []string{"run server", "stop a"}, calls)
+			},
+		},
+		"FAILED to stop a component": {
+			reg: func(lc *Lifecycle, calls *[]string) {
+				lc.Register(Component{Name: "a", Stop: record(calls, "stop a", nil)})
+				lc.Register(Component{Name: "b", Stop: record(calls, "stop b", errors.New(""))})
+			},
+			ctx: canceled,
+			exp: func(err error, calls []string) {
+				require.Error(t, err)
+				require.Equal(t, []string{"stop b", "stop a"}, calls)
+			},
+		},
+		"FAILED shutdown timeout": {
+			reg: func(lc *Lifecycle, calls *[]string) {
+				lc.Register(Component{
+					Name: "stuck",
+					Run: func(context.Context) error {
+						select {}
+					},
+				})
+			},
+			ctx: canceled,
+			exp: func(err error, _ []string) {
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+			lc := NewLifecycle(logger, 50*time.Millisecond)
+
+			var calls []string
+			tc.reg(lc, &calls)
+
+			err := lc.Run(tc.ctx())
+			tc.exp(err, calls)
+		})
+	}
+}
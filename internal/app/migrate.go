@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/internal/config"
+	"github.com/adanyl0v/pocket-ideas/migrations"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/database/migrate"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+)
+
+var ErrInvalidMigrateCommand = errors.New("invalid migrate command")
+
+const migrateUsage = "usage: app migrate up|down|status|goto N"
+
+// Migrate runs the "migrate" subcommand with the arguments that follow it
+func Migrate(args []string) {
+	cfg := config.MustReadFile(config.DefaultFilePath())
+	logger := mustSetupLogger(cfg.Env, &cfg.Log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	postgresDb := mustConnectToPostgres(logger, &cfg.PostgresConfig)
+	migrator := mustSetupMigrator(logger, postgresDb, &cfg.MigrateConfig)
+
+	err := runMigrateCommand(ctx, migrator, args, os.Stdout)
+	postgresDb.Close()
+
+	if err != nil {
+		if errors.Is(err, ErrInvalidMigrateCommand) {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+		}
+
+		logger.WithError(err).Error("failed to migrate")
+		os.Exit(1)
+	}
+}
+
+func runMigrateCommand(ctx context.Context, migrator *migrate.Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return ErrInvalidMigrateCommand
+	}
+
+	switch cmd, args := args[0], args[1:]; {
+	case cmd == "up" && len(args) == 0:
+		return migrator.Up(ctx)
+	case cmd == "down" && len(args) == 0:
+		return migrator.Down(ctx)
+	case cmd == "goto" && len(args) == 1:
+		version, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidMigrateCommand, err)
+		}
+
+		return migrator.Goto(ctx, version)
+	case cmd == "status" && len(args) == 0:
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		printMigrateStatus(out, &status)
+		return nil
+	default:
+		return ErrInvalidMigrateCommand
+	}
+}
+
+func printMigrateStatus(out io.Writer, status *migrate.Status) {
+	dirty := ""
+	if status.Dirty {
+		dirty = " (dirty)"
+	}
+	_, _ = fmt.Fprintf(out, "version: %d%s\n", status.Version, dirty)
+
+	for _, m := range status.Migrations {
+		mark := " "
+		if m.Applied {
+			mark = "x"
+		}
+		_, _ = fmt.Fprintf(out, "[%s] %d %s\n", mark, m.Version, m.Name)
+	}
+}
+
+// newSchemaCheck returns a start hook, which fails or only warns, depending
+// on the mode, if the database schema doesn't match the migrations
+func newSchemaCheck(logger log.Logger, migrator *migrate.Migrator, mode string) func(context.Context) error {
+	if mode != config.SchemaCheckFail && mode != config.SchemaCheckWarn {
+		panic(fmt.Errorf("invalid schema check mode: %s", mode))
+	}
+
+	return func(ctx context.Context) error {
+		err := migrator.Check(ctx)
+		if err == nil || !(errors.Is(err, migrate.ErrDirty) || errors.Is(err, migrate.ErrSchemaBehind)) {
+			return err
+		}
+
+		if mode == config.SchemaCheckWarn {
+			logger.WithError(err).Warn("the database schema doesn't match the migrations")
+			return nil
+		}
+
+		return err
+	}
+}
+
+func mustSetupMigrator(logger log.Logger, conn database.Conn, cfg *config.MigrateConfig) *migrate.Migrator {
+	migrator, err := migrate.New(conn, migrations.Postgres(), logger, &migrate.Config{
+		Table:  cfg.Table,
+		LockID: cfg.LockID,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return migrator
+}
@@ -15,17 +15,84 @@ const (
 	LogLevelError = "error"
 )
 
+const (
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+)
+
+const (
+	SchemaCheckFail = "fail"
+	SchemaCheckWarn = "warn"
+)
+
+const (
+	CacheDriverRedis  = "redis"
+	CacheDriverMemory = "memory"
+)
+
 type Config struct {
-	Env            string         `yaml:"env" env:"ENV" env-required:"true"`
-	Log            LogConfig      `yaml:"log"`
-	PostgresConfig PostgresConfig `yaml:"postgres"`
-	RedisConfig    RedisConfig    `yaml:"redis"`
+	Env             string         `yaml:"env" env:"ENV" env-required:"true"`
+	ShutdownTimeout time.Duration  `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" env-default:"15s"`
+	Log             LogConfig      `yaml:"log"`
+	HTTPConfig      HTTPConfig     `yaml:"http"`
+	PasswordConfig  PasswordConfig `yaml:"password"`
+	AuthConfig      AuthConfig     `yaml:"auth"`
+	JWTConfig       JWTConfig      `yaml:"jwt"`
+	PostgresConfig  PostgresConfig `yaml:"postgres"`
+	MigrateConfig   MigrateConfig  `yaml:"migrate"`
+	CacheConfig     CacheConfig    `yaml:"cache"`
+	RedisConfig     RedisConfig    `yaml:"redis"`
 }
 
 type LogConfig struct {
 	Level string `yaml:"level" env:"LOG_LEVEL" env-default:"warn"`
 }
 
+type HTTPConfig struct {
+	Address      string        `yaml:"address" env:"HTTP_ADDRESS" env-default:":8080"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" env-default:"5s"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" env-default:"10s"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" env-default:"60s"`
+}
+
+// PasswordConfig describes how new password hashes are computed. Hashes
+// computed with another algorithm or parameters are still verified,
+// but they are replaced on the next login
+type PasswordConfig struct {
+	Algorithm         string `yaml:"algorithm" env:"PASSWORD_ALGORITHM" env-default:"argon2id"`
+	Argon2Memory      uint32 `yaml:"argon2_memory" env:"PASSWORD_ARGON2_MEMORY" env-default:"65536"`
+	Argon2Iterations  uint32 `yaml:"argon2_iterations" env:"PASSWORD_ARGON2_ITERATIONS" env-default:"3"`
+	Argon2Parallelism uint8  `yaml:"argon2_parallelism" env:"PASSWORD_ARGON2_PARALLELISM" env-default:"2"`
+	BcryptCost        int    `yaml:"bcrypt_cost" env:"PASSWORD_BCRYPT_COST" env-default:"12"`
+}
+
+type AuthConfig struct {
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" env:"AUTH_ACCESS_TOKEN_TTL" env-default:"15m"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env:"AUTH_REFRESH_TOKEN_TTL" env-default:"720h"`
+
+	// MaxSessionsPerUser disables the limit if it is 0
+	MaxSessionsPerUser int `yaml:"max_sessions_per_user" env:"AUTH_MAX_SESSIONS_PER_USER" env-default:"10"`
+
+	// The logins are limited by the client ip and by the email, the refreshes
+	// by the client ip. The algorithms are described by ratelimit.Config
+	LoginRateLimitAlgorithm   string        `yaml:"login_rate_limit_algorithm" env:"AUTH_LOGIN_RATE_LIMIT_ALGORITHM" env-default:"sliding_window_log"`
+	LoginRateLimit            int64         `yaml:"login_rate_limit" env:"AUTH_LOGIN_RATE_LIMIT" env-default:"10"`
+	LoginRateLimitWindow      time.Duration `yaml:"login_rate_limit_window" env:"AUTH_LOGIN_RATE_LIMIT_WINDOW" env-default:"15m"`
+	RefreshRateLimitAlgorithm string        `yaml:"refresh_rate_limit_algorithm" env:"AUTH_REFRESH_RATE_LIMIT_ALGORITHM" env-default:"token_bucket"`
+	RefreshRateLimit          int64         `yaml:"refresh_rate_limit" env:"AUTH_REFRESH_RATE_LIMIT" env-default:"30"`
+	RefreshRateLimitWindow    time.Duration `yaml:"refresh_rate_limit_window" env:"AUTH_REFRESH_RATE_LIMIT_WINDOW" env-default:"1m"`
+}
+
+// JWTConfig describes how access tokens are signed and verified. The
+// layout of the keys directory is described by jwt.LoadKeySet
+type JWTConfig struct {
+	Issuer       string        `yaml:"issuer" env:"JWT_ISSUER" env-default:"pocket-ideas"`
+	Audience     []string      `yaml:"audience" env:"JWT_AUDIENCE" env-separator:"," env-default:"pocket-ideas"`
+	Leeway       time.Duration `yaml:"leeway" env:"JWT_LEEWAY" env-default:"30s"`
+	KeysDir      string        `yaml:"keys_dir" env:"JWT_KEYS_DIR" env-required:"true"`
+	SigningKeyID string        `yaml:"signing_key_id" env:"JWT_SIGNING_KEY_ID" env-required:"true"`
+}
+
 type PostgresConfig struct {
 	Host              string        `yaml:"host" env:"POSTGRES_HOST" env-required:"true"`
 	Port              int           `yaml:"port" env:"POSTGRES_PORT" env-required:"true"`
@@ -39,13 +106,61 @@ type PostgresConfig struct {
 	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" env:"POSTGRES_MAX_CONN_LIFETIME" env-default:"60m"`
 	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" env:"POSTGRES_MAX_CONN_IDLE_TIME" env-default:"30m"`
 	HealthCheckPeriod time.Duration `yaml:"health_check_period" env:"POSTGRES_HEALTH_CHECK_PERIOD" env-default:"1m"`
+
+	// StatementTimeout and IdleInTxSessionTimeout are enforced by the server,
+	// QueryTimeout cancels a single call on the client side. 0 disables them.
+	// The former two must be 0 with PgBouncer and set on the database role
+	StatementTimeout       time.Duration `yaml:"statement_timeout" env:"POSTGRES_STATEMENT_TIMEOUT" env-default:"30s"`
+	IdleInTxSessionTimeout time.Duration `yaml:"idle_in_transaction_session_timeout" env:"POSTGRES_IDLE_IN_TRANSACTION_SESSION_TIMEOUT" env-default:"1m"`
+	QueryTimeout           time.Duration `yaml:"query_timeout" env:"POSTGRES_QUERY_TIMEOUT" env-default:"10s"`
+
+	// QueryExecMode is one of cache_statement, cache_describe, describe_exec,
+	// exec and simple_protocol. Only the last two are allowed with PgBouncer,
+	// which is expected to run in the transaction pooling mode
+	QueryExecMode string `yaml:"query_exec_mode" env:"POSTGRES_QUERY_EXEC_MODE" env-default:"simple_protocol"`
+	PgBouncer     bool   `yaml:"pgbouncer" env:"POSTGRES_PGBOUNCER" env-default:"false"`
+
+	// Replicas receive the reads, which don't need to see the latest writes.
+	// They share the credentials and the pool settings with the primary
+	Replicas                 []PostgresReplicaConfig `yaml:"replicas"`
+	ReplicaHealthCheckPeriod time.Duration           `yaml:"replica_health_check_period" env:"POSTGRES_REPLICA_HEALTH_CHECK_PERIOD" env-default:"10s"`
+}
+
+type PostgresReplicaConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+// MigrateConfig describes how the embedded postgres migrations are applied.
+// The empty table and the zero lock id are replaced with the defaults
+// of the migrate package
+type MigrateConfig struct {
+	// AutoMigrate applies the pending migrations on start
+	AutoMigrate bool `yaml:"auto_migrate" env:"MIGRATE_AUTO_MIGRATE" env-default:"false"`
+
+	// SchemaCheck tells whether the application refuses to start or only
+	// warns, if the database schema is behind the migrations or dirty
+	SchemaCheck string `yaml:"schema_check" env:"MIGRATE_SCHEMA_CHECK" env-default:"fail"`
+	Table       string `yaml:"table" env:"MIGRATE_TABLE"`
+	LockID      int64  `yaml:"lock_id" env:"MIGRATE_LOCK_ID"`
+}
+
+// CacheConfig selects the store of the sessions, the locks and the rate
+// limits. The memory driver isn't shared by the instances of the application,
+// so it only fits a single instance, e.g. in the local environment
+type CacheConfig struct {
+	Driver string `yaml:"driver" env:"CACHE_DRIVER" env-default:"redis"`
+	// CleanupPeriod is only used by the memory driver
+	CleanupPeriod time.Duration `yaml:"cleanup_period" env:"CACHE_CLEANUP_PERIOD" env-default:"1m"`
 }
 
+// RedisConfig is only used by the redis cache driver,
+// so its address and credentials aren't required otherwise
 type RedisConfig struct {
-	Host            string        `yaml:"host" env:"REDIS_HOST" env-required:"true"`
-	Port            int           `yaml:"port" env:"REDIS_PORT" env-required:"true"`
-	User            string        `yaml:"user" env:"REDIS_USER" env-required:"true"`
-	Password        string        `yaml:"password" env:"REDIS_USER_PASSWORD" env-required:"true"`
+	Host            string        `yaml:"host" env:"REDIS_HOST"`
+	Port            int           `yaml:"port" env:"REDIS_PORT"`
+	User            string        `yaml:"user" env:"REDIS_USER"`
+	Password        string        `yaml:"password" env:"REDIS_USER_PASSWORD"`
 	Database        int           `yaml:"database" env:"REDIS_DATABASE" env-default:"0"`
 	DialTimeout     time.Duration `yaml:"dial_timeout" env:"REDIS_DIAL_TIMEOUT" env-default:"5s"`
 	ReadTimeout     time.Duration `yaml:"read_timeout" env:"REDIS_READ_TIMEOUT" env-default:"3s"`
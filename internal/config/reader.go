@@ -1,13 +1,17 @@
 package config
 
 import (
+	"errors"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
+	"io/fs"
 	"os"
 )
 
 func init() {
-	if err := godotenv.Load(); err != nil {
+	// The .env file is optional, because the variables
+	// may be already set by the environment
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		panic(err)
 	}
 }
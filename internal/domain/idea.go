@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+type Idea struct {
+	ID        string    `json:"id"`
+	User      User      `json:"user"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -3,7 +3,13 @@ package domain
 import "time"
 
 type Session struct {
-	ID           string      `json:"id"`
+	ID string `json:"id"`
+
+	// FamilyID identifies the refresh tokens issued since the login. It is
+	// shared by all the sessions created from the same login, so a reuse
+	// of any rotated refresh token revokes all of them
+	FamilyID string `json:"family_id"`
+
 	User         User        `json:"user"`
 	Fingerprint  Fingerprint `json:"fingerprint"`
 	RefreshToken string      `json:"refresh_token"`
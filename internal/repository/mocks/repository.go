@@ -0,0 +1,696 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/adanyl0v/pocket-ideas/internal/domain"
+	repository "github.com/adanyl0v/pocket-ideas/internal/repository"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTx is a mock of Tx interface.
+type MockTx struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxMockRecorder
+}
+
+// MockTxMockRecorder is the mock recorder for MockTx.
+type MockTxMockRecorder struct {
+	mock *MockTx
+}
+
+// NewMockTx creates a new mock instance.
+func NewMockTx(ctrl *gomock.Controller) *MockTx {
+	mock := &MockTx{ctrl: ctrl}
+	mock.recorder = &MockTxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTx) EXPECT() *MockTxMockRecorder {
+	return m.recorder
+}
+
+// Commit mocks base method.
+func (m *MockTx) Commit(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockTxMockRecorder) Commit(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockTx)(nil).Commit), ctx)
+}
+
+// Rollback mocks base method.
+func (m *MockTx) Rollback(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockTxMockRecorder) Rollback(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockTx)(nil).Rollback), ctx)
+}
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockRepository) Begin(ctx context.Context) (repository.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx)
+	ret0, _ := ret[0].(repository.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockRepositoryMockRecorder) Begin(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockRepository)(nil).Begin), ctx)
+}
+
+// WithTx mocks base method.
+func (m *MockRepository) WithTx(tx repository.Tx) repository.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(repository.Repository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRepositoryMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepository)(nil).WithTx), tx)
+}
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockUserRepository) Begin(ctx context.Context) (repository.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx)
+	ret0, _ := ret[0].(repository.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockUserRepositoryMockRecorder) Begin(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockUserRepository)(nil).Begin), ctx)
+}
+
+// DeleteById mocks base method.
+func (m *MockUserRepository) DeleteById(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteById", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteById indicates an expected call of DeleteById.
+func (mr *MockUserRepositoryMockRecorder) DeleteById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockUserRepository)(nil).DeleteById), ctx, id)
+}
+
+// FindAll mocks base method.
+func (m *MockUserRepository) FindAll(ctx context.Context) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockUserRepositoryMockRecorder) FindAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockUserRepository)(nil).FindAll), ctx)
+}
+
+// FindByEmail mocks base method.
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByEmail", ctx, email)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByEmail indicates an expected call of FindByEmail.
+func (mr *MockUserRepositoryMockRecorder) FindByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserRepository)(nil).FindByEmail), ctx, email)
+}
+
+// FindById mocks base method.
+func (m *MockUserRepository) FindById(ctx context.Context, id string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockUserRepositoryMockRecorder) FindById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockUserRepository)(nil).FindById), ctx, id)
+}
+
+// FindByName mocks base method.
+func (m *MockUserRepository) FindByName(ctx context.Context, name string) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByName", ctx, name)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByName indicates an expected call of FindByName.
+func (mr *MockUserRepositoryMockRecorder) FindByName(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockUserRepository)(nil).FindByName), ctx, name)
+}
+
+// Save mocks base method.
+func (m *MockUserRepository) Save(ctx context.Context, user *domain.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockUserRepositoryMockRecorder) Save(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUserRepository)(nil).Save), ctx, user)
+}
+
+// UpdateById mocks base method.
+func (m *MockUserRepository) UpdateById(ctx context.Context, user *domain.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateById", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateById indicates an expected call of UpdateById.
+func (mr *MockUserRepositoryMockRecorder) UpdateById(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateById", reflect.TypeOf((*MockUserRepository)(nil).UpdateById), ctx, user)
+}
+
+// WithTx mocks base method.
+func (m *MockUserRepository) WithTx(tx repository.Tx) repository.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(repository.Repository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockUserRepositoryMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockUserRepository)(nil).WithTx), tx)
+}
+
+// MockIdeaRepository is a mock of IdeaRepository interface.
+type MockIdeaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdeaRepositoryMockRecorder
+}
+
+// MockIdeaRepositoryMockRecorder is the mock recorder for MockIdeaRepository.
+type MockIdeaRepositoryMockRecorder struct {
+	mock *MockIdeaRepository
+}
+
+// NewMockIdeaRepository creates a new mock instance.
+func NewMockIdeaRepository(ctrl *gomock.Controller) *MockIdeaRepository {
+	mock := &MockIdeaRepository{ctrl: ctrl}
+	mock.recorder = &MockIdeaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdeaRepository) EXPECT() *MockIdeaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockIdeaRepository) Begin(ctx context.Context) (repository.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx)
+	ret0, _ := ret[0].(repository.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockIdeaRepositoryMockRecorder) Begin(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockIdeaRepository)(nil).Begin), ctx)
+}
+
+// DeleteById mocks base method.
+func (m *MockIdeaRepository) DeleteById(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteById", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteById indicates an expected call of DeleteById.
+func (mr *MockIdeaRepositoryMockRecorder) DeleteById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockIdeaRepository)(nil).DeleteById), ctx, id)
+}
+
+// DeleteByUserId mocks base method.
+func (m *MockIdeaRepository) DeleteByUserId(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUserId", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUserId indicates an expected call of DeleteByUserId.
+func (mr *MockIdeaRepositoryMockRecorder) DeleteByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUserId", reflect.TypeOf((*MockIdeaRepository)(nil).DeleteByUserId), ctx, userId)
+}
+
+// FindAll mocks base method.
+func (m *MockIdeaRepository) FindAll(ctx context.Context) ([]domain.Idea, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]domain.Idea)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockIdeaRepositoryMockRecorder) FindAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockIdeaRepository)(nil).FindAll), ctx)
+}
+
+// FindById mocks base method.
+func (m *MockIdeaRepository) FindById(ctx context.Context, id string) (domain.Idea, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(domain.Idea)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockIdeaRepositoryMockRecorder) FindById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockIdeaRepository)(nil).FindById), ctx, id)
+}
+
+// FindByUserId mocks base method.
+func (m *MockIdeaRepository) FindByUserId(ctx context.Context, userId string) ([]domain.Idea, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserId", ctx, userId)
+	ret0, _ := ret[0].([]domain.Idea)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserId indicates an expected call of FindByUserId.
+func (mr *MockIdeaRepositoryMockRecorder) FindByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserId", reflect.TypeOf((*MockIdeaRepository)(nil).FindByUserId), ctx, userId)
+}
+
+// Save mocks base method.
+func (m *MockIdeaRepository) Save(ctx context.Context, idea *domain.Idea) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, idea)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockIdeaRepositoryMockRecorder) Save(ctx, idea interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockIdeaRepository)(nil).Save), ctx, idea)
+}
+
+// UpdateById mocks base method.
+func (m *MockIdeaRepository) UpdateById(ctx context.Context, idea *domain.Idea) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateById", ctx, idea)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateById indicates an expected call of UpdateById.
+func (mr *MockIdeaRepositoryMockRecorder) UpdateById(ctx, idea interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateById", reflect.TypeOf((*MockIdeaRepository)(nil).UpdateById), ctx, idea)
+}
+
+// WithTx mocks base method.
+func (m *MockIdeaRepository) WithTx(tx repository.Tx) repository.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(repository.Repository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockIdeaRepositoryMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockIdeaRepository)(nil).WithTx), tx)
+}
+
+// MockAuthRepository is a mock of AuthRepository interface.
+type MockAuthRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthRepositoryMockRecorder
+}
+
+// MockAuthRepositoryMockRecorder is the mock recorder for MockAuthRepository.
+type MockAuthRepositoryMockRecorder struct {
+	mock *MockAuthRepository
+}
+
+// NewMockAuthRepository creates a new mock instance.
+func NewMockAuthRepository(ctrl *gomock.Controller) *MockAuthRepository {
+	mock := &MockAuthRepository{ctrl: ctrl}
+	mock.recorder = &MockAuthRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthRepository) EXPECT() *MockAuthRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteAccessTokenFromWhitelist mocks base method.
+func (m *MockAuthRepository) DeleteAccessTokenFromWhitelist(ctx context.Context, accessToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccessTokenFromWhitelist", ctx, accessToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccessTokenFromWhitelist indicates an expected call of DeleteAccessTokenFromWhitelist.
+func (mr *MockAuthRepositoryMockRecorder) DeleteAccessTokenFromWhitelist(ctx, accessToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessTokenFromWhitelist", reflect.TypeOf((*MockAuthRepository)(nil).DeleteAccessTokenFromWhitelist), ctx, accessToken)
+}
+
+// DeleteRefreshTokenFromBlacklist mocks base method.
+func (m *MockAuthRepository) DeleteRefreshTokenFromBlacklist(ctx context.Context, refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRefreshTokenFromBlacklist", ctx, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRefreshTokenFromBlacklist indicates an expected call of DeleteRefreshTokenFromBlacklist.
+func (mr *MockAuthRepositoryMockRecorder) DeleteRefreshTokenFromBlacklist(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRefreshTokenFromBlacklist", reflect.TypeOf((*MockAuthRepository)(nil).DeleteRefreshTokenFromBlacklist), ctx, refreshToken)
+}
+
+// DeleteSessionAccessTokensFromWhitelist mocks base method.
+func (m *MockAuthRepository) DeleteSessionAccessTokensFromWhitelist(ctx context.Context, sessionId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSessionAccessTokensFromWhitelist", ctx, sessionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSessionAccessTokensFromWhitelist indicates an expected call of DeleteSessionAccessTokensFromWhitelist.
+func (mr *MockAuthRepositoryMockRecorder) DeleteSessionAccessTokensFromWhitelist(ctx, sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessionAccessTokensFromWhitelist", reflect.TypeOf((*MockAuthRepository)(nil).DeleteSessionAccessTokensFromWhitelist), ctx, sessionId)
+}
+
+// DeleteSessionById mocks base method.
+func (m *MockAuthRepository) DeleteSessionById(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSessionById", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSessionById indicates an expected call of DeleteSessionById.
+func (mr *MockAuthRepositoryMockRecorder) DeleteSessionById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessionById", reflect.TypeOf((*MockAuthRepository)(nil).DeleteSessionById), ctx, id)
+}
+
+// FindAccessTokenInWhitelist mocks base method.
+func (m *MockAuthRepository) FindAccessTokenInWhitelist(ctx context.Context, accessToken string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAccessTokenInWhitelist", ctx, accessToken)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAccessTokenInWhitelist indicates an expected call of FindAccessTokenInWhitelist.
+func (mr *MockAuthRepositoryMockRecorder) FindAccessTokenInWhitelist(ctx, accessToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAccessTokenInWhitelist", reflect.TypeOf((*MockAuthRepository)(nil).FindAccessTokenInWhitelist), ctx, accessToken)
+}
+
+// FindAllSessions mocks base method.
+func (m *MockAuthRepository) FindAllSessions(ctx context.Context) ([]domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllSessions", ctx)
+	ret0, _ := ret[0].([]domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllSessions indicates an expected call of FindAllSessions.
+func (mr *MockAuthRepositoryMockRecorder) FindAllSessions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllSessions", reflect.TypeOf((*MockAuthRepository)(nil).FindAllSessions), ctx)
+}
+
+// FindRefreshTokenFamilyInBlacklist mocks base method.
+func (m *MockAuthRepository) FindRefreshTokenFamilyInBlacklist(ctx context.Context, refreshToken string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRefreshTokenFamilyInBlacklist", ctx, refreshToken)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRefreshTokenFamilyInBlacklist indicates an expected call of FindRefreshTokenFamilyInBlacklist.
+func (mr *MockAuthRepositoryMockRecorder) FindRefreshTokenFamilyInBlacklist(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRefreshTokenFamilyInBlacklist", reflect.TypeOf((*MockAuthRepository)(nil).FindRefreshTokenFamilyInBlacklist), ctx, refreshToken)
+}
+
+// FindRefreshTokenInBlacklist mocks base method.
+func (m *MockAuthRepository) FindRefreshTokenInBlacklist(ctx context.Context, refreshToken string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRefreshTokenInBlacklist", ctx, refreshToken)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRefreshTokenInBlacklist indicates an expected call of FindRefreshTokenInBlacklist.
+func (mr *MockAuthRepositoryMockRecorder) FindRefreshTokenInBlacklist(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRefreshTokenInBlacklist", reflect.TypeOf((*MockAuthRepository)(nil).FindRefreshTokenInBlacklist), ctx, refreshToken)
+}
+
+// FindSessionByFingerprint mocks base method.
+func (m *MockAuthRepository) FindSessionByFingerprint(ctx context.Context, userId string, fp domain.Fingerprint) (domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSessionByFingerprint", ctx, userId, fp)
+	ret0, _ := ret[0].(domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSessionByFingerprint indicates an expected call of FindSessionByFingerprint.
+func (mr *MockAuthRepositoryMockRecorder) FindSessionByFingerprint(ctx, userId, fp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSessionByFingerprint", reflect.TypeOf((*MockAuthRepository)(nil).FindSessionByFingerprint), ctx, userId, fp)
+}
+
+// FindSessionById mocks base method.
+func (m *MockAuthRepository) FindSessionById(ctx context.Context, id string) (domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSessionById", ctx, id)
+	ret0, _ := ret[0].(domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSessionById indicates an expected call of FindSessionById.
+func (mr *MockAuthRepositoryMockRecorder) FindSessionById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSessionById", reflect.TypeOf((*MockAuthRepository)(nil).FindSessionById), ctx, id)
+}
+
+// FindSessionByRefreshToken mocks base method.
+func (m *MockAuthRepository) FindSessionByRefreshToken(ctx context.Context, refreshToken string) (domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSessionByRefreshToken", ctx, refreshToken)
+	ret0, _ := ret[0].(domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSessionByRefreshToken indicates an expected call of FindSessionByRefreshToken.
+func (mr *MockAuthRepositoryMockRecorder) FindSessionByRefreshToken(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSessionByRefreshToken", reflect.TypeOf((*MockAuthRepository)(nil).FindSessionByRefreshToken), ctx, refreshToken)
+}
+
+// FindSessionsByFamilyId mocks base method.
+func (m *MockAuthRepository) FindSessionsByFamilyId(ctx context.Context, familyId string) ([]domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSessionsByFamilyId", ctx, familyId)
+	ret0, _ := ret[0].([]domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSessionsByFamilyId indicates an expected call of FindSessionsByFamilyId.
+func (mr *MockAuthRepositoryMockRecorder) FindSessionsByFamilyId(ctx, familyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSessionsByFamilyId", reflect.TypeOf((*MockAuthRepository)(nil).FindSessionsByFamilyId), ctx, familyId)
+}
+
+// FindSessionsByUserId mocks base method.
+func (m *MockAuthRepository) FindSessionsByUserId(ctx context.Context, userId string) ([]domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSessionsByUserId", ctx, userId)
+	ret0, _ := ret[0].([]domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSessionsByUserId indicates an expected call of FindSessionsByUserId.
+func (mr *MockAuthRepositoryMockRecorder) FindSessionsByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSessionsByUserId", reflect.TypeOf((*MockAuthRepository)(nil).FindSessionsByUserId), ctx, userId)
+}
+
+// SaveAccessTokenToWhitelist mocks base method.
+func (m *MockAuthRepository) SaveAccessTokenToWhitelist(ctx context.Context, sessionId, accessToken string, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAccessTokenToWhitelist", ctx, sessionId, accessToken, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAccessTokenToWhitelist indicates an expected call of SaveAccessTokenToWhitelist.
+func (mr *MockAuthRepositoryMockRecorder) SaveAccessTokenToWhitelist(ctx, sessionId, accessToken, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAccessTokenToWhitelist", reflect.TypeOf((*MockAuthRepository)(nil).SaveAccessTokenToWhitelist), ctx, sessionId, accessToken, expiration)
+}
+
+// SaveRefreshTokenToBlacklist mocks base method.
+func (m *MockAuthRepository) SaveRefreshTokenToBlacklist(ctx context.Context, refreshToken, familyId string, expiration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRefreshTokenToBlacklist", ctx, refreshToken, familyId, expiration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveRefreshTokenToBlacklist indicates an expected call of SaveRefreshTokenToBlacklist.
+func (mr *MockAuthRepositoryMockRecorder) SaveRefreshTokenToBlacklist(ctx, refreshToken, familyId, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRefreshTokenToBlacklist", reflect.TypeOf((*MockAuthRepository)(nil).SaveRefreshTokenToBlacklist), ctx, refreshToken, familyId, expiration)
+}
+
+// SaveSession mocks base method.
+func (m *MockAuthRepository) SaveSession(ctx context.Context, session *domain.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSession", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSession indicates an expected call of SaveSession.
+func (mr *MockAuthRepositoryMockRecorder) SaveSession(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSession", reflect.TypeOf((*MockAuthRepository)(nil).SaveSession), ctx, session)
+}
+
+// UpdateSessionById mocks base method.
+func (m *MockAuthRepository) UpdateSessionById(ctx context.Context, session *domain.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSessionById", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSessionById indicates an expected call of UpdateSessionById.
+func (mr *MockAuthRepositoryMockRecorder) UpdateSessionById(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSessionById", reflect.TypeOf((*MockAuthRepository)(nil).UpdateSessionById), ctx, session)
+}
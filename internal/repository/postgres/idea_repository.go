@@ -0,0 +1,289 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"github.com/adanyl0v/pocket-ideas/internal/repository"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"github.com/adanyl0v/pocket-ideas/pkg/uuid"
+	"time"
+)
+
+var (
+	ErrIdeaNotFound            = errors.New("idea not found")
+	ErrIdeaOwnerNotFound       = errors.New("idea owner not found")
+	ErrIdeaFieldMustNotBeEmpty = errors.New("idea field must not be empty")
+)
+
+type IdeaRepository struct {
+	Repository
+	idGen uuid.Generator
+}
+
+func NewIdeaRepository(conn database.Conn, logger log.Logger, idGen uuid.Generator) *IdeaRepository {
+	return &IdeaRepository{
+		Repository: Repository{
+			conn:   conn,
+			logger: logger,
+		},
+		idGen: idGen,
+	}
+}
+
+func (r *IdeaRepository) WithTx(tx repository.Tx) repository.Repository {
+	conn, ok := tx.(database.Tx)
+	if tx == nil || conn == nil || !ok {
+		r.logger.With(log.Fields{"tx": tx}).Error("failed to cast the transaction")
+		return nil
+	}
+
+	return NewIdeaRepository(conn, r.logger, r.idGen)
+}
+
+const qInsertIdea = `
+INSERT INTO ideas (id, user_id, title, body, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+func (r *IdeaRepository) Save(ctx context.Context, idea *domain.Idea) error {
+	dto := newSaveIdeaDto(idea)
+
+	var err error
+	dto.ID, err = r.idGen.NewV7()
+	if err != nil {
+		r.logger.WithError(err).Error("failed to generate idea uuid")
+		return err
+	}
+
+	dto.CreatedAt = time.Now()
+	dto.UpdatedAt = dto.CreatedAt
+	_, err = r.db(ctx).Execute(ctx, qInsertIdea, dto.ID, dto.UserID, dto.Title, dto.Body, dto.CreatedAt, dto.UpdatedAt)
+	if err != nil {
+		var pxErr proxerr.Error
+		if errors.As(err, &pxErr) {
+			e := pxErr.Unwrap()
+			switch {
+			case errors.Is(e, database.ErrForeignKeyViolation):
+				err = proxerr.New(ErrIdeaOwnerNotFound, pxErr.Error())
+			case errors.Is(e, database.ErrNotNullViolation):
+				err = proxerr.New(ErrIdeaFieldMustNotBeEmpty, pxErr.Error())
+			}
+		}
+
+		r.logger.WithError(err).Error("failed to save an idea")
+		return err
+	}
+
+	dto.ToDomain(idea)
+	r.logger.With(log.Fields{
+		"id":      dto.ID,
+		"user_id": dto.UserID,
+	}).Debug("saved an idea")
+	return nil
+}
+
+const qFindIdeaById = `
+SELECT user_id, title, body, created_at, updated_at
+FROM ideas WHERE id = $1
+`
+
+func (r *IdeaRepository) FindById(ctx context.Context, id string) (domain.Idea, error) {
+	logger := r.logger.With(log.Fields{"id": id})
+
+	idea := domain.Idea{ID: id}
+	dto := newFindIdeaByIdDto()
+
+	if err := r.db(ctx).QueryRow(ctx, qFindIdeaById, id).Scan(&dto.UserID,
+		&dto.Title, &dto.Body, &dto.CreatedAt, &dto.UpdatedAt); err != nil {
+
+		var pxErr proxerr.Error
+		if errors.As(err, &pxErr) && errors.Is(pxErr.Unwrap(), database.ErrNoRows) {
+			err = proxerr.New(ErrIdeaNotFound, pxErr.Error())
+		}
+
+		logger.WithError(err).Error("failed to find an idea by id")
+		return domain.Idea{}, err
+	}
+
+	dto.ToDomain(&idea)
+	logger.Debug("found an idea by id")
+	return idea, nil
+}
+
+const qFindAllIdeas = `
+SELECT id, user_id, title, body, created_at, updated_at
+FROM ideas
+`
+
+func (r *IdeaRepository) FindAll(ctx context.Context) ([]domain.Idea, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			r.logger.WithError(err).Error("failed to find all ideas")
+		}
+	}()
+
+	ideas := make([]domain.Idea, 0)
+	rows, err := r.db(ctx).Query(ctx, qFindAllIdeas)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		dto := newFindAllIdeasDto()
+		if err = rows.Scan(&dto.ID, &dto.UserID, &dto.Title, &dto.Body, &dto.CreatedAt, &dto.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		var idea domain.Idea
+		dto.ToDomain(&idea)
+		ideas = append(ideas, idea)
+	}
+
+	if err = rows.Err(); err != nil {
+		var pxErr proxerr.Error
+		if errors.As(err, &pxErr) && errors.Is(pxErr.Unwrap(), database.ErrNoRows) {
+			err = proxerr.New(ErrIdeaNotFound, pxErr.Error())
+		}
+
+		return nil, err
+	}
+
+	r.logger.Debug(fmt.Sprintf("found %d ideas", len(ideas)))
+	return ideas, nil
+}
+
+const qFindIdeasByUserId = `
+SELECT id, title, body, created_at, updated_at
+FROM ideas WHERE user_id = $1
+`
+
+func (r *IdeaRepository) FindByUserId(ctx context.Context, userId string) ([]domain.Idea, error) {
+	logger := r.logger.With(log.Fields{"user_id": userId})
+
+	var err error
+	defer func() {
+		if err != nil {
+			logger.WithError(err).Error("failed to find ideas by user id")
+		}
+	}()
+
+	ideas := make([]domain.Idea, 0)
+	rows, err := r.db(ctx).Query(ctx, qFindIdeasByUserId, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		dto := newFindIdeasByUserIdDto()
+		if err = rows.Scan(&dto.ID, &dto.Title, &dto.Body, &dto.CreatedAt, &dto.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		idea := domain.Idea{User: domain.User{ID: userId}}
+		dto.ToDomain(&idea)
+		ideas = append(ideas, idea)
+	}
+
+	if err = rows.Err(); err != nil {
+		var pxErr proxerr.Error
+		if errors.As(err, &pxErr) && errors.Is(pxErr.Unwrap(), database.ErrNoRows) {
+			err = proxerr.New(ErrIdeaNotFound, pxErr.Error())
+		}
+
+		return nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("found %d ideas by user id", len(ideas)))
+	return ideas, nil
+}
+
+const qUpdateIdeaById = `
+UPDATE ideas SET title = $1, body = $2, updated_at = $3
+WHERE id = $4
+`
+
+func (r *IdeaRepository) UpdateById(ctx context.Context, idea *domain.Idea) error {
+	logger := r.logger.With(log.Fields{"id": idea.ID})
+
+	var err error
+	defer func() {
+		if err != nil {
+			logger.WithError(err).Error("failed to update idea by id")
+		}
+	}()
+
+	dto := newUpdateIdeaByIdDto(idea)
+	dto.UpdatedAt = time.Now()
+	res, err := r.db(ctx).Execute(ctx, qUpdateIdeaById, dto.Title, dto.Body, dto.UpdatedAt, dto.ID)
+	if err != nil {
+		var pxErr proxerr.Error
+		if errors.As(err, &pxErr) && errors.Is(pxErr.Unwrap(), database.ErrNotNullViolation) {
+			err = proxerr.New(ErrIdeaFieldMustNotBeEmpty, pxErr.Error())
+		}
+
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		err = ErrIdeaNotFound
+		return err
+	}
+
+	dto.ToDomain(idea)
+	logger.Debug("updated idea by id")
+	return nil
+}
+
+const qDeleteIdeaById = `
+DELETE FROM ideas WHERE id = $1
+`
+
+func (r *IdeaRepository) DeleteById(ctx context.Context, id string) error {
+	logger := r.logger.With(log.Fields{"id": id})
+
+	var err error
+	defer func() {
+		if err != nil {
+			logger.WithError(err).Error("failed to delete idea by id")
+		}
+	}()
+
+	dto := newDeleteIdeaByIdDto(id)
+	res, err := r.db(ctx).Execute(ctx, qDeleteIdeaById, dto.ID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		err = ErrIdeaNotFound
+		return err
+	}
+
+	logger.Debug("deleted idea by id")
+	return nil
+}
+
+const qDeleteIdeasByUserId = `
+DELETE FROM ideas WHERE user_id = $1
+`
+
+// DeleteByUserId doesn't fail, if the user has no ideas
+func (r *IdeaRepository) DeleteByUserId(ctx context.Context, userId string) error {
+	logger := r.logger.With(log.Fields{"user_id": userId})
+
+	res, err := r.db(ctx).Execute(ctx, qDeleteIdeasByUserId, userId)
+	if err != nil {
+		logger.WithError(err).Error("failed to delete ideas by user id")
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("deleted %d ideas by user id", res.RowsAffected()))
+	return nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype/zeronull"
+	"time"
+)
+
+type saveIdeaDto struct {
+	ID        string        `json:"id" db:"id"`
+	UserID    string        `json:"user_id" db:"user_id"`
+	Title     zeronull.Text `json:"title" db:"title"`
+	Body      zeronull.Text `json:"body" db:"body"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+func newSaveIdeaDto(i *domain.Idea) saveIdeaDto {
+	return saveIdeaDto{
+		UserID: i.User.ID,
+		Title:  zeronull.Text(i.Title),
+		Body:   zeronull.Text(i.Body),
+	}
+}
+
+func (d *saveIdeaDto) ToDomain(i *domain.Idea) {
+	i.ID = d.ID
+	i.CreatedAt = d.CreatedAt
+	i.UpdatedAt = d.UpdatedAt
+}
+
+type findIdeaByIdDto struct {
+	ID        zeronull.UUID      `json:"id" db:"id"`
+	UserID    zeronull.UUID      `json:"user_id" db:"user_id"`
+	Title     zeronull.Text      `json:"title" db:"title"`
+	Body      zeronull.Text      `json:"body" db:"body"`
+	CreatedAt zeronull.Timestamp `json:"created_at" db:"created_at"`
+	UpdatedAt zeronull.Timestamp `json:"updated_at" db:"updated_at"`
+}
+
+func newFindIdeaByIdDto() findIdeaByIdDto {
+	return findIdeaByIdDto{}
+}
+
+func (d *findIdeaByIdDto) ToDomain(i *domain.Idea) {
+	userId, _ := d.UserID.Value()
+	title, _ := d.Title.Value()
+	body, _ := d.Body.Value()
+	createdAt, _ := d.CreatedAt.Value()
+	updatedAt, _ := d.UpdatedAt.Value()
+
+	i.User.ID, _ = userId.(string)
+	i.Title, _ = title.(string)
+	i.Body, _ = body.(string)
+	i.CreatedAt, _ = createdAt.(time.Time)
+	i.UpdatedAt, _ = updatedAt.(time.Time)
+}
+
+type findAllIdeasDto findIdeaByIdDto
+
+func newFindAllIdeasDto() findAllIdeasDto {
+	return findAllIdeasDto{}
+}
+
+func (d *findAllIdeasDto) ToDomain(i *domain.Idea) {
+	id, _ := d.ID.Value()
+	userId, _ := d.UserID.Value()
+	title, _ := d.Title.Value()
+	body, _ := d.Body.Value()
+	createdAt, _ := d.CreatedAt.Value()
+	updatedAt, _ := d.UpdatedAt.Value()
+
+	i.ID, _ = id.(string)
+	i.User.ID, _ = userId.(string)
+	i.Title, _ = title.(string)
+	i.Body, _ = body.(string)
+	i.CreatedAt, _ = createdAt.(time.Time)
+	i.UpdatedAt, _ = updatedAt.(time.Time)
+}
+
+type findIdeasByUserIdDto findAllIdeasDto
+
+func newFindIdeasByUserIdDto() findIdeasByUserIdDto {
+	return findIdeasByUserIdDto{}
+}
+
+func (d *findIdeasByUserIdDto) ToDomain(i *domain.Idea) {
+	id, _ := d.ID.Value()
+	title, _ := d.Title.Value()
+	body, _ := d.Body.Value()
+	createdAt, _ := d.CreatedAt.Value()
+	updatedAt, _ := d.UpdatedAt.Value()
+
+	i.ID, _ = id.(string)
+	i.Title, _ = title.(string)
+	i.Body, _ = body.(string)
+	i.CreatedAt, _ = createdAt.(time.Time)
+	i.UpdatedAt, _ = updatedAt.(time.Time)
+}
+
+type updateIdeaByIdDto struct {
+	ID        string        `json:"id" db:"id"`
+	Title     zeronull.Text `json:"title" db:"title"`
+	Body      zeronull.Text `json:"body" db:"body"`
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+func newUpdateIdeaByIdDto(i *domain.Idea) updateIdeaByIdDto {
+	return updateIdeaByIdDto{
+		ID:    i.ID,
+		Title: zeronull.Text(i.Title),
+		Body:  zeronull.Text(i.Body),
+	}
+}
+
+func (d *updateIdeaByIdDto) ToDomain(i *domain.Idea) {
+	i.UpdatedAt = d.UpdatedAt
+}
+
+type deleteIdeaByIdDto struct {
+	ID string `json:"id" db:"id"`
+}
+
+func newDeleteIdeaByIdDto(id string) deleteIdeaByIdDto {
+	return deleteIdeaByIdDto{ID: id}
+}
@@ -0,0 +1,539 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	_dbMock "github.com/adanyl0v/pocket-ideas/mocks/pkg/database"
+	_uuidMock "github.com/adanyl0v/pocket-ideas/mocks/pkg/uuid"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"github.com/golang/mock/gomock"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	"testing"
+)
+
+type (
+	ideaTestCaseRegister func(_ *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator)
+	ideaTestCaseCommand  func(repo *IdeaRepository) error
+	ideaTestCaseExpect   func(err error)
+
+	ideaTestCase struct {
+		reg ideaTestCaseRegister
+		cmd ideaTestCaseCommand
+		exp ideaTestCaseExpect
+	}
+)
+
+func TestIdeaRepository_WithTx(t *testing.T) {
+	tcs := map[string]ideaTestCase{
+		"SUCCESS": {
+			cmd: func(repo *IdeaRepository) error {
+				r := repo.WithTx(new(_dbMock.MockTx))
+				require.NotNil(t, r)
+				return nil
+			},
+		},
+		"FAILED": {
+			cmd: func(repo *IdeaRepository) error {
+				r := repo.WithTx(nil)
+				require.Nil(t, r)
+				return nil
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaTestCase(t, &tc)
+		})
+	}
+}
+
+func TestIdeaRepository_Save(t *testing.T) {
+	tcs := map[string]ideaTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, conn *_dbMock.MockConn, idGen *_uuidMock.MockGenerator) {
+				idGen.EXPECT().NewV7().Times(1).Return("", nil)
+				conn.EXPECT().Execute(gomock.Any(), qInsertIdea, gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.Save(context.Background(), new(domain.Idea))
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED id generation": {
+			reg: func(_ *gomock.Controller, _ *_dbMock.MockConn, idGen *_uuidMock.MockGenerator) {
+				idGen.EXPECT().NewV7().Times(1).Return("", errors.New(""))
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.Save(context.Background(), new(domain.Idea))
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"FAILED idea owner not found": {
+			reg: func(_ *gomock.Controller, conn *_dbMock.MockConn, idGen *_uuidMock.MockGenerator) {
+				idGen.EXPECT().NewV7().Times(1).Return("", nil)
+				conn.EXPECT().Execute(gomock.Any(), qInsertIdea, gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil,
+					proxerr.New(database.ErrForeignKeyViolation, ""))
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.Save(context.Background(), new(domain.Idea))
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrIdeaOwnerNotFound, err)
+			},
+		},
+		"FAILED idea field must not be empty": {
+			reg: func(_ *gomock.Controller, conn *_dbMock.MockConn, idGen *_uuidMock.MockGenerator) {
+				idGen.EXPECT().NewV7().Times(1).Return("", nil)
+				conn.EXPECT().Execute(gomock.Any(), qInsertIdea, gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil,
+					proxerr.New(database.ErrNotNullViolation, ""))
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.Save(context.Background(), new(domain.Idea))
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrIdeaFieldMustNotBeEmpty, err)
+			},
+		},
+		"FAILED": {
+			reg: func(_ *gomock.Controller, conn *_dbMock.MockConn, idGen *_uuidMock.MockGenerator) {
+				idGen.EXPECT().NewV7().Times(1).Return("", nil)
+				conn.EXPECT().Execute(gomock.Any(), qInsertIdea, gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New(""))
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.Save(context.Background(), new(domain.Idea))
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaTestCase(t, &tc)
+		})
+	}
+}
+
+func TestIdeaRepository_FindById(t *testing.T) {
+	const id = "0194f7a2-1c4e-7d31-a0a4-62f0b5c8d9e1"
+	tcs := map[string]ideaTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				row := _dbMock.NewMockRow(ctrl)
+				row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any()).Times(1).Return(nil)
+
+				conn.EXPECT().QueryRow(gomock.Any(), qFindIdeaById, id).Times(1).Return(row)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				_, err := repo.FindById(context.Background(), id)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED idea not found": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				row := _dbMock.NewMockRow(ctrl)
+				row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any()).Times(1).Return(proxerr.New(database.ErrNoRows, ""))
+
+				conn.EXPECT().QueryRow(gomock.Any(), qFindIdeaById, id).Times(1).Return(row)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				_, err := repo.FindById(context.Background(), id)
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrIdeaNotFound, err)
+			},
+		},
+		"FAILED": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				row := _dbMock.NewMockRow(ctrl)
+				row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any()).Times(1).Return(errors.New(""))
+
+				conn.EXPECT().QueryRow(gomock.Any(), qFindIdeaById, id).Times(1).Return(row)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				_, err := repo.FindById(context.Background(), id)
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaTestCase(t, &tc)
+		})
+	}
+}
+
+func TestIdeaRepository_FindAll(t *testing.T) {
+	tcs := map[string]ideaTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				rows := _dbMock.NewMockRows(ctrl)
+				rows.EXPECT().Close().Times(1)
+				rows.EXPECT().Err().Return(nil).Times(1)
+
+				var n, i = 5, 0
+				rows.EXPECT().Next().Times(n).DoAndReturn(func() bool {
+					i++
+					return i < n
+				})
+				rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(n - 1).Return(nil)
+
+				conn.EXPECT().Query(gomock.Any(), qFindAllIdeas).Times(1).Return(rows, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				_, err := repo.FindAll(context.Background())
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED query execution": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().Query(gomock.Any(), qFindAllIdeas).Times(1).Return(nil, errors.New(""))
+			},
+			cmd: func(repo *IdeaRepository) error {
+				_, err := repo.FindAll(context.Background())
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"FAILED model scan": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				rows := _dbMock.NewMockRows(ctrl)
+				rows.EXPECT().Close().Times(1)
+				rows.EXPECT().Next().Times(1).Return(true)
+				rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(errors.New(""))
+
+				conn.EXPECT().Query(gomock.Any(), qFindAllIdeas).Times(1).Return(rows, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				_, err := repo.FindAll(context.Background())
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"FAILED": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				rows := _dbMock.NewMockRows(ctrl)
+				rows.EXPECT().Close().Times(1)
+				rows.EXPECT().Next().Times(1).Return(false)
+				rows.EXPECT().Err().Return(errors.New("")).Times(1)
+
+				conn.EXPECT().Query(gomock.Any(), qFindAllIdeas).Times(1).Return(rows, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				_, err := repo.FindAll(context.Background())
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaTestCase(t, &tc)
+		})
+	}
+}
+
+func TestIdeaRepository_FindByUserId(t *testing.T) {
+	const userId = "0194f7a3-88b0-7c52-9e3f-0d2a61b7f4c5"
+	tcs := map[string]ideaTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				rows := _dbMock.NewMockRows(ctrl)
+				rows.EXPECT().Close().Times(1)
+				rows.EXPECT().Err().Return(nil).Times(1)
+
+				var n, i = 5, 0
+				rows.EXPECT().Next().Times(n).DoAndReturn(func() bool {
+					i++
+					return i < n
+				})
+				rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(n - 1).Return(nil)
+
+				conn.EXPECT().Query(gomock.Any(), qFindIdeasByUserId, userId).Times(1).Return(rows, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				_, err := repo.FindByUserId(context.Background(), userId)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED query execution": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().Query(gomock.Any(), qFindIdeasByUserId, userId).Times(1).Return(nil, errors.New(""))
+			},
+			cmd: func(repo *IdeaRepository) error {
+				_, err := repo.FindByUserId(context.Background(), userId)
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"FAILED model scan": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				rows := _dbMock.NewMockRows(ctrl)
+				rows.EXPECT().Close().Times(1)
+				rows.EXPECT().Next().Times(1).Return(true)
+				rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(errors.New(""))
+
+				conn.EXPECT().Query(gomock.Any(), qFindIdeasByUserId, userId).Times(1).Return(rows, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				_, err := repo.FindByUserId(context.Background(), userId)
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaTestCase(t, &tc)
+		})
+	}
+}
+
+func TestIdeaRepository_UpdateById(t *testing.T) {
+	const id = "0194f7a4-0b7d-7f10-8c6e-3a9d21e5b0f8"
+	tcs := map[string]ideaTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				result := _dbMock.NewMockResult(ctrl)
+				result.EXPECT().RowsAffected().Times(1).Return(int64(1))
+
+				conn.EXPECT().Execute(gomock.Any(), qUpdateIdeaById, gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any()).Return(result, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.UpdateById(context.Background(), &domain.Idea{ID: id})
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED idea field must not be empty": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().Execute(gomock.Any(), qUpdateIdeaById, gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any()).Return(nil, proxerr.New(database.ErrNotNullViolation, ""))
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.UpdateById(context.Background(), &domain.Idea{ID: id})
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrIdeaFieldMustNotBeEmpty, err)
+			},
+		},
+		"FAILED idea not found": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				result := _dbMock.NewMockResult(ctrl)
+				result.EXPECT().RowsAffected().Times(1).Return(int64(0))
+
+				conn.EXPECT().Execute(gomock.Any(), qUpdateIdeaById, gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any()).Return(result, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.UpdateById(context.Background(), &domain.Idea{ID: id})
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrIdeaNotFound, err)
+			},
+		},
+		"FAILED": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().Execute(gomock.Any(), qUpdateIdeaById, gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any()).Return(nil, errors.New(""))
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.UpdateById(context.Background(), &domain.Idea{ID: id})
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaTestCase(t, &tc)
+		})
+	}
+}
+
+func TestIdeaRepository_DeleteById(t *testing.T) {
+	const id = "0194f7a4-d215-7a83-b6c0-5e8f43a2d716"
+	tcs := map[string]ideaTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				result := _dbMock.NewMockResult(ctrl)
+				result.EXPECT().RowsAffected().Times(1).Return(int64(1))
+
+				conn.EXPECT().Execute(gomock.Any(), qDeleteIdeaById, id).Times(1).Return(result, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.DeleteById(context.Background(), id)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED idea not found": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				result := _dbMock.NewMockResult(ctrl)
+				result.EXPECT().RowsAffected().Times(1).Return(int64(0))
+
+				conn.EXPECT().Execute(gomock.Any(), qDeleteIdeaById, id).Times(1).Return(result, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.DeleteById(context.Background(), id)
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrIdeaNotFound, err)
+			},
+		},
+		"FAILED": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().Execute(gomock.Any(), qDeleteIdeaById, id).Times(1).
+					Return(nil, errors.New(""))
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.DeleteById(context.Background(), id)
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaTestCase(t, &tc)
+		})
+	}
+}
+
+func TestIdeaRepository_DeleteByUserId(t *testing.T) {
+	const userId = "0194f7a4-d215-7a83-b6c0-5e8f43a2d716"
+	tcs := map[string]ideaTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				result := _dbMock.NewMockResult(ctrl)
+				result.EXPECT().RowsAffected().Times(1).Return(int64(2))
+
+				conn.EXPECT().Execute(gomock.Any(), qDeleteIdeasByUserId, userId).Times(1).Return(result, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.DeleteByUserId(context.Background(), userId)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS no ideas": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				result := _dbMock.NewMockResult(ctrl)
+				result.EXPECT().RowsAffected().Times(1).Return(int64(0))
+
+				conn.EXPECT().Execute(gomock.Any(), qDeleteIdeasByUserId, userId).Times(1).Return(result, nil)
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.DeleteByUserId(context.Background(), userId)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().Execute(gomock.Any(), qDeleteIdeasByUserId, userId).Times(1).
+					Return(nil, errors.New(""))
+			},
+			cmd: func(repo *IdeaRepository) error {
+				return repo.DeleteByUserId(context.Background(), userId)
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaTestCase(t, &tc)
+		})
+	}
+}
+
+// runIdeaTestCase should be called by [testing.T.Run]
+func runIdeaTestCase(t *testing.T, tc *ideaTestCase) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn := _dbMock.NewMockConn(ctrl)
+	idGen := _uuidMock.NewMockGenerator(ctrl)
+	if tc.reg != nil {
+		tc.reg(ctrl, conn, idGen)
+	}
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	repo := NewIdeaRepository(conn, logger, idGen)
+
+	var err error
+	if tc.cmd != nil {
+		err = tc.cmd(repo)
+	}
+
+	if err != nil {
+		var pxErr proxerr.Error
+		if errors.As(err, &pxErr) {
+			err = pxErr.Unwrap()
+		}
+	}
+
+	if tc.exp != nil {
+		tc.exp(err)
+	}
+}
@@ -12,6 +12,12 @@ type Repository struct {
 	logger log.Logger
 }
 
+// db returns the transaction that was put into the context
+// by [database.TxRunner.RunInTx] or the repository connection
+func (r *Repository) db(ctx context.Context) database.Conn {
+	return database.ConnFromContext(ctx, r.conn)
+}
+
 func (r *Repository) Begin(ctx context.Context) (repository.Tx, error) {
 	tx, err := r.conn.Begin(ctx)
 	if err != nil {
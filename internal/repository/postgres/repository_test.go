@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	_dbMock "github.com/adanyl0v/pocket-ideas/mocks/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
 	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
 	"github.com/golang/mock/gomock"
 	slogzap "github.com/samber/slog-zap/v2"
@@ -71,3 +72,20 @@ func TestRepository_Begin(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_db(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn := _dbMock.NewMockConn(ctrl)
+	tx := _dbMock.NewMockTx(ctrl)
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	repo := &Repository{
+		conn:   conn,
+		logger: logger,
+	}
+
+	require.Equal(t, database.Conn(conn), repo.db(context.Background()))
+	require.Equal(t, database.Conn(tx), repo.db(database.ContextWithTx(context.Background(), tx)))
+}
@@ -61,7 +61,7 @@ func (r *UserRepository) Save(ctx context.Context, user *domain.User) error {
 
 	dto.CreatedAt = time.Now()
 	dto.UpdatedAt = dto.CreatedAt
-	_, err = r.conn.Execute(ctx, qInsertUser, dto.ID, user.Name, dto.Email, dto.Password, dto.CreatedAt, dto.UpdatedAt)
+	_, err = r.db(ctx).Execute(ctx, qInsertUser, dto.ID, user.Name, dto.Email, dto.Password, dto.CreatedAt, dto.UpdatedAt)
 	if err != nil {
 		var pxErr proxerr.Error
 		if errors.As(err, &pxErr) {
@@ -94,7 +94,7 @@ func (r *UserRepository) FindById(ctx context.Context, id string) (domain.User,
 	user := domain.User{ID: id}
 	dto := newFindUserByIdDto(id)
 
-	if err := r.conn.QueryRow(ctx, qFindUserById, id).Scan(&dto.Name,
+	if err := r.db(ctx).QueryRow(ctx, qFindUserById, id).Scan(&dto.Name,
 		&dto.Email, &dto.Password, &dto.CreatedAt, &dto.UpdatedAt); err != nil {
 
 		var pxErr proxerr.Error
@@ -122,7 +122,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (domain.
 	user := domain.User{Email: email}
 	dto := newFindUserByEmailDto(email)
 
-	if err := r.conn.QueryRow(ctx, qFindUserByEmail, email).Scan(&dto.ID,
+	if err := r.db(ctx).QueryRow(ctx, qFindUserByEmail, email).Scan(&dto.ID,
 		&dto.Name, &dto.Password, &dto.CreatedAt, &dto.UpdatedAt); err != nil {
 
 		var pxErr proxerr.Error
@@ -153,7 +153,7 @@ func (r *UserRepository) FindAll(ctx context.Context) ([]domain.User, error) {
 	}()
 
 	users := make([]domain.User, 0)
-	rows, err := r.conn.Query(ctx, qFindAllUsers)
+	rows, err := r.db(ctx).Query(ctx, qFindAllUsers)
 	if err != nil {
 		return nil, err
 	}
@@ -199,7 +199,7 @@ func (r *UserRepository) FindByName(ctx context.Context, name string) ([]domain.
 	}()
 
 	users := make([]domain.User, 0)
-	rows, err := r.conn.Query(ctx, qFindUsersByName, name)
+	rows, err := r.db(ctx).Query(ctx, qFindUsersByName, name)
 	if err != nil {
 		return nil, err
 	}
@@ -250,11 +250,13 @@ func (r *UserRepository) UpdateById(ctx context.Context, user *domain.User) erro
 
 	dto := newUpdateUserByIdDto(user)
 	dto.UpdatedAt = time.Now()
-	res, err := r.conn.Execute(ctx, qUpdateUserById, dto.Name, dto.Email, dto.Password, dto.UpdatedAt, dto.ID)
+	res, err := r.db(ctx).Execute(ctx, qUpdateUserById, dto.Name, dto.Email, dto.Password, dto.UpdatedAt, dto.ID)
 	if err != nil {
 		var pxErr proxerr.Error
 		if errors.As(err, &pxErr) {
 			switch {
+			case errors.Is(pxErr.Unwrap(), database.ErrUniqueViolation):
+				err = proxerr.New(ErrUserAlreadyExists, pxErr.Error())
 			case errors.Is(pxErr.Unwrap(), database.ErrNotNullViolation):
 				err = proxerr.New(ErrUserFieldMustNotBeEmpty, pxErr.Error())
 			case errors.Is(pxErr.Unwrap(), database.ErrForeignKeyViolation):
@@ -266,7 +268,7 @@ func (r *UserRepository) UpdateById(ctx context.Context, user *domain.User) erro
 	}
 
 	if res.RowsAffected() == 0 {
-		err = errors.New("no rows were affected")
+		err = ErrUserNotFound
 		return err
 	}
 
@@ -290,13 +292,13 @@ func (r *UserRepository) DeleteById(ctx context.Context, id string) error {
 	}()
 
 	dto := newDeleteUserByIdDto(id)
-	res, err := r.conn.Execute(ctx, qDeleteUserById, dto.ID)
+	res, err := r.db(ctx).Execute(ctx, qDeleteUserById, dto.ID)
 	if err != nil {
 		return err
 	}
 
 	if res.RowsAffected() == 0 {
-		err = errors.New("no rows were affected")
+		err = ErrUserNotFound
 		return err
 	}
 
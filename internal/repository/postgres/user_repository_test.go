@@ -466,6 +466,18 @@ func TestUserRepository_UpdateById(t *testing.T) {
 				require.NoError(t, err)
 			},
 		},
+		"FAILED user already exists": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().Execute(gomock.Any(), qUpdateUserById, gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any()).Return(nil, proxerr.New(database.ErrUniqueViolation, ""))
+			},
+			cmd: func(repo *UserRepository) error {
+				return repo.UpdateById(context.Background(), &domain.User{ID: id})
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrUserAlreadyExists, err)
+			},
+		},
 		"FAILED user field must not be empty": {
 			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn, _ *_uuidMock.MockGenerator) {
 				conn.EXPECT().Execute(gomock.Any(), qUpdateUserById, gomock.Any(), gomock.Any(), gomock.Any(),
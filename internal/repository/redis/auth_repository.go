@@ -2,11 +2,12 @@ package redis
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
-	"strings"
 	"time"
 
 	"github.com/adanyl0v/pocket-ideas/internal/domain"
@@ -15,7 +16,10 @@ import (
 	"github.com/adanyl0v/pocket-ideas/pkg/uuid"
 )
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound       = errors.New("not found")
+	ErrSessionExpired = errors.New("session expired")
+)
 
 type JSONer interface {
 	Marshal(v interface{}) ([]byte, error)
@@ -31,26 +35,57 @@ const (
 	// sessionKeyFormat will be interpreted as "session:<session_id>"
 	sessionKeyFormat = "session:%s"
 
-	// whitelistKeyFormat will be interpreted as "whitelist:<jwt_token>"
+	// userSessionsIndexKeyFormat will be interpreted as "user_sessions:<user_id>".
+	// The key holds a set of the user session ids and expires with the latest of them
+	userSessionsIndexKeyFormat = "user_sessions:%s"
+
+	// familySessionsIndexKeyFormat will be interpreted as "family_sessions:<family_id>".
+	// The key holds a set of the session ids of the refresh token family
+	familySessionsIndexKeyFormat = "family_sessions:%s"
+
+	// refreshTokenIndexKeyFormat will be interpreted as "refresh_token_session:<refresh_token_hash>".
+	// The key holds the id of the session with the refresh token
+	refreshTokenIndexKeyFormat = "refresh_token_session:%s"
+
+	// fingerprintIndexKeyFormat will be interpreted as "fingerprint_session:<user_id>:<fingerprint_hash>".
+	// The key holds the id of the latest session of the user with the fingerprint
+	fingerprintIndexKeyFormat = "fingerprint_session:%s:%s"
+
+	// whitelistKeyFormat will be interpreted as "whitelist:<access_token_hash>".
+	// The key holds the id of the session the token was issued for
 	whitelistKeyFormat = "whitelist:%s"
 
-	// blacklistKeyFormat will be interpreted as "blacklist:<jwt_token>"
+	// sessionAccessTokensKeyFormat will be interpreted as "session_access_tokens:<session_id>".
+	// The key holds a set of the hashes of the whitelisted access tokens of the session
+	sessionAccessTokensKeyFormat = "session_access_tokens:%s"
+
+	// blacklistKeyFormat will be interpreted as "blacklist:<refresh_token_hash>".
+	// The key holds the family id of the rotated refresh token, or
+	// nothing, if the token was blacklisted with its revoked session
 	blacklistKeyFormat = "blacklist:%s"
+
+	// sessionsScanCount is the number of the keys scanned
+	// by a single round trip in [AuthRepository.FindAllSessions]
+	sessionsScanCount = 100
+
+	// maxSessionTxAttempts limits the retries of a transaction,
+	// which failed because its watched keys were modified
+	maxSessionTxAttempts = 10
 )
 
 type AuthRepository struct {
-	sessionsConn  cache.Conn
+	sessionsConn  cache.Watcher
 	whitelistConn cache.Conn
-	blacklistConn cache.Conn
+	blacklistConn cache.Watcher
 	logger        log.Logger
 	idGen         uuid.Generator
 	jsoner        JSONer
 }
 
 func NewAuthRepository(
-	sessionsConn cache.Conn,
+	sessionsConn cache.Watcher,
 	whitelistConn cache.Conn,
-	blacklistConn cache.Conn,
+	blacklistConn cache.Watcher,
 	logger log.Logger,
 	idGen uuid.Generator,
 ) *AuthRepository {
@@ -68,16 +103,28 @@ func (r *AuthRepository) SetJSONer(jsoner JSONer) {
 	r.jsoner = jsoner
 }
 
+// SaveSession stores the session until it expires. It returns
+// [ErrSessionExpired] if the session has already expired. A session
+// without a family starts a new one identified by the session id
 func (r *AuthRepository) SaveSession(ctx context.Context, session *domain.Session) error {
 	dto := newSaveSessionDto(session)
 
-	var err error
+	ttl, err := sessionTTL(dto.ExpiresAt)
+	if err != nil {
+		r.logger.With(log.Fields{"expires_at": dto.ExpiresAt}).WithError(err).Error("failed to save a session")
+		return err
+	}
+
 	dto.ID, err = r.idGen.NewV7()
 	if err != nil {
 		r.logger.WithError(err).Error("failed to generate session uuid")
 		return err
 	}
 
+	if dto.FamilyID == "" {
+		dto.FamilyID = dto.ID
+	}
+
 	// Set the UTC timezone explicitly
 	dto.CreatedAt = time.Now().UTC()
 	dto.UpdatedAt = dto.CreatedAt
@@ -88,7 +135,23 @@ func (r *AuthRepository) SaveSession(ctx context.Context, session *domain.Sessio
 		return err
 	}
 
-	if err = r.sessionsConn.Set(ctx, formatToSessionKey(dto.ID), b, -1); err != nil {
+	// The user index is watched, so that a concurrent save
+	// doesn't shorten its expiration computed below
+	userKey := formatToUserSessionsIndexKey(dto.UserID)
+	if err = r.watchSessions(ctx, func(conn cache.WatchConn) error {
+		userTTL, err := r.userSessionsIndexTTL(ctx, conn, dto.UserID, ttl)
+		if err != nil {
+			return err
+		}
+
+		return r.execTx(ctx, conn, func(tx cache.Tx) error {
+			if err := tx.Set(ctx, formatToSessionKey(dto.ID), b, ttl); err != nil {
+				return err
+			}
+
+			return r.saveSessionIndexes(ctx, tx, &dto, ttl, userTTL)
+		})
+	}, userKey); err != nil {
 		r.logger.WithError(err).Error("failed to save a session")
 		return err
 	}
@@ -98,23 +161,35 @@ func (r *AuthRepository) SaveSession(ctx context.Context, session *domain.Sessio
 	return nil
 }
 
+// FindSessionById returns [ErrNotFound] only if the session does not exist
+// or has expired. The other errors of the cache are returned unchanged
 func (r *AuthRepository) FindSessionById(ctx context.Context, id string) (domain.Session, error) {
 	logger := r.logger.With(log.Fields{"id": id})
 
-	var session domain.Session
+	session := domain.Session{ID: id}
 	dto := newFindSessionByIdDto(id)
 
-	var raw string
-	if err := r.sessionsConn.Get(ctx, formatToSessionKey(dto.ID), &raw); err != nil {
+	raw, err := r.getRawSession(ctx, r.sessionsConn, dto.ID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			logger.Debug("session not found by id")
+			return domain.Session{}, err
+		}
+
 		logger.WithError(err).Error("failed to find a session by id")
-		return domain.Session{}, proxerr.New(ErrNotFound, err.Error())
+		return domain.Session{}, err
 	}
 
-	if err := r.jsoner.Unmarshal([]byte(raw), &dto); err != nil {
+	if err = r.jsoner.Unmarshal(raw, &dto); err != nil {
 		logger.WithError(err).Error("failed to unmarshal a session")
 		return domain.Session{}, err
 	}
 
+	if isSessionExpired(dto.ExpiresAt) {
+		logger.Debug("found an expired session by id")
+		return domain.Session{}, proxerr.New(ErrNotFound, ErrSessionExpired.Error())
+	}
+
 	dto.ToDomain(&session)
 	r.logger.With(log.Fields{
 		"id":      dto.ID,
@@ -124,43 +199,33 @@ func (r *AuthRepository) FindSessionById(ctx context.Context, id string) (domain
 }
 
 func (r *AuthRepository) FindSessionByRefreshToken(ctx context.Context, refreshToken string) (domain.Session, error) {
-	logger := r.logger.With(log.Fields{"refresh_token": refreshToken})
+	logger := r.logger.With(log.Fields{"refresh_token_hash": hashToken(refreshToken)})
 
 	var session domain.Session
 	dto := newFindSessionByRefreshTokenDto(refreshToken)
 
-	it := r.sessionsConn.Scan(ctx, cache.DefaultScanner)
-	if err := it.Err(); err != nil {
-		logger.WithError(it.Err()).Error("failed to scan sessions")
+	id, err := r.findSessionIdByIndex(ctx, r.sessionsConn, formatToRefreshTokenIndexKey(refreshToken))
+	if err != nil {
+		logger.WithError(err).Error("failed to find a session id by refresh token")
 		return domain.Session{}, err
 	}
 
-	var raw string
-	for it.Next(ctx) {
-		var s string
-		if err := r.sessionsConn.Get(ctx, it.Val(), &s); err != nil {
-			logger.WithError(err).Error("failed to get a session by key")
-			return domain.Session{}, err
-		}
-
-		// Trying to avoid json unmarshalling on every iteration
-		key := fmt.Sprintf("\"refresh_token\":\"%s\"", refreshToken)
-		if strings.Contains(s, key) {
-			raw = s
-			break
-		}
-	}
-	// All tests will return an error here, because the raw string is always empty
-	if raw == "" {
-		logger.WithError(ErrNotFound).Error("failed to find a session by refresh token")
-		return domain.Session{}, ErrNotFound
+	raw, err := r.getRawSession(ctx, r.sessionsConn, id)
+	if err != nil {
+		logger.WithError(err).Error("failed to find a session by refresh token")
+		return domain.Session{}, err
 	}
 
-	if err := r.jsoner.Unmarshal([]byte(raw), &dto); err != nil {
+	if err = r.jsoner.Unmarshal(raw, &dto); err != nil {
 		logger.WithError(err).Error("failed to unmarshal a session")
 		return domain.Session{}, err
 	}
 
+	if isSessionExpired(dto.ExpiresAt) {
+		logger.Debug("found an expired session by refresh token")
+		return domain.Session{}, proxerr.New(ErrNotFound, ErrSessionExpired.Error())
+	}
+
 	dto.ToDomain(&session)
 	r.logger.With(log.Fields{
 		"id":      dto.ID,
@@ -169,50 +234,43 @@ func (r *AuthRepository) FindSessionByRefreshToken(ctx context.Context, refreshT
 	return session, nil
 }
 
-func (r *AuthRepository) FindSessionByFingerprint(ctx context.Context, fp domain.Fingerprint) (domain.Session, error) {
-	logger := r.logger.With(log.Fields{"fingerprint": fp})
+// FindSessionByFingerprint returns the latest session of the user with the
+// fingerprint. The users behind the same address and browser don't share it
+func (r *AuthRepository) FindSessionByFingerprint(
+	ctx context.Context,
+	userId string,
+	fp domain.Fingerprint,
+) (domain.Session, error) {
+	logger := r.logger.With(log.Fields{
+		"user_id":     userId,
+		"fingerprint": fp,
+	})
 
 	var session domain.Session
-	dto := newFindSessionByFingerprintDto(fp)
+	dto := newFindSessionByFingerprintDto(userId, fp)
 
-	fpBytes, err := r.jsoner.Marshal(fp)
+	id, err := r.findSessionIdByIndex(ctx, r.sessionsConn, formatToFingerprintIndexKey(userId, fp))
 	if err != nil {
-		logger.WithError(err).Error("failed to marshal a fingerprint")
+		logger.WithError(err).Error("failed to find a session id by fingerprint")
 		return domain.Session{}, err
 	}
-	fpRaw := string(fpBytes)
 
-	it := r.sessionsConn.Scan(ctx, cache.DefaultScanner)
-	if err = it.Err(); err != nil {
-		logger.WithError(it.Err()).Error("failed to scan sessions")
+	raw, err := r.getRawSession(ctx, r.sessionsConn, id)
+	if err != nil {
+		logger.WithError(err).Error("failed to find a session by fingerprint")
 		return domain.Session{}, err
 	}
 
-	var raw string
-	for it.Next(ctx) {
-		var s string
-		if err = r.sessionsConn.Get(ctx, it.Val(), &s); err != nil {
-			logger.WithError(err).Error("failed to get a session by key")
-			return domain.Session{}, err
-		}
-
-		// Trying to avoid json unmarshalling on every iteration
-		if strings.Contains(s, fpRaw) {
-			raw = s
-			break
-		}
-	}
-	// All tests will return an error here, because the raw string is always empty
-	if raw == "" {
-		logger.WithError(ErrNotFound).Error("failed to find a session by fingerprint")
-		return domain.Session{}, ErrNotFound
-	}
-
-	if err = r.jsoner.Unmarshal([]byte(raw), &dto); err != nil {
+	if err = r.jsoner.Unmarshal(raw, &dto); err != nil {
 		logger.WithError(err).Error("failed to unmarshal a session")
 		return domain.Session{}, err
 	}
 
+	if isSessionExpired(dto.ExpiresAt) {
+		logger.Debug("found an expired session by fingerprint")
+		return domain.Session{}, proxerr.New(ErrNotFound, ErrSessionExpired.Error())
+	}
+
 	dto.ToDomain(&session)
 	r.logger.With(log.Fields{
 		"id":      dto.ID,
@@ -221,9 +279,11 @@ func (r *AuthRepository) FindSessionByFingerprint(ctx context.Context, fp domain
 	return session, nil
 }
 
-// FindAllSessions returns a zero-length slice if no sessions were found
+// FindAllSessions returns a zero-length slice if no sessions were found.
+// Expired sessions are skipped
 func (r *AuthRepository) FindAllSessions(ctx context.Context) ([]domain.Session, error) {
-	it := r.sessionsConn.Scan(ctx, cache.DefaultScanner)
+	// Only the session keys are scanned, as the indexes are stored nearby
+	it := r.sessionsConn.ScanKeys(ctx, formatToSessionKey(""), sessionsScanCount)
 	if err := it.Err(); err != nil {
 		r.logger.WithError(err).Error("failed to scan sessions")
 		return nil, err
@@ -236,6 +296,11 @@ func (r *AuthRepository) FindAllSessions(ctx context.Context) ([]domain.Session,
 
 		var raw string
 		if err := r.sessionsConn.Get(ctx, it.Val(), &raw); err != nil {
+			// The session might expire after the key was scanned
+			if errors.Is(err, cache.ErrKeyDoesNotExist) {
+				continue
+			}
+
 			r.logger.WithError(err).Error("failed to get a session by key")
 			return nil, err
 		}
@@ -245,6 +310,10 @@ func (r *AuthRepository) FindAllSessions(ctx context.Context) ([]domain.Session,
 			return nil, err
 		}
 
+		if isSessionExpired(dto.ExpiresAt) {
+			continue
+		}
+
 		dto.ToDomain(&session)
 		sessions = append(sessions, session)
 	}
@@ -253,36 +322,51 @@ func (r *AuthRepository) FindAllSessions(ctx context.Context) ([]domain.Session,
 	return sessions, nil
 }
 
-// FindSessionsByUserId returns a zero-length slice if no sessions were found
+// FindSessionsByUserId returns a zero-length slice if no sessions were found.
+// Expired sessions are skipped and the ids of the sessions that no longer
+// exist are removed from the index
 func (r *AuthRepository) FindSessionsByUserId(ctx context.Context, userId string) ([]domain.Session, error) {
 	logger := r.logger.With(log.Fields{"user_id": userId})
 
-	it := r.sessionsConn.Scan(ctx, cache.DefaultScanner)
-	if err := it.Err(); err != nil {
-		logger.WithError(err).Error("failed to scan sessions")
+	ids, err := r.sessionsConn.SMembers(ctx, formatToUserSessionsIndexKey(userId))
+	if err != nil {
+		logger.WithError(err).Error("failed to find session ids by user id")
 		return nil, err
 	}
 
-	sessions := make([]domain.Session, 0)
-	key := fmt.Sprintf("\"user_id\":\"%s\"", userId)
-	for it.Next(ctx) {
-		var raw string
-		if err := r.sessionsConn.Get(ctx, it.Val(), &raw); err != nil {
-			logger.WithError(err).Error("failed to get a session by key")
+	sessions := make([]domain.Session, 0, len(ids))
+	var staleIds []any
+	for _, id := range ids {
+		raw, err := r.getRawSession(ctx, r.sessionsConn, id)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				staleIds = append(staleIds, id)
+				continue
+			}
+
+			logger.WithError(err).Error("failed to get a session by id")
 			return nil, err
 		}
 
-		if strings.Contains(raw, key) {
-			var session domain.Session
-			dto := newFindSessionsByUserIdDto(userId)
+		session := domain.Session{User: domain.User{ID: userId}}
+		dto := newFindSessionsByUserIdDto(userId)
+		if err = r.jsoner.Unmarshal(raw, &dto); err != nil {
+			logger.WithError(err).Error("failed to unmarshal a session")
+			return nil, err
+		}
 
-			if err := r.jsoner.Unmarshal([]byte(raw), &dto); err != nil {
-				logger.WithError(err).Error("failed to unmarshal a session")
-				return nil, err
-			}
+		if isSessionExpired(dto.ExpiresAt) {
+			continue
+		}
+
+		dto.ToDomain(&session)
+		sessions = append(sessions, session)
+	}
 
-			dto.ToDomain(&session)
-			sessions = append(sessions, session)
+	if len(staleIds) > 0 {
+		if _, err = r.sessionsConn.SRem(ctx, formatToUserSessionsIndexKey(userId), staleIds...); err != nil {
+			logger.WithError(err).Error("failed to remove stale session ids")
+			return nil, err
 		}
 	}
 
@@ -290,19 +374,130 @@ func (r *AuthRepository) FindSessionsByUserId(ctx context.Context, userId string
 	return sessions, nil
 }
 
+// FindSessionsByFamilyId returns a zero-length slice if no sessions were found.
+// Expired sessions are skipped and the ids of the sessions that no longer
+// exist are removed from the index
+func (r *AuthRepository) FindSessionsByFamilyId(ctx context.Context, familyId string) ([]domain.Session, error) {
+	logger := r.logger.With(log.Fields{"family_id": familyId})
+
+	ids, err := r.sessionsConn.SMembers(ctx, formatToFamilySessionsIndexKey(familyId))
+	if err != nil {
+		logger.WithError(err).Error("failed to find session ids by family id")
+		return nil, err
+	}
+
+	sessions := make([]domain.Session, 0, len(ids))
+	var staleIds []any
+	for _, id := range ids {
+		raw, err := r.getRawSession(ctx, r.sessionsConn, id)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				staleIds = append(staleIds, id)
+				continue
+			}
+
+			logger.WithError(err).Error("failed to get a session by id")
+			return nil, err
+		}
+
+		session := domain.Session{FamilyID: familyId}
+		dto := newFindSessionsByFamilyIdDto(familyId)
+		if err = r.jsoner.Unmarshal(raw, &dto); err != nil {
+			logger.WithError(err).Error("failed to unmarshal a session")
+			return nil, err
+		}
+
+		if isSessionExpired(dto.ExpiresAt) {
+			continue
+		}
+
+		dto.ToDomain(&session)
+		sessions = append(sessions, session)
+	}
+
+	if len(staleIds) > 0 {
+		if _, err = r.sessionsConn.SRem(ctx, formatToFamilySessionsIndexKey(familyId), staleIds...); err != nil {
+			logger.WithError(err).Error("failed to remove stale session ids")
+			return nil, err
+		}
+	}
+
+	logger.Debug(fmt.Sprintf("found %d sessions by family id", len(sessions)))
+	return sessions, nil
+}
+
+// UpdateSessionById replaces the session and moves its indexes,
+// if the refresh token, the fingerprint or the user were changed.
+// The expiration is reset to the new [domain.Session.ExpiresAt], so it
+// returns [ErrSessionExpired] if the updated session has already expired
 func (r *AuthRepository) UpdateSessionById(ctx context.Context, session *domain.Session) error {
 	logger := r.logger.With(log.Fields{"id": session.ID})
 
 	dto := newUpdateSessionByIdDto(session)
-	dto.UpdatedAt = time.Now().UTC()
-
-	b, err := r.jsoner.Marshal(dto)
+	ttl, err := sessionTTL(dto.ExpiresAt)
 	if err != nil {
-		logger.WithError(err).Error("failed to marshal a session")
+		logger.With(log.Fields{"expires_at": dto.ExpiresAt}).WithError(err).Error("failed to update a session")
 		return err
 	}
 
-	if err = r.sessionsConn.Set(ctx, formatToSessionKey(dto.ID), b, -1); err != nil {
+	// The stored session is watched, so that the indexes
+	// moved by a concurrent update are not left behind
+	if err = r.watchSessions(ctx, func(conn cache.WatchConn) error {
+		old, err := r.findSessionDto(ctx, conn, session.ID)
+		if err != nil {
+			return err
+		}
+
+		if isSessionExpired(old.ExpiresAt) {
+			logger.Debug("session to update has expired")
+			return proxerr.New(ErrNotFound, ErrSessionExpired.Error())
+		}
+
+		updated := dto
+		if updated.FamilyID == "" {
+			updated.FamilyID = old.FamilyID
+		}
+		updated.UpdatedAt = time.Now().UTC()
+
+		indexes := sessionIndexes{
+			user:         updated.UserID != old.UserID,
+			family:       updated.FamilyID != old.FamilyID,
+			refreshToken: updated.RefreshToken != old.RefreshToken,
+			fingerprint:  updated.Fingerprint != old.Fingerprint || updated.UserID != old.UserID,
+		}
+		if indexes.fingerprint {
+			if indexes.fingerprint, err = r.ownsFingerprintIndex(ctx, conn, &old); err != nil {
+				return err
+			}
+		}
+
+		userTTL, err := r.userSessionsIndexTTL(ctx, conn, updated.UserID, ttl)
+		if err != nil {
+			return err
+		}
+
+		b, err := r.jsoner.Marshal(updated)
+		if err != nil {
+			return err
+		}
+
+		if err = r.execTx(ctx, conn, func(tx cache.Tx) error {
+			if err := tx.Set(ctx, formatToSessionKey(updated.ID), b, ttl); err != nil {
+				return err
+			}
+
+			if err := r.deleteSessionIndexes(ctx, tx, &old, indexes); err != nil {
+				return err
+			}
+
+			return r.saveSessionIndexes(ctx, tx, (*saveSessionDto)(&updated), ttl, userTTL)
+		}); err != nil {
+			return err
+		}
+
+		dto = updated
+		return nil
+	}, formatToSessionKey(session.ID), formatToUserSessionsIndexKey(session.User.ID)); err != nil {
 		logger.WithError(err).Error("failed to update a session")
 		return err
 	}
@@ -312,11 +507,35 @@ func (r *AuthRepository) UpdateSessionById(ctx context.Context, session *domain.
 	return nil
 }
 
+// DeleteSessionById deletes the session with its indexes. It is not
+// an error if the session does not exist
 func (r *AuthRepository) DeleteSessionById(ctx context.Context, id string) error {
 	logger := r.logger.With(log.Fields{"id": id})
 
-	_, err := r.sessionsConn.Delete(ctx, formatToSessionKey(id))
-	if err != nil {
+	if err := r.watchSessions(ctx, func(conn cache.WatchConn) error {
+		dto, err := r.findSessionDto(ctx, conn, id)
+		if err != nil {
+			return err
+		}
+
+		indexes := allSessionIndexes
+		if indexes.fingerprint, err = r.ownsFingerprintIndex(ctx, conn, &dto); err != nil {
+			return err
+		}
+
+		return r.execTx(ctx, conn, func(tx cache.Tx) error {
+			if _, err := tx.Delete(ctx, formatToSessionKey(id)); err != nil {
+				return err
+			}
+
+			return r.deleteSessionIndexes(ctx, tx, &dto, indexes)
+		})
+	}, formatToSessionKey(id)); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			logger.Debug("session to delete not found")
+			return nil
+		}
+
 		logger.WithError(err).Error("failed to delete a session")
 		return err
 	}
@@ -325,11 +544,29 @@ func (r *AuthRepository) DeleteSessionById(ctx context.Context, id string) error
 	return nil
 }
 
-func (r *AuthRepository) SaveAccessTokenToWhitelist(ctx context.Context, accessToken string, expiration time.Duration) error {
-	logger := r.logger.With(log.Fields{"access_token": accessToken})
+// SaveAccessTokenToWhitelist also tracks the token among the tokens of the session,
+// so that they can be deleted by [AuthRepository.DeleteSessionAccessTokensFromWhitelist]
+func (r *AuthRepository) SaveAccessTokenToWhitelist(
+	ctx context.Context,
+	sessionId, accessToken string,
+	expiration time.Duration,
+) error {
+	logger := r.logger.With(log.Fields{
+		"session_id":        sessionId,
+		"access_token_hash": hashToken(accessToken),
+	})
+
+	tx := r.whitelistConn.Begin(ctx)
+	if err := r.queueWhitelistAccessToken(ctx, tx, sessionId, accessToken, expiration); err != nil {
+		if txErr := tx.Discard(ctx); txErr != nil {
+			err = errors.Join(err, txErr)
+		}
 
-	if err := r.whitelistConn.Set(ctx, formatAccessTokenIntoCacheKey(accessToken),
-		accessToken, expiration); err != nil {
+		logger.WithError(err).Error("failed to save access token to whitelist")
+		return err
+	}
+
+	if err := tx.Exec(ctx); err != nil {
 		logger.WithError(err).Error("failed to save access token to whitelist")
 		return err
 	}
@@ -339,7 +576,7 @@ func (r *AuthRepository) SaveAccessTokenToWhitelist(ctx context.Context, accessT
 }
 
 func (r *AuthRepository) FindAccessTokenInWhitelist(ctx context.Context, accessToken string) (bool, error) {
-	logger := r.logger.With(log.Fields{"access_token": accessToken})
+	logger := r.logger.With(log.Fields{"access_token_hash": hashToken(accessToken)})
 
 	n, err := r.whitelistConn.Exists(ctx, formatAccessTokenIntoCacheKey(accessToken))
 	if err != nil {
@@ -357,7 +594,7 @@ func (r *AuthRepository) FindAccessTokenInWhitelist(ctx context.Context, accessT
 }
 
 func (r *AuthRepository) DeleteAccessTokenFromWhitelist(ctx context.Context, accessToken string) error {
-	logger := r.logger.With(log.Fields{"access_token": accessToken})
+	logger := r.logger.With(log.Fields{"access_token_hash": hashToken(accessToken)})
 
 	if _, err := r.whitelistConn.Delete(ctx, formatAccessTokenIntoCacheKey(accessToken)); err != nil {
 		logger.WithError(err).Error("failed to delete access token from whitelist")
@@ -368,21 +605,84 @@ func (r *AuthRepository) DeleteAccessTokenFromWhitelist(ctx context.Context, acc
 	return nil
 }
 
-func (r *AuthRepository) SaveRefreshTokenToBlacklist(ctx context.Context, refreshToken string, expiration time.Duration) error {
-	logger := r.logger.With(log.Fields{"refresh_token": refreshToken})
+// DeleteSessionAccessTokensFromWhitelist deletes all the access tokens
+// saved by [AuthRepository.SaveAccessTokenToWhitelist] for the session
+func (r *AuthRepository) DeleteSessionAccessTokensFromWhitelist(ctx context.Context, sessionId string) error {
+	logger := r.logger.With(log.Fields{"session_id": sessionId})
 
-	if err := r.blacklistConn.Set(ctx, formatRefreshTokenIntoCacheKey(refreshToken),
-		refreshToken, expiration); err != nil {
-		logger.WithError(err).Error("failed to save refresh token to blacklist")
+	key := formatToSessionAccessTokensKey(sessionId)
+	hashes, err := r.whitelistConn.SMembers(ctx, key)
+	if err != nil {
+		logger.WithError(err).Error("failed to find access tokens of the session")
 		return err
 	}
 
-	logger.Debug("saved refresh token to blacklist")
+	tx := r.whitelistConn.Begin(ctx)
+	if err = r.queueDeleteAccessTokens(ctx, tx, key, hashes); err != nil {
+		if txErr := tx.Discard(ctx); txErr != nil {
+			err = errors.Join(err, txErr)
+		}
+
+		logger.WithError(err).Error("failed to delete access tokens of the session from whitelist")
+		return err
+	}
+
+	if err = tx.Exec(ctx); err != nil {
+		logger.WithError(err).Error("failed to delete access tokens of the session from whitelist")
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("deleted %d access tokens of the session from whitelist", len(hashes)))
 	return nil
 }
 
+// SaveRefreshTokenToBlacklist remembers the family of the token, so that a reuse
+// of the token can be traced back to the family sessions. The token is only saved,
+// if it is not blacklisted yet, so that only one of the concurrent rotations of the
+// token succeeds. It reports whether the token was saved
+func (r *AuthRepository) SaveRefreshTokenToBlacklist(
+	ctx context.Context,
+	refreshToken, familyId string,
+	expiration time.Duration,
+) (bool, error) {
+	logger := r.logger.With(log.Fields{
+		"refresh_token_hash": hashToken(refreshToken),
+		"family_id":          familyId,
+	})
+
+	var saved bool
+	key := formatRefreshTokenIntoCacheKey(refreshToken)
+	err := r.blacklistConn.Watch(ctx, func(conn cache.WatchConn) error {
+		n, err := conn.Exists(ctx, key)
+		if err != nil || n > 0 {
+			return err
+		}
+
+		if err = r.execTx(ctx, conn, func(tx cache.Tx) error {
+			return tx.Set(ctx, key, familyId, expiration)
+		}); err != nil {
+			return err
+		}
+
+		saved = true
+		return nil
+	}, key)
+	if err != nil && !errors.Is(err, cache.ErrTxFailed) {
+		logger.WithError(err).Error("failed to save refresh token to blacklist")
+		return false, err
+	}
+
+	if !saved {
+		logger.Debug("refresh token is already blacklisted")
+		return false, nil
+	}
+
+	logger.Debug("saved refresh token to blacklist")
+	return true, nil
+}
+
 func (r *AuthRepository) FindRefreshTokenInBlacklist(ctx context.Context, refreshToken string) (bool, error) {
-	logger := r.logger.With(log.Fields{"refresh_token": refreshToken})
+	logger := r.logger.With(log.Fields{"refresh_token_hash": hashToken(refreshToken)})
 
 	n, err := r.blacklistConn.Exists(ctx, formatRefreshTokenIntoCacheKey(refreshToken))
 	if err != nil {
@@ -399,8 +699,28 @@ func (r *AuthRepository) FindRefreshTokenInBlacklist(ctx context.Context, refres
 	return true, nil
 }
 
+// FindRefreshTokenFamilyInBlacklist returns the family id of
+// the token or [ErrNotFound] if the token is not blacklisted
+func (r *AuthRepository) FindRefreshTokenFamilyInBlacklist(ctx context.Context, refreshToken string) (string, error) {
+	logger := r.logger.With(log.Fields{"refresh_token_hash": hashToken(refreshToken)})
+
+	var familyId string
+	if err := r.blacklistConn.Get(ctx, formatRefreshTokenIntoCacheKey(refreshToken), &familyId); err != nil {
+		if errors.Is(err, cache.ErrKeyDoesNotExist) {
+			logger.Debug("refresh token not found in blacklist")
+			return "", proxerr.New(ErrNotFound, err.Error())
+		}
+
+		logger.WithError(err).Error("failed to find refresh token family in blacklist")
+		return "", err
+	}
+
+	logger.With(log.Fields{"family_id": familyId}).Debug("found refresh token family in blacklist")
+	return familyId, nil
+}
+
 func (r *AuthRepository) DeleteRefreshTokenFromBlacklist(ctx context.Context, refreshToken string) error {
-	logger := r.logger.With(log.Fields{"refresh_token": refreshToken})
+	logger := r.logger.With(log.Fields{"refresh_token_hash": hashToken(refreshToken)})
 
 	if _, err := r.blacklistConn.Delete(ctx, formatRefreshTokenIntoCacheKey(refreshToken)); err != nil {
 		logger.WithError(err).Error("failed to delete refresh token from blacklist")
@@ -411,6 +731,262 @@ func (r *AuthRepository) DeleteRefreshTokenFromBlacklist(ctx context.Context, re
 	return nil
 }
 
+// execTx executes the commands queued by the function atomically
+func (r *AuthRepository) execTx(ctx context.Context, conn cache.Conn, fn func(tx cache.Tx) error) error {
+	tx := conn.Begin(ctx)
+	if err := fn(tx); err != nil {
+		if txErr := tx.Discard(ctx); txErr != nil {
+			return errors.Join(err, txErr)
+		}
+
+		return err
+	}
+
+	return tx.Exec(ctx)
+}
+
+// sessionIndexes selects the indexes of a session
+type sessionIndexes struct {
+	user         bool
+	family       bool
+	refreshToken bool
+	fingerprint  bool
+}
+
+var allSessionIndexes = sessionIndexes{
+	user:         true,
+	family:       true,
+	refreshToken: true,
+	fingerprint:  true,
+}
+
+// saveSessionIndexes makes the refresh token and the fingerprint indexes
+// expire along with the session. The family index lives as long as its
+// latest session. The user index is shared by all the user sessions, so it
+// expires after userTTL computed by [AuthRepository.userSessionsIndexTTL],
+// and its expired ids are removed on [AuthRepository.FindSessionsByUserId]
+func (r *AuthRepository) saveSessionIndexes(
+	ctx context.Context,
+	tx cache.Tx,
+	dto *saveSessionDto,
+	ttl, userTTL time.Duration,
+) error {
+	userKey := formatToUserSessionsIndexKey(dto.UserID)
+	if _, err := tx.SAdd(ctx, userKey, dto.ID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Expire(ctx, userKey, userTTL); err != nil {
+		return err
+	}
+
+	familyKey := formatToFamilySessionsIndexKey(dto.FamilyID)
+	if _, err := tx.SAdd(ctx, familyKey, dto.ID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Expire(ctx, familyKey, ttl); err != nil {
+		return err
+	}
+
+	if dto.RefreshToken != "" {
+		if err := tx.Set(ctx, formatToRefreshTokenIndexKey(dto.RefreshToken), dto.ID, ttl); err != nil {
+			return err
+		}
+	}
+
+	return tx.Set(ctx, formatToFingerprintIndexKey(dto.UserID, dto.Fingerprint), dto.ID, ttl)
+}
+
+// userSessionsIndexTTL returns the expiration of the user index, which outlives
+// both its current sessions and the one with the ttl. The connection must watch
+// the index, so that a concurrent change of the expiration fails the transaction
+func (r *AuthRepository) userSessionsIndexTTL(
+	ctx context.Context,
+	conn cache.Conn,
+	userId string,
+	ttl time.Duration,
+) (time.Duration, error) {
+	current, err := conn.TTL(ctx, formatToUserSessionsIndexKey(userId))
+	if err != nil {
+		if errors.Is(err, cache.ErrKeyDoesNotExist) {
+			return ttl, nil
+		}
+
+		return 0, err
+	}
+
+	// The indexes saved before they started to expire are given the ttl too
+	return max(current, ttl), nil
+}
+
+// watchSessions runs the function on a connection watching the keys. It is
+// retried, while its transaction fails because the keys were modified
+func (r *AuthRepository) watchSessions(ctx context.Context, fn func(conn cache.WatchConn) error, keys ...string) error {
+	var err error
+	for range maxSessionTxAttempts {
+		if err = r.sessionsConn.Watch(ctx, fn, keys...); !errors.Is(err, cache.ErrTxFailed) {
+			return err
+		}
+
+		r.logger.With(log.Fields{"keys": keys}).Debug("watched keys were modified, retrying the transaction")
+	}
+
+	return err
+}
+
+// deleteSessionIndexes deletes the selected indexes of the session.
+// The fingerprint index is only selected, if [AuthRepository.ownsFingerprintIndex]
+func (r *AuthRepository) deleteSessionIndexes(
+	ctx context.Context,
+	tx cache.Tx,
+	dto *findSessionByIdDto,
+	indexes sessionIndexes,
+) error {
+	if indexes.user {
+		if _, err := tx.SRem(ctx, formatToUserSessionsIndexKey(dto.UserID), dto.ID); err != nil {
+			return err
+		}
+	}
+
+	if indexes.family && dto.FamilyID != "" {
+		if _, err := tx.SRem(ctx, formatToFamilySessionsIndexKey(dto.FamilyID), dto.ID); err != nil {
+			return err
+		}
+	}
+
+	if indexes.refreshToken && dto.RefreshToken != "" {
+		if _, err := tx.Delete(ctx, formatToRefreshTokenIndexKey(dto.RefreshToken)); err != nil {
+			return err
+		}
+	}
+
+	if indexes.fingerprint {
+		if _, err := tx.Delete(ctx, formatToFingerprintIndexKey(dto.UserID, dto.Fingerprint)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ownsFingerprintIndex watches the fingerprint index of the session and reports
+// whether it points to the session. It may already point to a newer session
+// of the user with the same fingerprint, which must keep the index
+func (r *AuthRepository) ownsFingerprintIndex(
+	ctx context.Context,
+	conn cache.WatchConn,
+	dto *findSessionByIdDto,
+) (bool, error) {
+	key := formatToFingerprintIndexKey(dto.UserID, dto.Fingerprint)
+	if err := conn.Watch(ctx, key); err != nil {
+		return false, err
+	}
+
+	id, err := r.findSessionIdByIndex(ctx, conn, key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return id == dto.ID, nil
+}
+
+func (r *AuthRepository) queueWhitelistAccessToken(
+	ctx context.Context,
+	tx cache.Tx,
+	sessionId, accessToken string,
+	expiration time.Duration,
+) error {
+	if err := tx.Set(ctx, formatAccessTokenIntoCacheKey(accessToken), sessionId, expiration); err != nil {
+		return err
+	}
+
+	// The tokens are issued one after another, so the latest one expires last
+	key := formatToSessionAccessTokensKey(sessionId)
+	if _, err := tx.SAdd(ctx, key, hashToken(accessToken)); err != nil {
+		return err
+	}
+
+	_, err := tx.Expire(ctx, key, expiration)
+	return err
+}
+
+func (r *AuthRepository) queueDeleteAccessTokens(ctx context.Context, tx cache.Tx, key string, hashes []string) error {
+	for _, hash := range hashes {
+		if _, err := tx.Delete(ctx, fmt.Sprintf(whitelistKeyFormat, hash)); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Delete(ctx, key)
+	return err
+}
+
+// findSessionIdByIndex returns [ErrNotFound] if the index key does not exist
+func (r *AuthRepository) findSessionIdByIndex(ctx context.Context, conn cache.Conn, key string) (string, error) {
+	var id string
+	if err := conn.Get(ctx, key, &id); err != nil {
+		if errors.Is(err, cache.ErrKeyDoesNotExist) {
+			return "", proxerr.New(ErrNotFound, err.Error())
+		}
+
+		return "", err
+	}
+
+	return id, nil
+}
+
+// getRawSession returns [ErrNotFound] if the session does not exist
+func (r *AuthRepository) getRawSession(ctx context.Context, conn cache.Conn, id string) ([]byte, error) {
+	var raw string
+	if err := conn.Get(ctx, formatToSessionKey(id), &raw); err != nil {
+		if errors.Is(err, cache.ErrKeyDoesNotExist) {
+			return nil, proxerr.New(ErrNotFound, err.Error())
+		}
+
+		return nil, err
+	}
+
+	return []byte(raw), nil
+}
+
+func (r *AuthRepository) findSessionDto(ctx context.Context, conn cache.Conn, id string) (findSessionByIdDto, error) {
+	dto := newFindSessionByIdDto(id)
+
+	raw, err := r.getRawSession(ctx, conn, id)
+	if err != nil {
+		return findSessionByIdDto{}, err
+	}
+
+	if err = r.jsoner.Unmarshal(raw, &dto); err != nil {
+		r.logger.WithError(err).Error("failed to unmarshal a session")
+		return findSessionByIdDto{}, err
+	}
+
+	return dto, nil
+}
+
+// sessionTTL returns the time left until the session expires
+// or [ErrSessionExpired] if there is no time left
+func sessionTTL(expiresAt time.Time) (time.Duration, error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return 0, ErrSessionExpired
+	}
+
+	return ttl, nil
+}
+
+// isSessionExpired covers the sessions that have
+// expired, but were not evicted by Redis yet
+func isSessionExpired(expiresAt time.Time) bool {
+	return !expiresAt.After(time.Now())
+}
+
 func formatToSessionKey(sessionId string) string {
 	if sessionId == "" {
 		sessionId = "*"
@@ -420,9 +996,39 @@ func formatToSessionKey(sessionId string) string {
 }
 
 func formatAccessTokenIntoCacheKey(accessToken string) string {
-	return fmt.Sprintf(whitelistKeyFormat, accessToken)
+	return fmt.Sprintf(whitelistKeyFormat, hashToken(accessToken))
 }
 
 func formatRefreshTokenIntoCacheKey(refreshToken string) string {
-	return fmt.Sprintf(blacklistKeyFormat, refreshToken)
+	return fmt.Sprintf(blacklistKeyFormat, hashToken(refreshToken))
+}
+
+func formatToUserSessionsIndexKey(userId string) string {
+	return fmt.Sprintf(userSessionsIndexKeyFormat, userId)
+}
+
+func formatToFamilySessionsIndexKey(familyId string) string {
+	return fmt.Sprintf(familySessionsIndexKeyFormat, familyId)
+}
+
+func formatToSessionAccessTokensKey(sessionId string) string {
+	return fmt.Sprintf(sessionAccessTokensKeyFormat, sessionId)
+}
+
+func formatToRefreshTokenIndexKey(refreshToken string) string {
+	return fmt.Sprintf(refreshTokenIndexKeyFormat, hashToken(refreshToken))
+}
+
+// formatToFingerprintIndexKey hashes the fingerprint,
+// so that the user agent doesn't bloat the key
+func formatToFingerprintIndexKey(userId string, fp domain.Fingerprint) string {
+	sum := sha256.Sum256([]byte(fp.ClientIP + "\x00" + fp.UserAgent))
+	return fmt.Sprintf(fingerprintIndexKeyFormat, userId, hex.EncodeToString(sum[:]))
+}
+
+// hashToken keeps the tokens out of the keys and the logs, so that
+// neither of them is enough to take over a session
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -8,6 +8,7 @@ import (
 
 type saveSessionDto struct {
 	ID           string             `json:"id"`
+	FamilyID     string             `json:"family_id"`
 	UserID       string             `json:"user_id"`
 	Fingerprint  domain.Fingerprint `json:"fingerprint"`
 	RefreshToken string             `json:"refresh_token"`
@@ -18,6 +19,7 @@ type saveSessionDto struct {
 
 func newSaveSessionDto(s *domain.Session) saveSessionDto {
 	return saveSessionDto{
+		FamilyID:     s.FamilyID,
 		UserID:       s.User.ID,
 		Fingerprint:  s.Fingerprint,
 		RefreshToken: s.RefreshToken,
@@ -27,6 +29,7 @@ func newSaveSessionDto(s *domain.Session) saveSessionDto {
 
 func (d *saveSessionDto) ToDomain(s *domain.Session) {
 	s.ID = d.ID
+	s.FamilyID = d.FamilyID
 	s.CreatedAt = d.CreatedAt
 	s.UpdatedAt = d.UpdatedAt
 }
@@ -38,6 +41,7 @@ func newFindSessionByIdDto(id string) findSessionByIdDto {
 }
 
 func (d *findSessionByIdDto) ToDomain(s *domain.Session) {
+	s.FamilyID = d.FamilyID
 	s.User.ID = d.UserID
 	s.Fingerprint = d.Fingerprint
 	s.RefreshToken = d.RefreshToken
@@ -56,6 +60,7 @@ func newFindSessionByRefreshTokenDto(refreshToken string) findSessionByRefreshTo
 
 func (d *findSessionByRefreshTokenDto) ToDomain(s *domain.Session) {
 	s.ID = d.ID
+	s.FamilyID = d.FamilyID
 	s.User.ID = d.UserID
 	s.Fingerprint = d.Fingerprint
 	s.ExpiresAt = d.ExpiresAt
@@ -65,14 +70,16 @@ func (d *findSessionByRefreshTokenDto) ToDomain(s *domain.Session) {
 
 type findSessionByFingerprintDto saveSessionDto
 
-func newFindSessionByFingerprintDto(fp domain.Fingerprint) findSessionByFingerprintDto {
+func newFindSessionByFingerprintDto(userId string, fp domain.Fingerprint) findSessionByFingerprintDto {
 	return findSessionByFingerprintDto{
+		UserID:      userId,
 		Fingerprint: fp,
 	}
 }
 
 func (d *findSessionByFingerprintDto) ToDomain(s *domain.Session) {
 	s.ID = d.ID
+	s.FamilyID = d.FamilyID
 	s.User.ID = d.UserID
 	s.RefreshToken = d.RefreshToken
 	s.ExpiresAt = d.ExpiresAt
@@ -88,6 +95,7 @@ func newFindAllSessionsDto() findAllSessionsDto {
 
 func (d *findAllSessionsDto) ToDomain(s *domain.Session) {
 	s.ID = d.ID
+	s.FamilyID = d.FamilyID
 	s.User.ID = d.UserID
 	s.Fingerprint = d.Fingerprint
 	s.RefreshToken = d.RefreshToken
@@ -106,6 +114,25 @@ func newFindSessionsByUserIdDto(userId string) findSessionsByUserIdDto {
 
 func (d *findSessionsByUserIdDto) ToDomain(s *domain.Session) {
 	s.ID = d.ID
+	s.FamilyID = d.FamilyID
+	s.Fingerprint = d.Fingerprint
+	s.RefreshToken = d.RefreshToken
+	s.ExpiresAt = d.ExpiresAt
+	s.CreatedAt = d.CreatedAt
+	s.UpdatedAt = d.UpdatedAt
+}
+
+type findSessionsByFamilyIdDto saveSessionDto
+
+func newFindSessionsByFamilyIdDto(familyId string) findSessionsByFamilyIdDto {
+	return findSessionsByFamilyIdDto{
+		FamilyID: familyId,
+	}
+}
+
+func (d *findSessionsByFamilyIdDto) ToDomain(s *domain.Session) {
+	s.ID = d.ID
+	s.User.ID = d.UserID
 	s.Fingerprint = d.Fingerprint
 	s.RefreshToken = d.RefreshToken
 	s.ExpiresAt = d.ExpiresAt
@@ -118,6 +145,7 @@ type updateSessionByIdDto saveSessionDto
 func newUpdateSessionByIdDto(s *domain.Session) updateSessionByIdDto {
 	return updateSessionByIdDto{
 		ID:           s.ID,
+		FamilyID:     s.FamilyID,
 		UserID:       s.User.ID,
 		Fingerprint:  s.Fingerprint,
 		RefreshToken: s.RefreshToken,
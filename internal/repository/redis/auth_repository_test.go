@@ -2,12 +2,16 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"github.com/adanyl0v/pocket-ideas/internal/domain"
 	_redisrepoMock "github.com/adanyl0v/pocket-ideas/internal/repository/redis/mocks"
 	_cacheMock "github.com/adanyl0v/pocket-ideas/mocks/pkg/cache"
 	_uuidMock "github.com/adanyl0v/pocket-ideas/mocks/pkg/uuid"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/memory"
 	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
 	"github.com/golang/mock/gomock"
 	slogzap "github.com/samber/slog-zap/v2"
 	"github.com/stretchr/testify/require"
@@ -23,11 +27,29 @@ type (
 		exp authRepositoryTestCaseExpect
 	}
 
-	authRepositoryTestCaseRegister func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator)
+	authRepositoryTestCaseRegister func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator)
 	authRepositoryTestCaseCommand  func(repo *AuthRepository) error
 	authRepositoryTestCaseExpect   func(err error)
 )
 
+const (
+	testSessionId    = "0194fa01-1a2b-7c3d-8e4f-5a6b7c8d9e0f"
+	testUserId       = "0194fa02-2b3c-7d4e-8f5a-6b7c8d9e0f1a"
+	testRefreshToken = "testRefreshToken"
+)
+
+var testFingerprint = domain.Fingerprint{
+	ClientIP:  "127.0.0.1",
+	UserAgent: "test",
+}
+
+// testExpiresAt is far enough, so that the test sessions don't expire
+var testExpiresAt = time.Now().Add(time.Hour)
+
+var testRawSession = `{"id":"` + testSessionId + `","family_id":"` + testSessionId + `","user_id":"` + testUserId +
+	`","fingerprint":{"client_ip":"127.0.0.1","user_agent":"test"},"refresh_token":"` + testRefreshToken +
+	`","expires_at":"2030-01-01T00:00:00Z","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}`
+
 type (
 	authRepoSessionsTestCase struct {
 		reg authRepoSessionsTcRegister
@@ -37,7 +59,7 @@ type (
 
 	authRepoSessionsTcRegister func(
 		_ *gomock.Controller,
-		conn *_cacheMock.MockConn,
+		conn *_cacheMock.MockWatcher,
 		_ *_uuidMock.MockGenerator,
 		jsoner *_redisrepoMock.MockJSONer,
 	)
@@ -48,51 +70,131 @@ type (
 func TestAuthRepository_SaveSession(t *testing.T) {
 	tcs := map[string]authRepoSessionsTestCase{
 		"SUCCESS": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, idGen *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
-				idGen.EXPECT().NewV7().Return("", nil)
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, idGen *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				idGen.EXPECT().NewV7().Return(testSessionId, nil)
 				jsoner.EXPECT().Marshal(gomock.Any()).Return(nil, nil)
-				conn.EXPECT().Set(gomock.Any(), formatToSessionKey(""), gomock.Any(), gomock.Any()).
-					Return(nil)
+
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToUserSessionsIndexKey(testUserId)).
+					DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().TTL(gomock.Any(), formatToUserSessionsIndexKey(testUserId)).
+					Return(time.Duration(0), proxerr.New(cache.ErrKeyDoesNotExist, ""))
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				wconn.EXPECT().Begin(gomock.Any()).Return(tx)
+				gomock.InOrder(
+					tx.EXPECT().Set(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any(), ttlMatcher{}).Return(nil),
+					tx.EXPECT().SAdd(gomock.Any(), formatToUserSessionsIndexKey(testUserId), testSessionId).Return(int64(1), nil),
+					tx.EXPECT().Expire(gomock.Any(), formatToUserSessionsIndexKey(testUserId), ttlMatcher{}).Return(true, nil),
+					tx.EXPECT().SAdd(gomock.Any(), formatToFamilySessionsIndexKey(testSessionId), testSessionId).Return(int64(1), nil),
+					tx.EXPECT().Expire(gomock.Any(), formatToFamilySessionsIndexKey(testSessionId), ttlMatcher{}).Return(true, nil),
+					tx.EXPECT().Set(gomock.Any(), formatToRefreshTokenIndexKey(testRefreshToken), testSessionId, ttlMatcher{}).Return(nil),
+					tx.EXPECT().Set(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint), testSessionId, ttlMatcher{}).Return(nil),
+					tx.EXPECT().Exec(gomock.Any()).Return(nil),
+				)
 			},
 			cmd: func(repo *AuthRepository) error {
-				return repo.SaveSession(context.Background(), new(domain.Session))
+				session := domain.Session{
+					User:         domain.User{ID: testUserId},
+					Fingerprint:  testFingerprint,
+					RefreshToken: testRefreshToken,
+					ExpiresAt:    testExpiresAt,
+				}
+				err := repo.SaveSession(context.Background(), &session)
+				require.Equal(t, testSessionId, session.ID)
+				require.Equal(t, testSessionId, session.FamilyID)
+				return err
 			},
 			exp: func(err error) {
 				require.NoError(t, err)
 			},
 		},
+		"SUCCESS keeps the longer expiration of the user index": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, idGen *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				idGen.EXPECT().NewV7().Return(testSessionId, nil)
+				jsoner.EXPECT().Marshal(gomock.Any()).Return(nil, nil)
+
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToUserSessionsIndexKey(testUserId)).
+					DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().TTL(gomock.Any(), formatToUserSessionsIndexKey(testUserId)).Return(2*time.Hour, nil)
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				wconn.EXPECT().Begin(gomock.Any()).Return(tx)
+				tx.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(3).Return(nil)
+				tx.EXPECT().SAdd(gomock.Any(), gomock.Any(), testSessionId).Times(2).Return(int64(1), nil)
+				tx.EXPECT().Expire(gomock.Any(), formatToUserSessionsIndexKey(testUserId), 2*time.Hour).Return(true, nil)
+				tx.EXPECT().Expire(gomock.Any(), formatToFamilySessionsIndexKey(testSessionId), ttlMatcher{}).Return(true, nil)
+				tx.EXPECT().Exec(gomock.Any()).Return(nil)
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.SaveSession(context.Background(), &domain.Session{
+					User:         domain.User{ID: testUserId},
+					Fingerprint:  testFingerprint,
+					RefreshToken: testRefreshToken,
+					ExpiresAt:    testExpiresAt,
+				})
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED session expired": {
+			reg: func(_ *gomock.Controller, _ *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.SaveSession(context.Background(), &domain.Session{
+					User:      domain.User{ID: testUserId},
+					ExpiresAt: time.Now().Add(-time.Second),
+				})
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrSessionExpired)
+			},
+		},
 		"FAILED to generate session uuid": {
-			reg: func(_ *gomock.Controller, _ *_cacheMock.MockConn, idGen *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+			reg: func(_ *gomock.Controller, _ *_cacheMock.MockWatcher, idGen *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
 				idGen.EXPECT().NewV7().Return("", errors.New(""))
 			},
 			cmd: func(repo *AuthRepository) error {
-				return repo.SaveSession(context.Background(), new(domain.Session))
+				return repo.SaveSession(context.Background(), &domain.Session{ExpiresAt: testExpiresAt})
 			},
 			exp: func(err error) {
 				require.Error(t, err)
 			},
 		},
 		"FAILED to marshal a session": {
-			reg: func(_ *gomock.Controller, _ *_cacheMock.MockConn, idGen *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+			reg: func(_ *gomock.Controller, _ *_cacheMock.MockWatcher, idGen *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
 				idGen.EXPECT().NewV7().Return("", nil)
 				jsoner.EXPECT().Marshal(gomock.Any()).Return(nil, errors.New(""))
 			},
 			cmd: func(repo *AuthRepository) error {
-				return repo.SaveSession(context.Background(), new(domain.Session))
+				return repo.SaveSession(context.Background(), &domain.Session{ExpiresAt: testExpiresAt})
 			},
 			exp: func(err error) {
 				require.Error(t, err)
 			},
 		},
 		"FAILED to save a session": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, idGen *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, idGen *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
 				idGen.EXPECT().NewV7().Return("", nil)
 				jsoner.EXPECT().Marshal(gomock.Any()).Return(nil, nil)
-				conn.EXPECT().Set(gomock.Any(), formatToSessionKey(""), gomock.Any(), gomock.Any()).
-					Return(errors.New(""))
+
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToUserSessionsIndexKey("")).
+					DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().TTL(gomock.Any(), gomock.Any()).Return(time.Hour, nil)
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				wconn.EXPECT().Begin(gomock.Any()).Return(tx)
+				tx.EXPECT().Set(gomock.Any(), formatToSessionKey(""), gomock.Any(), gomock.Any()).Return(nil)
+				tx.EXPECT().SAdd(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(int64(0), nil)
+				tx.EXPECT().Expire(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(true, nil)
+				tx.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				tx.EXPECT().Exec(gomock.Any()).Return(errors.New(""))
 			},
 			cmd: func(repo *AuthRepository) error {
-				return repo.SaveSession(context.Background(), new(domain.Session))
+				return repo.SaveSession(context.Background(), &domain.Session{ExpiresAt: testExpiresAt})
 			},
 			exp: func(err error) {
 				require.Error(t, err)
@@ -110,7 +212,21 @@ func TestAuthRepository_SaveSession(t *testing.T) {
 func TestAuthRepository_FindSessionById(t *testing.T) {
 	tcs := map[string]authRepoSessionsTestCase{
 		"SUCCESS": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).DoAndReturn(json.Unmarshal)
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+			},
+			cmd: func(repo *AuthRepository) error {
+				_, err := repo.FindSessionById(context.Background(), testSessionId)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED session expired": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
 				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).Return(nil)
 				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(""), gomock.Any()).
 					Return(nil)
@@ -120,24 +236,38 @@ func TestAuthRepository_FindSessionById(t *testing.T) {
 				return err
 			},
 			exp: func(err error) {
-				require.NoError(t, err)
+				require.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		"FAILED session not found": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					Return(proxerr.New(cache.ErrKeyDoesNotExist, "redis: nil"))
+			},
+			cmd: func(repo *AuthRepository) error {
+				_, err := repo.FindSessionById(context.Background(), testSessionId)
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrNotFound)
 			},
 		},
 		"FAILED to find a session by id": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
-				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(""), gomock.Any()).
-					Return(errors.New(""))
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					Return(errors.New("i/o timeout"))
 			},
 			cmd: func(repo *AuthRepository) error {
-				_, err := repo.FindSessionById(context.Background(), "")
+				_, err := repo.FindSessionById(context.Background(), testSessionId)
 				return err
 			},
 			exp: func(err error) {
 				require.Error(t, err)
+				require.NotErrorIs(t, err, ErrNotFound)
 			},
 		},
 		"FAILED to unmarshal a session": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
 				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(""), gomock.Any()).Return(nil)
 				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).Return(errors.New(""))
 			},
@@ -158,11 +288,567 @@ func TestAuthRepository_FindSessionById(t *testing.T) {
 	}
 }
 
+func TestAuthRepository_FindSessionByRefreshToken(t *testing.T) {
+	tcs := map[string]authRepoSessionsTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().Get(gomock.Any(), formatToRefreshTokenIndexKey(testRefreshToken), gomock.Any()).
+					DoAndReturn(getReturns(testSessionId))
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).DoAndReturn(json.Unmarshal)
+			},
+			cmd: func(repo *AuthRepository) error {
+				session, err := repo.FindSessionByRefreshToken(context.Background(), testRefreshToken)
+				require.Equal(t, testSessionId, session.ID)
+				require.Equal(t, testUserId, session.User.ID)
+				require.Equal(t, testFingerprint, session.Fingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED index not found": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().Get(gomock.Any(), formatToRefreshTokenIndexKey(testRefreshToken), gomock.Any()).
+					Return(cache.ErrKeyDoesNotExist)
+			},
+			cmd: func(repo *AuthRepository) error {
+				_, err := repo.FindSessionByRefreshToken(context.Background(), testRefreshToken)
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		"FAILED session expired": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().Get(gomock.Any(), formatToRefreshTokenIndexKey(testRefreshToken), gomock.Any()).
+					DoAndReturn(getReturns(testSessionId))
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			cmd: func(repo *AuthRepository) error {
+				_, err := repo.FindSessionByRefreshToken(context.Background(), testRefreshToken)
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		"FAILED session not found": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().Get(gomock.Any(), formatToRefreshTokenIndexKey(testRefreshToken), gomock.Any()).
+					DoAndReturn(getReturns(testSessionId))
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					Return(cache.ErrKeyDoesNotExist)
+			},
+			cmd: func(repo *AuthRepository) error {
+				_, err := repo.FindSessionByRefreshToken(context.Background(), testRefreshToken)
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		"FAILED": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().Get(gomock.Any(), formatToRefreshTokenIndexKey(testRefreshToken), gomock.Any()).
+					Return(errors.New(""))
+			},
+			cmd: func(repo *AuthRepository) error {
+				_, err := repo.FindSessionByRefreshToken(context.Background(), testRefreshToken)
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+				require.NotErrorIs(t, err, ErrNotFound)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runSessionsTestCase(t, &tc)
+		})
+	}
+}
+
+func TestAuthRepository_FindSessionByFingerprint(t *testing.T) {
+	tcs := map[string]authRepoSessionsTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().Get(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint), gomock.Any()).
+					DoAndReturn(getReturns(testSessionId))
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).DoAndReturn(json.Unmarshal)
+			},
+			cmd: func(repo *AuthRepository) error {
+				session, err := repo.FindSessionByFingerprint(context.Background(), testUserId, testFingerprint)
+				require.Equal(t, testSessionId, session.ID)
+				require.Equal(t, testRefreshToken, session.RefreshToken)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED index not found": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().Get(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint), gomock.Any()).
+					Return(cache.ErrKeyDoesNotExist)
+			},
+			cmd: func(repo *AuthRepository) error {
+				_, err := repo.FindSessionByFingerprint(context.Background(), testUserId, testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runSessionsTestCase(t, &tc)
+		})
+	}
+}
+
+func TestAuthRepository_FindSessionsByUserId(t *testing.T) {
+	const staleSessionId = "0194fa03-3c4d-7e5f-8a6b-7c8d9e0f1a2b"
+
+	tcs := map[string]authRepoSessionsTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().SMembers(gomock.Any(), formatToUserSessionsIndexKey(testUserId)).
+					Return([]string{testSessionId}, nil)
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).DoAndReturn(json.Unmarshal)
+			},
+			cmd: func(repo *AuthRepository) error {
+				sessions, err := repo.FindSessionsByUserId(context.Background(), testUserId)
+				require.Len(t, sessions, 1)
+				require.Equal(t, testSessionId, sessions[0].ID)
+				require.Equal(t, testUserId, sessions[0].User.ID)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS removes stale session ids": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().SMembers(gomock.Any(), formatToUserSessionsIndexKey(testUserId)).
+					Return([]string{testSessionId, staleSessionId}, nil)
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(staleSessionId), gomock.Any()).
+					Return(cache.ErrKeyDoesNotExist)
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).DoAndReturn(json.Unmarshal)
+				conn.EXPECT().SRem(gomock.Any(), formatToUserSessionsIndexKey(testUserId), staleSessionId).
+					Return(int64(1), nil)
+			},
+			cmd: func(repo *AuthRepository) error {
+				sessions, err := repo.FindSessionsByUserId(context.Background(), testUserId)
+				require.Len(t, sessions, 1)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS no sessions": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().SMembers(gomock.Any(), formatToUserSessionsIndexKey(testUserId)).
+					Return([]string{}, nil)
+			},
+			cmd: func(repo *AuthRepository) error {
+				sessions, err := repo.FindSessionsByUserId(context.Background(), testUserId)
+				require.NotNil(t, sessions)
+				require.Empty(t, sessions)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().SMembers(gomock.Any(), formatToUserSessionsIndexKey(testUserId)).
+					Return(nil, errors.New(""))
+			},
+			cmd: func(repo *AuthRepository) error {
+				_, err := repo.FindSessionsByUserId(context.Background(), testUserId)
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runSessionsTestCase(t, &tc)
+		})
+	}
+}
+
+func TestAuthRepository_FindSessionsByFamilyId(t *testing.T) {
+	const staleSessionId = "0194fa03-3c4d-7e5f-8a6b-7c8d9e0f1a2b"
+
+	tcs := map[string]authRepoSessionsTestCase{
+		"SUCCESS removes stale session ids": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().SMembers(gomock.Any(), formatToFamilySessionsIndexKey(testSessionId)).
+					Return([]string{testSessionId, staleSessionId}, nil)
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				conn.EXPECT().Get(gomock.Any(), formatToSessionKey(staleSessionId), gomock.Any()).
+					Return(cache.ErrKeyDoesNotExist)
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).DoAndReturn(json.Unmarshal)
+				conn.EXPECT().SRem(gomock.Any(), formatToFamilySessionsIndexKey(testSessionId), staleSessionId).
+					Return(int64(1), nil)
+			},
+			cmd: func(repo *AuthRepository) error {
+				sessions, err := repo.FindSessionsByFamilyId(context.Background(), testSessionId)
+				require.Len(t, sessions, 1)
+				require.Equal(t, testSessionId, sessions[0].FamilyID)
+				require.Equal(t, testUserId, sessions[0].User.ID)
+				require.Equal(t, testRefreshToken, sessions[0].RefreshToken)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+				conn.EXPECT().SMembers(gomock.Any(), formatToFamilySessionsIndexKey(testSessionId)).
+					Return(nil, errors.New(""))
+			},
+			cmd: func(repo *AuthRepository) error {
+				_, err := repo.FindSessionsByFamilyId(context.Background(), testSessionId)
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runSessionsTestCase(t, &tc)
+		})
+	}
+}
+
+func TestAuthRepository_UpdateSessionById(t *testing.T) {
+	const rotatedRefreshToken = "rotatedRefreshToken"
+
+	tcs := map[string]authRepoSessionsTestCase{
+		"SUCCESS moves the refresh token index": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToSessionKey(testSessionId),
+					formatToUserSessionsIndexKey(testUserId)).DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).DoAndReturn(json.Unmarshal)
+				wconn.EXPECT().TTL(gomock.Any(), formatToUserSessionsIndexKey(testUserId)).Return(time.Hour, nil)
+				jsoner.EXPECT().Marshal(gomock.Any()).Return(nil, nil)
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				wconn.EXPECT().Begin(gomock.Any()).Return(tx)
+				gomock.InOrder(
+					tx.EXPECT().Set(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any(), gomock.Any()).Return(nil),
+					tx.EXPECT().Delete(gomock.Any(), formatToRefreshTokenIndexKey(testRefreshToken)).Return(int64(1), nil),
+					tx.EXPECT().SAdd(gomock.Any(), formatToUserSessionsIndexKey(testUserId), testSessionId).Return(int64(0), nil),
+					tx.EXPECT().Expire(gomock.Any(), formatToUserSessionsIndexKey(testUserId), time.Hour).Return(true, nil),
+					tx.EXPECT().SAdd(gomock.Any(), formatToFamilySessionsIndexKey(testSessionId), testSessionId).Return(int64(0), nil),
+					tx.EXPECT().Expire(gomock.Any(), formatToFamilySessionsIndexKey(testSessionId), ttlMatcher{}).Return(true, nil),
+					tx.EXPECT().Set(gomock.Any(), formatToRefreshTokenIndexKey(rotatedRefreshToken), testSessionId, gomock.Any()).Return(nil),
+					tx.EXPECT().Set(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint), testSessionId, gomock.Any()).Return(nil),
+					tx.EXPECT().Exec(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.UpdateSessionById(context.Background(), &domain.Session{
+					ID:           testSessionId,
+					User:         domain.User{ID: testUserId},
+					Fingerprint:  testFingerprint,
+					RefreshToken: rotatedRefreshToken,
+					ExpiresAt:    testExpiresAt,
+				})
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED updated session expired": {
+			reg: func(_ *gomock.Controller, _ *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.UpdateSessionById(context.Background(), &domain.Session{
+					ID:        testSessionId,
+					ExpiresAt: time.Now().Add(-time.Second),
+				})
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrSessionExpired)
+			},
+		},
+		"FAILED stored session expired": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToSessionKey(testSessionId),
+					formatToUserSessionsIndexKey("")).DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.UpdateSessionById(context.Background(), &domain.Session{
+					ID:        testSessionId,
+					ExpiresAt: testExpiresAt,
+				})
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		"FAILED session not found": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToSessionKey(testSessionId),
+					formatToUserSessionsIndexKey("")).DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					Return(cache.ErrKeyDoesNotExist)
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.UpdateSessionById(context.Background(), &domain.Session{
+					ID:        testSessionId,
+					ExpiresAt: testExpiresAt,
+				})
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		"FAILED to queue a command": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToSessionKey(testSessionId),
+					formatToUserSessionsIndexKey("")).DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).DoAndReturn(json.Unmarshal)
+				wconn.EXPECT().Watch(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint)).Return(nil)
+				wconn.EXPECT().Get(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint), gomock.Any()).
+					DoAndReturn(getReturns(testSessionId))
+				wconn.EXPECT().TTL(gomock.Any(), formatToUserSessionsIndexKey("")).Return(time.Hour, nil)
+				jsoner.EXPECT().Marshal(gomock.Any()).Return(nil, nil)
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				wconn.EXPECT().Begin(gomock.Any()).Return(tx)
+				tx.EXPECT().Set(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any(), gomock.Any()).
+					Return(errors.New(""))
+				tx.EXPECT().Discard(gomock.Any()).Return(nil)
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.UpdateSessionById(context.Background(), &domain.Session{
+					ID:        testSessionId,
+					ExpiresAt: testExpiresAt,
+				})
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runSessionsTestCase(t, &tc)
+		})
+	}
+}
+
+func TestAuthRepository_DeleteSessionById(t *testing.T) {
+	tcs := map[string]authRepoSessionsTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToSessionKey(testSessionId)).
+					DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).DoAndReturn(json.Unmarshal)
+				wconn.EXPECT().Watch(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint)).Return(nil)
+				wconn.EXPECT().Get(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint), gomock.Any()).
+					DoAndReturn(getReturns(testSessionId))
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				wconn.EXPECT().Begin(gomock.Any()).Return(tx)
+				gomock.InOrder(
+					tx.EXPECT().Delete(gomock.Any(), formatToSessionKey(testSessionId)).Return(int64(1), nil),
+					tx.EXPECT().SRem(gomock.Any(), formatToUserSessionsIndexKey(testUserId), testSessionId).Return(int64(1), nil),
+					tx.EXPECT().SRem(gomock.Any(), formatToFamilySessionsIndexKey(testSessionId), testSessionId).Return(int64(1), nil),
+					tx.EXPECT().Delete(gomock.Any(), formatToRefreshTokenIndexKey(testRefreshToken)).Return(int64(1), nil),
+					tx.EXPECT().Delete(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint)).Return(int64(1), nil),
+					tx.EXPECT().Exec(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.DeleteSessionById(context.Background(), testSessionId)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS keeps the fingerprint index of another session": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToSessionKey(testSessionId)).
+					DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).DoAndReturn(json.Unmarshal)
+				wconn.EXPECT().Watch(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint)).Return(nil)
+				wconn.EXPECT().Get(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint), gomock.Any()).
+					DoAndReturn(getReturns("another"))
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				wconn.EXPECT().Begin(gomock.Any()).Return(tx)
+				tx.EXPECT().Delete(gomock.Any(), formatToSessionKey(testSessionId)).Return(int64(1), nil)
+				tx.EXPECT().SRem(gomock.Any(), formatToUserSessionsIndexKey(testUserId), testSessionId).Return(int64(1), nil)
+				tx.EXPECT().SRem(gomock.Any(), formatToFamilySessionsIndexKey(testSessionId), testSessionId).Return(int64(1), nil)
+				tx.EXPECT().Delete(gomock.Any(), formatToRefreshTokenIndexKey(testRefreshToken)).Return(int64(1), nil)
+				tx.EXPECT().Exec(gomock.Any()).Return(nil)
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.DeleteSessionById(context.Background(), testSessionId)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS session not found": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, _ *_redisrepoMock.MockJSONer) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToSessionKey(testSessionId)).
+					DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					Return(cache.ErrKeyDoesNotExist)
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.DeleteSessionById(context.Background(), testSessionId)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS retries the transaction, if the watched keys were modified": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToSessionKey(testSessionId)).Times(2).
+					DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).Times(2).
+					DoAndReturn(getReturns(testRawSession))
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(json.Unmarshal)
+				wconn.EXPECT().Watch(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint)).Times(2).Return(nil)
+				wconn.EXPECT().Get(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint), gomock.Any()).Times(2).
+					DoAndReturn(getReturns(testSessionId))
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				wconn.EXPECT().Begin(gomock.Any()).Times(2).Return(tx)
+				tx.EXPECT().Delete(gomock.Any(), gomock.Any()).AnyTimes().Return(int64(1), nil)
+				tx.EXPECT().SRem(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(int64(1), nil)
+				gomock.InOrder(
+					tx.EXPECT().Exec(gomock.Any()).Return(proxerr.New(cache.ErrTxFailed, "")),
+					tx.EXPECT().Exec(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.DeleteSessionById(context.Background(), testSessionId)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator, jsoner *_redisrepoMock.MockJSONer) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), formatToSessionKey(testSessionId)).
+					DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Get(gomock.Any(), formatToSessionKey(testSessionId), gomock.Any()).
+					DoAndReturn(getReturns(testRawSession))
+				jsoner.EXPECT().Unmarshal(gomock.Any(), gomock.Any()).DoAndReturn(json.Unmarshal)
+				wconn.EXPECT().Watch(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint)).Return(nil)
+				wconn.EXPECT().Get(gomock.Any(), formatToFingerprintIndexKey(testUserId, testFingerprint), gomock.Any()).
+					DoAndReturn(getReturns(testSessionId))
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				wconn.EXPECT().Begin(gomock.Any()).Return(tx)
+				tx.EXPECT().Delete(gomock.Any(), gomock.Any()).AnyTimes().Return(int64(1), nil)
+				tx.EXPECT().SRem(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(int64(1), nil)
+				tx.EXPECT().Exec(gomock.Any()).Return(errors.New(""))
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.DeleteSessionById(context.Background(), testSessionId)
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runSessionsTestCase(t, &tc)
+		})
+	}
+}
+
+// ttlMatcher matches a positive expiration that
+// doesn't exceed the lifetime of the test sessions
+type ttlMatcher struct{}
+
+func (ttlMatcher) Matches(x any) bool {
+	ttl, ok := x.(time.Duration)
+	return ok && ttl > 0 && ttl <= time.Hour
+}
+
+func (ttlMatcher) String() string {
+	return "is a positive ttl not exceeding an hour"
+}
+
+// watchCalls imitates [cache.Watcher.Watch] that calls the function with the connection
+func watchCalls(conn cache.WatchConn) func(context.Context, func(cache.WatchConn) error, ...string) error {
+	return func(_ context.Context, fn func(cache.WatchConn) error, _ ...string) error {
+		return fn(conn)
+	}
+}
+
+// getReturns imitates [cache.Conn.Get] that scans the value into a string
+func getReturns(value string) func(_ context.Context, _ string, dest any) error {
+	return func(_ context.Context, _ string, dest any) error {
+		*dest.(*string) = value
+		return nil
+	}
+}
+
 func runSessionsTestCase(t *testing.T, tc *authRepoSessionsTestCase) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	sessionsConn := _cacheMock.NewMockConn(ctrl)
+	sessionsConn := _cacheMock.NewMockWatcher(ctrl)
 	idGen := _uuidMock.NewMockGenerator(ctrl)
 	jsoner := _redisrepoMock.NewMockJSONer(ctrl)
 	if tc.reg != nil {
@@ -189,22 +875,49 @@ func TestAuthRepository_SaveAccessTokenToWhiteList(t *testing.T) {
 
 	tcs := map[string]authRepositoryTestCase{
 		"SUCCESS": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
-				conn.EXPECT().Set(gomock.Any(), formatAccessTokenIntoCacheKey(token), token, expiration).Return(nil)
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				tx := _cacheMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx)
+				gomock.InOrder(
+					tx.EXPECT().Set(gomock.Any(), formatAccessTokenIntoCacheKey(token), testSessionId, expiration).Return(nil),
+					tx.EXPECT().SAdd(gomock.Any(), formatToSessionAccessTokensKey(testSessionId), hashToken(token)).Return(int64(1), nil),
+					tx.EXPECT().Expire(gomock.Any(), formatToSessionAccessTokensKey(testSessionId), expiration).Return(true, nil),
+					tx.EXPECT().Exec(gomock.Any()).Return(nil),
+				)
 			},
 			cmd: func(repo *AuthRepository) error {
-				return repo.SaveAccessTokenToWhitelist(context.Background(), token, expiration)
+				return repo.SaveAccessTokenToWhitelist(context.Background(), testSessionId, token, expiration)
 			},
 			exp: func(err error) {
 				require.NoError(t, err)
 			},
 		},
+		"FAILURE to queue a command": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				tx := _cacheMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx)
+				tx.EXPECT().Set(gomock.Any(), formatAccessTokenIntoCacheKey(token), testSessionId, expiration).
+					Return(errors.New(""))
+				tx.EXPECT().Discard(gomock.Any()).Return(nil)
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.SaveAccessTokenToWhitelist(context.Background(), testSessionId, token, expiration)
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
 		"FAILURE": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
-				conn.EXPECT().Set(gomock.Any(), formatAccessTokenIntoCacheKey(token), token, expiration).Return(errors.New(""))
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				tx := _cacheMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx)
+				tx.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				tx.EXPECT().SAdd(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
+				tx.EXPECT().Expire(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				tx.EXPECT().Exec(gomock.Any()).Return(errors.New(""))
 			},
 			cmd: func(repo *AuthRepository) error {
-				return repo.SaveAccessTokenToWhitelist(context.Background(), token, expiration)
+				return repo.SaveAccessTokenToWhitelist(context.Background(), testSessionId, token, expiration)
 			},
 			exp: func(err error) {
 				require.Error(t, err)
@@ -224,7 +937,7 @@ func TestAuthRepository_FindAccessTokenInWhiteList(t *testing.T) {
 
 	tcs := map[string]authRepositoryTestCase{
 		"SUCCESS": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
 				conn.EXPECT().Exists(gomock.Any(), formatAccessTokenIntoCacheKey(token)).
 					Return(int64(1), nil)
 			},
@@ -238,7 +951,7 @@ func TestAuthRepository_FindAccessTokenInWhiteList(t *testing.T) {
 			},
 		},
 		"FAILURE": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
 				conn.EXPECT().Exists(gomock.Any(), formatAccessTokenIntoCacheKey(token)).
 					Return(int64(0), errors.New(""))
 			},
@@ -252,7 +965,7 @@ func TestAuthRepository_FindAccessTokenInWhiteList(t *testing.T) {
 			},
 		},
 		"FAILURE not found": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
 				conn.EXPECT().Exists(gomock.Any(), formatAccessTokenIntoCacheKey(token)).
 					Return(int64(0), nil)
 			},
@@ -279,7 +992,7 @@ func TestAuthRepository_DeleteAccessTokenFromWhiteList(t *testing.T) {
 
 	tcs := map[string]authRepositoryTestCase{
 		"SUCCESS": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
 				conn.EXPECT().Delete(gomock.Any(), formatAccessTokenIntoCacheKey(token)).
 					Return(int64(1), nil)
 			},
@@ -291,7 +1004,7 @@ func TestAuthRepository_DeleteAccessTokenFromWhiteList(t *testing.T) {
 			},
 		},
 		"FAILURE": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
 				conn.EXPECT().Delete(gomock.Any(), formatAccessTokenIntoCacheKey(token)).
 					Return(int64(0), errors.New(""))
 			},
@@ -311,11 +1024,73 @@ func TestAuthRepository_DeleteAccessTokenFromWhiteList(t *testing.T) {
 	}
 }
 
+func TestAuthRepository_DeleteSessionAccessTokensFromWhitelist(t *testing.T) {
+	const token = "testAccessToken"
+
+	tcs := map[string]authRepositoryTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().SMembers(gomock.Any(), formatToSessionAccessTokensKey(testSessionId)).
+					Return([]string{hashToken(token)}, nil)
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx)
+				gomock.InOrder(
+					tx.EXPECT().Delete(gomock.Any(), formatAccessTokenIntoCacheKey(token)).Return(int64(1), nil),
+					tx.EXPECT().Delete(gomock.Any(), formatToSessionAccessTokensKey(testSessionId)).Return(int64(1), nil),
+					tx.EXPECT().Exec(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.DeleteSessionAccessTokensFromWhitelist(context.Background(), testSessionId)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILURE to find access tokens": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().SMembers(gomock.Any(), formatToSessionAccessTokensKey(testSessionId)).
+					Return(nil, errors.New(""))
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.DeleteSessionAccessTokensFromWhitelist(context.Background(), testSessionId)
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"FAILURE": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().SMembers(gomock.Any(), formatToSessionAccessTokensKey(testSessionId)).
+					Return([]string{}, nil)
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx)
+				tx.EXPECT().Delete(gomock.Any(), formatToSessionAccessTokensKey(testSessionId)).Return(int64(0), nil)
+				tx.EXPECT().Exec(gomock.Any()).Return(errors.New(""))
+			},
+			cmd: func(repo *AuthRepository) error {
+				return repo.DeleteSessionAccessTokensFromWhitelist(context.Background(), testSessionId)
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runAccessTokensWhiteListTestCase(t, &tc)
+		})
+	}
+}
+
 func runAccessTokensWhiteListTestCase(t *testing.T, tc *authRepositoryTestCase) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	whiteListConn := _cacheMock.NewMockConn(ctrl)
+	whiteListConn := _cacheMock.NewMockWatcher(ctrl)
 	idGen := _uuidMock.NewMockGenerator(ctrl)
 	if tc.reg != nil {
 		tc.reg(ctrl, whiteListConn, idGen)
@@ -336,31 +1111,131 @@ func runAccessTokensWhiteListTestCase(t *testing.T, tc *authRepositoryTestCase)
 
 func TestAuthRepository_SaveRefreshTokenToBlackList(t *testing.T) {
 	const token = "testRefreshToken"
-	const expiration = time.Duration(-1)
+	const expiration = time.Minute
+	key := formatRefreshTokenIntoCacheKey(token)
 
 	tcs := map[string]authRepositoryTestCase{
 		"SUCCESS": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
-				conn.EXPECT().Set(gomock.Any(), formatRefreshTokenIntoCacheKey(token), token, expiration).
-					Return(nil)
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), key).DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Exists(gomock.Any(), key).Return(int64(0), nil)
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				wconn.EXPECT().Begin(gomock.Any()).Return(tx)
+				tx.EXPECT().Set(gomock.Any(), key, testSessionId, expiration).Return(nil)
+				tx.EXPECT().Exec(gomock.Any()).Return(nil)
 			},
 			cmd: func(repo *AuthRepository) error {
-				return repo.SaveRefreshTokenToBlacklist(context.Background(), token, expiration)
+				saved, err := repo.SaveRefreshTokenToBlacklist(context.Background(), token, testSessionId, expiration)
+				require.True(t, saved)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS already blacklisted": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), key).DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Exists(gomock.Any(), key).Return(int64(1), nil)
+			},
+			cmd: func(repo *AuthRepository) error {
+				saved, err := repo.SaveRefreshTokenToBlacklist(context.Background(), token, testSessionId, expiration)
+				require.False(t, saved)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS blacklisted concurrently": {
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), key).DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Exists(gomock.Any(), key).Return(int64(0), nil)
+
+				tx := _cacheMock.NewMockTx(ctrl)
+				wconn.EXPECT().Begin(gomock.Any()).Return(tx)
+				tx.EXPECT().Set(gomock.Any(), key, testSessionId, expiration).Return(nil)
+				tx.EXPECT().Exec(gomock.Any()).Return(proxerr.New(cache.ErrTxFailed, ""))
+			},
+			cmd: func(repo *AuthRepository) error {
+				saved, err := repo.SaveRefreshTokenToBlacklist(context.Background(), token, testSessionId, expiration)
+				require.False(t, saved)
+				return err
 			},
 			exp: func(err error) {
 				require.NoError(t, err)
 			},
 		},
 		"FAILURE": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
-				conn.EXPECT().Set(gomock.Any(), formatRefreshTokenIntoCacheKey(token), token, expiration).
+			reg: func(ctrl *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				wconn := _cacheMock.NewMockWatchConn(ctrl)
+				conn.EXPECT().Watch(gomock.Any(), gomock.Any(), key).DoAndReturn(watchCalls(wconn))
+				wconn.EXPECT().Exists(gomock.Any(), key).Return(int64(0), errors.New(""))
+			},
+			cmd: func(repo *AuthRepository) error {
+				_, err := repo.SaveRefreshTokenToBlacklist(context.Background(), token, testSessionId, expiration)
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runRefreshTokensBlackListTestCase(t, &tc)
+		})
+	}
+}
+
+func TestAuthRepository_FindRefreshTokenFamilyInBlacklist(t *testing.T) {
+	const token = "testRefreshToken"
+
+	tcs := map[string]authRepositoryTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().Get(gomock.Any(), formatRefreshTokenIntoCacheKey(token), gomock.Any()).
+					DoAndReturn(getReturns(testSessionId))
+			},
+			cmd: func(repo *AuthRepository) error {
+				familyId, err := repo.FindRefreshTokenFamilyInBlacklist(context.Background(), token)
+				require.Equal(t, testSessionId, familyId)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILURE not found": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().Get(gomock.Any(), formatRefreshTokenIntoCacheKey(token), gomock.Any()).
+					Return(cache.ErrKeyDoesNotExist)
+			},
+			cmd: func(repo *AuthRepository) error {
+				_, err := repo.FindRefreshTokenFamilyInBlacklist(context.Background(), token)
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		"FAILURE": {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
+				conn.EXPECT().Get(gomock.Any(), formatRefreshTokenIntoCacheKey(token), gomock.Any()).
 					Return(errors.New(""))
 			},
 			cmd: func(repo *AuthRepository) error {
-				return repo.SaveRefreshTokenToBlacklist(context.Background(), token, expiration)
+				_, err := repo.FindRefreshTokenFamilyInBlacklist(context.Background(), token)
+				return err
 			},
 			exp: func(err error) {
 				require.Error(t, err)
+				require.NotErrorIs(t, err, ErrNotFound)
 			},
 		},
 	}
@@ -377,7 +1252,7 @@ func TestAuthRepository_FindRefreshTokenInBlackList(t *testing.T) {
 
 	tcs := map[string]authRepositoryTestCase{
 		"SUCCESS": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
 				conn.EXPECT().Exists(gomock.Any(), formatRefreshTokenIntoCacheKey(token)).
 					Return(int64(1), nil)
 			},
@@ -391,7 +1266,7 @@ func TestAuthRepository_FindRefreshTokenInBlackList(t *testing.T) {
 			},
 		},
 		"FAILURE": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
 				conn.EXPECT().Exists(gomock.Any(), formatRefreshTokenIntoCacheKey(token)).
 					Return(int64(0), errors.New(""))
 			},
@@ -405,7 +1280,7 @@ func TestAuthRepository_FindRefreshTokenInBlackList(t *testing.T) {
 			},
 		},
 		"FAILURE not found": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
 				conn.EXPECT().Exists(gomock.Any(), formatRefreshTokenIntoCacheKey(token)).
 					Return(int64(0), nil)
 			},
@@ -432,7 +1307,7 @@ func TestAuthRepository_DeleteRefreshTokenFromBlackList(t *testing.T) {
 
 	tcs := map[string]authRepositoryTestCase{
 		"SUCCESS": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
 				conn.EXPECT().Delete(gomock.Any(), formatRefreshTokenIntoCacheKey(token)).
 					Return(int64(1), nil)
 			},
@@ -444,7 +1319,7 @@ func TestAuthRepository_DeleteRefreshTokenFromBlackList(t *testing.T) {
 			},
 		},
 		"FAILURE": {
-			reg: func(_ *gomock.Controller, conn *_cacheMock.MockConn, _ *_uuidMock.MockGenerator) {
+			reg: func(_ *gomock.Controller, conn *_cacheMock.MockWatcher, _ *_uuidMock.MockGenerator) {
 				conn.EXPECT().Delete(gomock.Any(), formatRefreshTokenIntoCacheKey(token)).
 					Return(int64(0), errors.New(""))
 			},
@@ -468,7 +1343,7 @@ func runRefreshTokensBlackListTestCase(t *testing.T, tc *authRepositoryTestCase)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	blackListConn := _cacheMock.NewMockConn(ctrl)
+	blackListConn := _cacheMock.NewMockWatcher(ctrl)
 	idGen := _uuidMock.NewMockGenerator(ctrl)
 	if tc.reg != nil {
 		tc.reg(ctrl, blackListConn, idGen)
@@ -486,3 +1361,154 @@ func runRefreshTokensBlackListTestCase(t *testing.T, tc *authRepositoryTestCase)
 		tc.exp(err)
 	}
 }
+
+// TestAuthRepository_Memory runs the session lifecycle against the in-memory
+// cache, so that the indexes are checked by their behaviour
+func TestAuthRepository_Memory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	conn := memory.New(logger, nil)
+
+	idGen := _uuidMock.NewMockGenerator(ctrl)
+	idGen.EXPECT().NewV7().Return(testSessionId, nil)
+	repo := NewAuthRepository(conn, conn, conn, logger, idGen)
+
+	session := domain.Session{
+		User:         domain.User{ID: testUserId},
+		Fingerprint:  testFingerprint,
+		RefreshToken: testRefreshToken,
+		ExpiresAt:    testExpiresAt,
+	}
+	require.NoError(t, repo.SaveSession(ctx, &session))
+
+	found, err := repo.FindSessionByRefreshToken(ctx, testRefreshToken)
+	require.NoError(t, err)
+	require.Equal(t, testSessionId, found.ID)
+
+	found, err = repo.FindSessionByFingerprint(ctx, testUserId, testFingerprint)
+	require.NoError(t, err)
+	require.Equal(t, testSessionId, found.ID)
+
+	sessions, err := repo.FindSessionsByUserId(ctx, testUserId)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	// The user index expires with the session
+	ttl, err := conn.TTL(ctx, formatToUserSessionsIndexKey(testUserId))
+	require.NoError(t, err)
+	require.True(t, ttlMatcher{}.Matches(ttl))
+
+	// The old refresh token index is moved to the new token
+	session.RefreshToken = "newRefreshToken"
+	require.NoError(t, repo.UpdateSessionById(ctx, &session))
+
+	_, err = repo.FindSessionByRefreshToken(ctx, testRefreshToken)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	found, err = repo.FindSessionByRefreshToken(ctx, session.RefreshToken)
+	require.NoError(t, err)
+	require.Equal(t, testSessionId, found.ID)
+
+	require.NoError(t, repo.SaveAccessTokenToWhitelist(ctx, testSessionId, "accessToken", time.Minute))
+
+	// The indexes and the whitelist share the database, but only the sessions are scanned
+	sessions, err = repo.FindAllSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	// Neither of the tokens is stored in the key names
+	it := conn.ScanKeys(ctx, "*", sessionsScanCount)
+	for it.Next(ctx) {
+		require.NotContains(t, it.Val(), "accessToken")
+		require.NotContains(t, it.Val(), session.RefreshToken)
+	}
+	require.NoError(t, it.Err())
+
+	require.NoError(t, repo.DeleteSessionAccessTokensFromWhitelist(ctx, testSessionId))
+
+	ok, err := repo.FindAccessTokenInWhitelist(ctx, "accessToken")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, repo.DeleteSessionById(ctx, testSessionId))
+
+	_, err = repo.FindSessionById(ctx, testSessionId)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = repo.FindSessionByFingerprint(ctx, testUserId, testFingerprint)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	sessions, err = repo.FindSessionsByUserId(ctx, testUserId)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+
+	// Only the first of the concurrent rotations blacklists the token
+	saved, err := repo.SaveRefreshTokenToBlacklist(ctx, testRefreshToken, testSessionId, time.Minute)
+	require.NoError(t, err)
+	require.True(t, saved)
+
+	saved, err = repo.SaveRefreshTokenToBlacklist(ctx, testRefreshToken, "", time.Minute)
+	require.NoError(t, err)
+	require.False(t, saved)
+
+	familyId, err := repo.FindRefreshTokenFamilyInBlacklist(ctx, testRefreshToken)
+	require.NoError(t, err)
+	require.Equal(t, testSessionId, familyId)
+}
+
+// TestAuthRepository_MemorySharedFingerprint checks that the users behind
+// the same address and browser don't overwrite each other's fingerprint index
+func TestAuthRepository_MemorySharedFingerprint(t *testing.T) {
+	const (
+		otherSessionId = "0194fa03-3c4d-7e5f-9a6b-7c8d9e0f1a2b"
+		otherUserId    = "0194fa04-4d5e-7f6a-8b7c-8d9e0f1a2b3c"
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	conn := memory.New(logger, nil)
+
+	idGen := _uuidMock.NewMockGenerator(ctrl)
+	gomock.InOrder(
+		idGen.EXPECT().NewV7().Return(testSessionId, nil),
+		idGen.EXPECT().NewV7().Return(otherSessionId, nil),
+	)
+	repo := NewAuthRepository(conn, conn, conn, logger, idGen)
+
+	require.NoError(t, repo.SaveSession(ctx, &domain.Session{
+		User:         domain.User{ID: testUserId},
+		Fingerprint:  testFingerprint,
+		RefreshToken: testRefreshToken,
+		ExpiresAt:    testExpiresAt,
+	}))
+	require.NoError(t, repo.SaveSession(ctx, &domain.Session{
+		User:         domain.User{ID: otherUserId},
+		Fingerprint:  testFingerprint,
+		RefreshToken: "otherRefreshToken",
+		ExpiresAt:    testExpiresAt,
+	}))
+
+	found, err := repo.FindSessionByFingerprint(ctx, testUserId, testFingerprint)
+	require.NoError(t, err)
+	require.Equal(t, testSessionId, found.ID)
+
+	found, err = repo.FindSessionByFingerprint(ctx, otherUserId, testFingerprint)
+	require.NoError(t, err)
+	require.Equal(t, otherSessionId, found.ID)
+
+	// Deleting the session of one user keeps the index of the other
+	require.NoError(t, repo.DeleteSessionById(ctx, otherSessionId))
+
+	_, err = repo.FindSessionByFingerprint(ctx, otherUserId, testFingerprint)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	found, err = repo.FindSessionByFingerprint(ctx, testUserId, testFingerprint)
+	require.NoError(t, err)
+	require.Equal(t, testSessionId, found.ID)
+}
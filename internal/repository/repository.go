@@ -36,21 +36,35 @@ type UserRepository interface {
 	DeleteById(ctx context.Context, id string) error
 }
 
+type IdeaRepository interface {
+	Repository
+	Save(ctx context.Context, idea *domain.Idea) error
+	FindById(ctx context.Context, id string) (domain.Idea, error)
+	FindAll(ctx context.Context) ([]domain.Idea, error)
+	FindByUserId(ctx context.Context, userId string) ([]domain.Idea, error)
+	UpdateById(ctx context.Context, idea *domain.Idea) error
+	DeleteById(ctx context.Context, id string) error
+	DeleteByUserId(ctx context.Context, userId string) error
+}
+
 type AuthRepository interface {
 	SaveSession(ctx context.Context, session *domain.Session) error
 	FindSessionById(ctx context.Context, id string) (domain.Session, error)
 	FindSessionByRefreshToken(ctx context.Context, refreshToken string) (domain.Session, error)
-	FindSessionByFingerprint(ctx context.Context, fp domain.Fingerprint) (domain.Session, error)
+	FindSessionByFingerprint(ctx context.Context, userId string, fp domain.Fingerprint) (domain.Session, error)
 	FindAllSessions(ctx context.Context) ([]domain.Session, error)
 	FindSessionsByUserId(ctx context.Context, userId string) ([]domain.Session, error)
+	FindSessionsByFamilyId(ctx context.Context, familyId string) ([]domain.Session, error)
 	UpdateSessionById(ctx context.Context, session *domain.Session) error
 	DeleteSessionById(ctx context.Context, id string) error
 
-	SaveAccessTokenToWhitelist(ctx context.Context, accessToken string, expiration time.Duration) error
+	SaveAccessTokenToWhitelist(ctx context.Context, sessionId, accessToken string, expiration time.Duration) error
 	FindAccessTokenInWhitelist(ctx context.Context, accessToken string) (bool, error)
 	DeleteAccessTokenFromWhitelist(ctx context.Context, accessToken string) error
+	DeleteSessionAccessTokensFromWhitelist(ctx context.Context, sessionId string) error
 
-	SaveRefreshTokenToBlacklist(ctx context.Context, refreshToken string, expiration time.Duration) error
+	SaveRefreshTokenToBlacklist(ctx context.Context, refreshToken, familyId string, expiration time.Duration) (bool, error)
 	FindRefreshTokenInBlacklist(ctx context.Context, refreshToken string) (bool, error)
+	FindRefreshTokenFamilyInBlacklist(ctx context.Context, refreshToken string) (string, error)
 	DeleteRefreshTokenFromBlacklist(ctx context.Context, refreshToken string) error
 }
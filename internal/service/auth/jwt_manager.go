@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"github.com/adanyl0v/pocket-ideas/pkg/jwt"
+	"time"
+)
+
+// JWTManager issues access tokens as JWTs, which carry the user id
+// in the "sub" claim and the session id in the "sid" claim
+type JWTManager struct {
+	manager *jwt.Manager
+}
+
+func NewJWTManager(manager *jwt.Manager) *JWTManager {
+	return &JWTManager{manager: manager}
+}
+
+func (i *JWTManager) IssueAccessToken(session *domain.Session, expiresAt time.Time) (string, error) {
+	return i.manager.Sign(&jwt.Claims{
+		Subject:   session.User.ID,
+		SessionID: session.ID,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// ParseAccessToken verifies the token and returns the session it is bound to.
+// Only the ids of the session and its user are set
+func (i *JWTManager) ParseAccessToken(token string) (domain.Session, error) {
+	claims, err := i.manager.Verify(token)
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	return domain.Session{
+		ID:   claims.SessionID,
+		User: domain.User{ID: claims.Subject},
+	}, nil
+}
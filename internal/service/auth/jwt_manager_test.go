@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"github.com/adanyl0v/pocket-ideas/pkg/jwt"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestJWTManager(t *testing.T) {
+	key, err := jwt.NewHMACKey("key", []byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+
+	keys, err := jwt.NewKeySet(key)
+	require.NoError(t, err)
+
+	manager := NewJWTManager(jwt.NewManager(keys, &jwt.Config{Issuer: "test"}))
+	session := domain.Session{
+		ID:   "0194f9b2-5e6f-7a8b-9c0d-1e2f3a4b5c6d",
+		User: domain.User{ID: "0194f9a1-1b2c-7d3e-8f4a-5b6c7d8e9f0a"},
+	}
+
+	token, err := manager.IssueAccessToken(&session, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	parsed, err := manager.ParseAccessToken(token)
+	require.NoError(t, err)
+	require.Equal(t, session.ID, parsed.ID)
+	require.Equal(t, session.User.ID, parsed.User.ID)
+
+	token, err = manager.IssueAccessToken(&session, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	_, err = manager.ParseAccessToken(token)
+	require.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
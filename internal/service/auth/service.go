@@ -0,0 +1,578 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"github.com/adanyl0v/pocket-ideas/internal/repository"
+	pgrepo "github.com/adanyl0v/pocket-ideas/internal/repository/postgres"
+	redisrepo "github.com/adanyl0v/pocket-ideas/internal/repository/redis"
+	postgresdb "github.com/adanyl0v/pocket-ideas/pkg/database/postgres/pgx"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	passwordhasher "github.com/adanyl0v/pocket-ideas/pkg/password"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrSessionExpired      = errors.New("session expired")
+	ErrFingerprintMismatch = errors.New("fingerprint mismatch")
+	ErrInvalidAccessToken  = errors.New("invalid access token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+)
+
+// refreshTokenSize is the number of random bytes in a refresh token
+const refreshTokenSize = 32
+
+// sessionsLockFormat will be interpreted as "sessions:<user_id>"
+const sessionsLockFormat = "sessions:%s"
+
+// dummyPassword is hashed once to be verified on the logins with an unknown email
+const dummyPassword = "dummy password"
+
+type (
+	PasswordHasher interface {
+		Hash(password string) (string, error)
+		Verify(password, hash string) (bool, error)
+
+		// NeedsRehash reports whether the hash was computed with outdated parameters
+		NeedsRehash(hash string) bool
+	}
+
+	AccessTokenManager interface {
+		// IssueAccessToken returns a token bound to the session, which
+		// must not be used after the expiration time
+		IssueAccessToken(session *domain.Session, expiresAt time.Time) (string, error)
+
+		// ParseAccessToken verifies the token and returns the session it is bound to
+		ParseAccessToken(token string) (domain.Session, error)
+	}
+
+	// Locker serializes the calls by the name across all the instances
+	Locker interface {
+		Do(ctx context.Context, name string, fn func(ctx context.Context, token int64) error) error
+	}
+)
+
+type Config struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// MaxSessionsPerUser limits the number of concurrent sessions of
+	// a user. The limit is disabled if the value is not positive
+	MaxSessionsPerUser int
+}
+
+type Tokens struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+type Service struct {
+	userRepo     repository.UserRepository
+	authRepo     repository.AuthRepository
+	hasher       PasswordHasher
+	accessTokens AccessTokenManager
+	locker       Locker
+	logger       log.Logger
+	config       Config
+
+	// dummyHash is computed by the current hasher on the first
+	// login with an unknown email, so that it has the same cost
+	dummyHash func() (string, error)
+}
+
+func NewService(
+	userRepo repository.UserRepository,
+	authRepo repository.AuthRepository,
+	hasher PasswordHasher,
+	accessTokens AccessTokenManager,
+	locker Locker,
+	logger log.Logger,
+	config *Config,
+) *Service {
+	return &Service{
+		userRepo:     userRepo,
+		authRepo:     authRepo,
+		hasher:       hasher,
+		accessTokens: accessTokens,
+		locker:       locker,
+		logger:       logger,
+		config:       *config,
+		dummyHash: sync.OnceValues(func() (string, error) {
+			return hasher.Hash(dummyPassword)
+		}),
+	}
+}
+
+// Register saves the user with the hashed password
+func (s *Service) Register(ctx context.Context, user *domain.User) error {
+	logger := s.logger.With(log.Fields{"email": user.Email})
+
+	hash, err := s.hasher.Hash(user.Password)
+	if err != nil {
+		logger.WithError(err).Error("failed to hash a password")
+		return err
+	}
+
+	user.Password = hash
+	if err = s.userRepo.Save(ctx, user); err != nil {
+		logger.WithError(err).Error("failed to register a user")
+		return err
+	}
+
+	logger.With(log.Fields{"id": user.ID}).Debug("registered a user")
+	return nil
+}
+
+// Login creates a new session bound to the fingerprint. A session that
+// was previously created by the same user with the same fingerprint is revoked.
+// If the user has reached the sessions limit, the least recently used
+// sessions are revoked to make room for the new one. The sessions of the user
+// are changed under a lock, so that the concurrent logins can't exceed the limit
+func (s *Service) Login(ctx context.Context, email, password string, fp domain.Fingerprint) (Tokens, error) {
+	logger := s.logger.With(log.Fields{
+		"email":       email,
+		"fingerprint": fp,
+	})
+
+	// The user is read from the primary, so that a registration, a changed
+	// password or a rehash takes effect at once, even if the replicas lag
+	user, err := s.userRepo.FindByEmail(postgresdb.WithPrimary(ctx), email)
+	if err != nil {
+		if errors.Is(err, pgrepo.ErrUserNotFound) {
+			s.verifyDummyPassword(password)
+			logger.Debug("failed to login with an unknown email")
+			return Tokens{}, ErrInvalidCredentials
+		}
+
+		logger.WithError(err).Error("failed to find a user by email")
+		return Tokens{}, err
+	}
+
+	ok, err := s.hasher.Verify(password, user.Password)
+	if err != nil {
+		// The stored hash is a legacy one, which can't be verified, so
+		// the user has to reset the password rather than see an error
+		if errors.Is(err, passwordhasher.ErrUnsupportedAlgorithm) {
+			logger.With(log.Fields{"user_id": user.ID}).WithError(err).
+				Warn("failed to login with a password hash of an unsupported algorithm")
+			return Tokens{}, ErrInvalidCredentials
+		}
+
+		logger.WithError(err).Error("failed to verify a password")
+		return Tokens{}, err
+	}
+	if !ok {
+		logger.Debug("failed to login with an invalid password")
+		return Tokens{}, ErrInvalidCredentials
+	}
+
+	if s.hasher.NeedsRehash(user.Password) {
+		s.rehashPassword(ctx, &user, password)
+	}
+
+	var tokens Tokens
+	err = s.locker.Do(ctx, fmt.Sprintf(sessionsLockFormat, user.ID), func(ctx context.Context, _ int64) error {
+		if err := s.revokeSessionByFingerprint(ctx, user.ID, fp); err != nil {
+			return err
+		}
+
+		if err := s.evictSessions(ctx, user.ID); err != nil {
+			return err
+		}
+
+		var err error
+		tokens, err = s.createSession(ctx, &user, fp)
+		return err
+	})
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	logger.With(log.Fields{"user_id": user.ID}).Debug("logged in")
+	return tokens, nil
+}
+
+// Refresh rotates the refresh token of the session and issues a new access
+// token. The old refresh token is blacklisted until the session expiration.
+// A rotated refresh token may have been stolen, so its reuse revokes the
+// whole family of the token. Of the concurrent refreshes with the same
+// token only the first one succeeds, the rest are treated as its reuse
+func (s *Service) Refresh(ctx context.Context, refreshToken string, fp domain.Fingerprint) (Tokens, error) {
+	logger := s.logger.With(log.Fields{"fingerprint": fp})
+
+	familyId, err := s.authRepo.FindRefreshTokenFamilyInBlacklist(ctx, refreshToken)
+	if err == nil {
+		return Tokens{}, s.rejectBlacklistedRefreshToken(ctx, logger, familyId)
+	}
+	if !errors.Is(err, redisrepo.ErrNotFound) {
+		return Tokens{}, err
+	}
+
+	session, err := s.findSessionByRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return Tokens{}, err
+	}
+	logger = logger.With(log.Fields{
+		"session_id": session.ID,
+		"user_id":    session.User.ID,
+	})
+
+	now := time.Now().UTC()
+	if !now.Before(session.ExpiresAt) {
+		if err = s.authRepo.DeleteSessionById(ctx, session.ID); err != nil {
+			return Tokens{}, err
+		}
+
+		logger.Debug("deleted an expired session")
+		return Tokens{}, ErrSessionExpired
+	}
+
+	if session.Fingerprint != fp {
+		if err = s.revokeSession(ctx, &session); err != nil {
+			return Tokens{}, err
+		}
+
+		logger.With(log.Fields{
+			"session_fingerprint": session.Fingerprint,
+		}).Warn("revoked a session refreshed with a different fingerprint")
+		return Tokens{}, ErrFingerprintMismatch
+	}
+
+	rotated, err := s.authRepo.SaveRefreshTokenToBlacklist(ctx, refreshToken, session.FamilyID,
+		session.ExpiresAt.Sub(now))
+	if err != nil {
+		return Tokens{}, err
+	}
+	if !rotated {
+		// The token was blacklisted after the check above
+		if familyId, err = s.authRepo.FindRefreshTokenFamilyInBlacklist(ctx, refreshToken); err != nil {
+			return Tokens{}, err
+		}
+
+		return Tokens{}, s.rejectBlacklistedRefreshToken(ctx, logger, familyId)
+	}
+
+	session.RefreshToken, err = newRefreshToken()
+	if err != nil {
+		logger.WithError(err).Error("failed to generate a refresh token")
+		return Tokens{}, err
+	}
+
+	session.ExpiresAt = now.Add(s.config.RefreshTokenTTL)
+	if err = s.authRepo.UpdateSessionById(ctx, &session); err != nil {
+		return Tokens{}, err
+	}
+
+	tokens, err := s.issueTokens(ctx, &session)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	logger.Debug("refreshed a session")
+	return tokens, nil
+}
+
+// Authenticate returns the session of the access token. Only the ids of
+// the session and its user are set. The token must be whitelisted, so
+// the tokens of the revoked sessions are rejected before they expire
+func (s *Service) Authenticate(ctx context.Context, accessToken string) (domain.Session, error) {
+	session, err := s.accessTokens.ParseAccessToken(accessToken)
+	if err != nil {
+		s.logger.WithError(err).Debug("failed to parse an access token")
+		return domain.Session{}, proxerr.New(ErrInvalidAccessToken, err.Error())
+	}
+
+	whitelisted, err := s.authRepo.FindAccessTokenInWhitelist(ctx, accessToken)
+	if err != nil {
+		return domain.Session{}, err
+	}
+	if !whitelisted {
+		s.logger.With(log.Fields{
+			"session_id": session.ID,
+			"user_id":    session.User.ID,
+		}).Debug("attempted to use a revoked access token")
+		return domain.Session{}, ErrInvalidAccessToken
+	}
+
+	return session, nil
+}
+
+// Logout revokes the session of the refresh token. The access token
+// is removed from the whitelist if it is not empty
+func (s *Service) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	session, err := s.findSessionByRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	if err = s.revokeSession(ctx, &session); err != nil {
+		return err
+	}
+
+	if accessToken != "" {
+		if err = s.authRepo.DeleteAccessTokenFromWhitelist(ctx, accessToken); err != nil {
+			return err
+		}
+	}
+
+	s.logger.With(log.Fields{
+		"session_id": session.ID,
+		"user_id":    session.User.ID,
+	}).Debug("logged out")
+	return nil
+}
+
+// LogoutAll revokes every session of the user and
+// removes their access tokens from the whitelist
+func (s *Service) LogoutAll(ctx context.Context, userId string) error {
+	logger := s.logger.With(log.Fields{"user_id": userId})
+
+	sessions, err := s.authRepo.FindSessionsByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	for i := range sessions {
+		if err = s.revokeSessionWithAccessTokens(ctx, &sessions[i]); err != nil {
+			return err
+		}
+	}
+
+	logger.Debug("logged out of all sessions")
+	return nil
+}
+
+// verifyDummyPassword takes as long as the verification of a real password,
+// so that the response time doesn't tell whether the email is registered
+func (s *Service) verifyDummyPassword(password string) {
+	hash, err := s.dummyHash()
+	if err == nil {
+		_, err = s.hasher.Verify(password, hash)
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("failed to verify a dummy password")
+	}
+}
+
+// rehashPassword replaces the password hash of the user with the one computed
+// with the current parameters. Failures are not fatal, as the outdated hash
+// is still valid, so the rehash is retried on the next login
+func (s *Service) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	logger := s.logger.With(log.Fields{"user_id": user.ID})
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		logger.WithError(err).Error("failed to rehash a password")
+		return
+	}
+
+	updated := *user
+	updated.Password = hash
+	if err = s.userRepo.UpdateById(ctx, &updated); err != nil {
+		logger.WithError(err).Error("failed to update a rehashed password")
+		return
+	}
+
+	*user = updated
+	logger.Debug("rehashed a password")
+}
+
+func (s *Service) createSession(ctx context.Context, user *domain.User, fp domain.Fingerprint) (Tokens, error) {
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate a refresh token")
+		return Tokens{}, err
+	}
+
+	session := domain.Session{
+		User:         *user,
+		Fingerprint:  fp,
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().UTC().Add(s.config.RefreshTokenTTL),
+	}
+	if err = s.authRepo.SaveSession(ctx, &session); err != nil {
+		return Tokens{}, err
+	}
+
+	return s.issueTokens(ctx, &session)
+}
+
+func (s *Service) issueTokens(ctx context.Context, session *domain.Session) (Tokens, error) {
+	expiresAt := time.Now().UTC().Add(s.config.AccessTokenTTL)
+
+	accessToken, err := s.accessTokens.IssueAccessToken(session, expiresAt)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to issue an access token")
+		return Tokens{}, err
+	}
+
+	if err = s.authRepo.SaveAccessTokenToWhitelist(ctx, session.ID, accessToken, s.config.AccessTokenTTL); err != nil {
+		return Tokens{}, err
+	}
+
+	return Tokens{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  expiresAt,
+		RefreshToken:          session.RefreshToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+func (s *Service) findSessionByRefreshToken(ctx context.Context, refreshToken string) (domain.Session, error) {
+	session, err := s.authRepo.FindSessionByRefreshToken(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, redisrepo.ErrNotFound) {
+			return domain.Session{}, proxerr.New(ErrInvalidRefreshToken, err.Error())
+		}
+
+		return domain.Session{}, err
+	}
+
+	session.RefreshToken = refreshToken
+	return session, nil
+}
+
+// revokeSessionByFingerprint revokes the session of the user with the same fingerprint, if any
+func (s *Service) revokeSessionByFingerprint(ctx context.Context, userId string, fp domain.Fingerprint) error {
+	session, err := s.authRepo.FindSessionByFingerprint(ctx, userId, fp)
+	if err != nil {
+		if errors.Is(err, redisrepo.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	session.Fingerprint = fp
+	return s.revokeSession(ctx, &session)
+}
+
+// evictSessions revokes the least recently used sessions of the user and their
+// access tokens, so that a new session doesn't exceed [Config.MaxSessionsPerUser].
+// It must be called under the lock of the user
+func (s *Service) evictSessions(ctx context.Context, userId string) error {
+	if s.config.MaxSessionsPerUser <= 0 {
+		return nil
+	}
+
+	sessions, err := s.authRepo.FindSessionsByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	excess := len(sessions) - s.config.MaxSessionsPerUser + 1
+	if excess <= 0 {
+		return nil
+	}
+
+	slices.SortFunc(sessions, func(a, b domain.Session) int {
+		return lastUsedAt(&a).Compare(lastUsedAt(&b))
+	})
+
+	for i := range sessions[:excess] {
+		if err = s.revokeSessionWithAccessTokens(ctx, &sessions[i]); err != nil {
+			return err
+		}
+
+		s.logger.With(log.Fields{
+			"session_id":   sessions[i].ID,
+			"user_id":      userId,
+			"last_used_at": lastUsedAt(&sessions[i]),
+		}).Info("evicted a session over the limit")
+	}
+
+	return nil
+}
+
+// rejectBlacklistedRefreshToken revokes the family of the rotated refresh token.
+// The token of a revoked session has no family, so its use is not a reuse
+func (s *Service) rejectBlacklistedRefreshToken(ctx context.Context, logger log.Logger, familyId string) error {
+	if familyId == "" {
+		logger.Debug("attempted to refresh a revoked session")
+		return ErrInvalidRefreshToken
+	}
+
+	if err := s.revokeFamily(ctx, familyId); err != nil {
+		return err
+	}
+
+	logger.With(log.Fields{"family_id": familyId}).
+		Warn("revoked a session family after a reuse of a rotated refresh token")
+	return ErrRefreshTokenReused
+}
+
+// revokeFamily revokes every session of the family
+// and removes their access tokens from the whitelist
+func (s *Service) revokeFamily(ctx context.Context, familyId string) error {
+	sessions, err := s.authRepo.FindSessionsByFamilyId(ctx, familyId)
+	if err != nil {
+		return err
+	}
+
+	for i := range sessions {
+		if err = s.revokeSessionWithAccessTokens(ctx, &sessions[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revokeSession deletes the session and blacklists its refresh token without
+// the family, so that a later use of the token is not taken for its reuse
+func (s *Service) revokeSession(ctx context.Context, session *domain.Session) error {
+	if err := s.authRepo.DeleteSessionById(ctx, session.ID); err != nil {
+		return err
+	}
+
+	if ttl := time.Until(session.ExpiresAt); ttl > 0 && session.RefreshToken != "" {
+		if _, err := s.authRepo.SaveRefreshTokenToBlacklist(ctx, session.RefreshToken, "", ttl); err != nil {
+			return err
+		}
+	}
+
+	s.logger.With(log.Fields{
+		"session_id": session.ID,
+		"user_id":    session.User.ID,
+	}).Debug("revoked a session")
+	return nil
+}
+
+// revokeSessionWithAccessTokens revokes the session and removes
+// its access tokens from the whitelist, so that they are rejected at once
+func (s *Service) revokeSessionWithAccessTokens(ctx context.Context, session *domain.Session) error {
+	if err := s.revokeSession(ctx, session); err != nil {
+		return err
+	}
+
+	return s.authRepo.DeleteSessionAccessTokensFromWhitelist(ctx, session.ID)
+}
+
+// lastUsedAt returns the time the session was created or last refreshed at
+func lastUsedAt(session *domain.Session) time.Time {
+	if session.UpdatedAt.After(session.CreatedAt) {
+		return session.UpdatedAt
+	}
+
+	return session.CreatedAt
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, refreshTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
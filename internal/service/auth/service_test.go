@@ -0,0 +1,718 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	_repoMock "github.com/adanyl0v/pocket-ideas/internal/repository/mocks"
+	pgrepo "github.com/adanyl0v/pocket-ideas/internal/repository/postgres"
+	redisrepo "github.com/adanyl0v/pocket-ideas/internal/repository/redis"
+	_authMock "github.com/adanyl0v/pocket-ideas/internal/service/auth/mocks"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/lock"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/memory"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	passwordhasher "github.com/adanyl0v/pocket-ideas/pkg/password"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"github.com/golang/mock/gomock"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	"testing"
+	"time"
+)
+
+type (
+	serviceTestCaseRegister func(
+		_ *gomock.Controller,
+		userRepo *_repoMock.MockUserRepository,
+		authRepo *_repoMock.MockAuthRepository,
+		hasher *_authMock.MockPasswordHasher,
+		accessTokens *_authMock.MockAccessTokenManager,
+	)
+	serviceTestCaseCommand func(svc *Service) error
+	serviceTestCaseExpect  func(err error)
+
+	serviceTestCase struct {
+		reg serviceTestCaseRegister
+		cmd serviceTestCaseCommand
+		exp serviceTestCaseExpect
+	}
+)
+
+const testMaxSessionsPerUser = 2
+
+var testFingerprint = domain.Fingerprint{
+	ClientIP:  "127.0.0.1",
+	UserAgent: "test",
+}
+
+func TestService_Register(t *testing.T) {
+	tcs := map[string]serviceTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, userRepo *_repoMock.MockUserRepository, _ *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				hasher.EXPECT().Hash("password").Return("hash", nil)
+				userRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, user *domain.User) error {
+						require.Equal(t, "hash", user.Password)
+						return nil
+					})
+			},
+			cmd: func(svc *Service) error {
+				return svc.Register(context.Background(), &domain.User{Password: "password"})
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED to hash a password": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, _ *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				hasher.EXPECT().Hash("password").Return("", errors.New(""))
+			},
+			cmd: func(svc *Service) error {
+				return svc.Register(context.Background(), &domain.User{Password: "password"})
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runServiceTestCase(t, &tc)
+		})
+	}
+}
+
+func TestService_Login(t *testing.T) {
+	const userId = "0194f9a1-1b2c-7d3e-8f4a-5b6c7d8e9f0a"
+	user := domain.User{ID: userId, Email: "user@example.com", Password: "hash"}
+
+	tcs := map[string]serviceTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, userRepo *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, accessTokens *_authMock.MockAccessTokenManager) {
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(user, nil)
+				hasher.EXPECT().Verify("password", "hash").Return(true, nil)
+				hasher.EXPECT().NeedsRehash("hash").Return(false)
+				authRepo.EXPECT().FindSessionByFingerprint(gomock.Any(), userId, testFingerprint).
+					Return(domain.Session{}, redisrepo.ErrNotFound)
+				authRepo.EXPECT().FindSessionsByUserId(gomock.Any(), userId).Return(nil, nil)
+				authRepo.EXPECT().SaveSession(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, session *domain.Session) error {
+						require.Equal(t, userId, session.User.ID)
+						require.Equal(t, testFingerprint, session.Fingerprint)
+						require.NotEmpty(t, session.RefreshToken)
+						return nil
+					})
+				accessTokens.EXPECT().IssueAccessToken(gomock.Any(), gomock.Any()).Return("access", nil)
+				authRepo.EXPECT().SaveAccessTokenToWhitelist(gomock.Any(), gomock.Any(), "access", time.Minute).Return(nil)
+			},
+			cmd: func(svc *Service) error {
+				tokens, err := svc.Login(context.Background(), user.Email, "password", testFingerprint)
+				require.Equal(t, "access", tokens.AccessToken)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS revokes a session with the same fingerprint": {
+			reg: func(_ *gomock.Controller, userRepo *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, accessTokens *_authMock.MockAccessTokenManager) {
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(user, nil)
+				hasher.EXPECT().Verify("password", "hash").Return(true, nil)
+				hasher.EXPECT().NeedsRehash("hash").Return(false)
+				authRepo.EXPECT().FindSessionByFingerprint(gomock.Any(), userId, testFingerprint).
+					Return(domain.Session{
+						ID:           "old",
+						User:         domain.User{ID: userId},
+						RefreshToken: "old refresh",
+						ExpiresAt:    time.Now().Add(time.Hour),
+					}, nil)
+				authRepo.EXPECT().DeleteSessionById(gomock.Any(), "old").Return(nil)
+				authRepo.EXPECT().SaveRefreshTokenToBlacklist(gomock.Any(), "old refresh", "", gomock.Any()).Return(true, nil)
+				authRepo.EXPECT().FindSessionsByUserId(gomock.Any(), userId).Return(nil, nil)
+				authRepo.EXPECT().SaveSession(gomock.Any(), gomock.Any()).Return(nil)
+				accessTokens.EXPECT().IssueAccessToken(gomock.Any(), gomock.Any()).Return("access", nil)
+				authRepo.EXPECT().SaveAccessTokenToWhitelist(gomock.Any(), gomock.Any(), "access", time.Minute).Return(nil)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Login(context.Background(), user.Email, "password", testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS evicts the least recently used sessions": {
+			reg: func(_ *gomock.Controller, userRepo *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, accessTokens *_authMock.MockAccessTokenManager) {
+				now := time.Now()
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(user, nil)
+				hasher.EXPECT().Verify("password", "hash").Return(true, nil)
+				hasher.EXPECT().NeedsRehash("hash").Return(false)
+				authRepo.EXPECT().FindSessionByFingerprint(gomock.Any(), userId, testFingerprint).
+					Return(domain.Session{}, redisrepo.ErrNotFound)
+				authRepo.EXPECT().FindSessionsByUserId(gomock.Any(), userId).Return([]domain.Session{
+					{
+						ID:           "refreshed",
+						User:         domain.User{ID: userId},
+						RefreshToken: "refreshed refresh",
+						ExpiresAt:    now.Add(time.Hour),
+						CreatedAt:    now.Add(-3 * time.Hour),
+						UpdatedAt:    now.Add(-time.Minute),
+					},
+					{
+						ID:           "oldest",
+						User:         domain.User{ID: userId},
+						RefreshToken: "oldest refresh",
+						ExpiresAt:    now.Add(time.Hour),
+						CreatedAt:    now.Add(-2 * time.Hour),
+						UpdatedAt:    now.Add(-2 * time.Hour),
+					},
+					{
+						ID:           "older",
+						User:         domain.User{ID: userId},
+						RefreshToken: "older refresh",
+						ExpiresAt:    now.Add(time.Hour),
+						CreatedAt:    now.Add(-time.Hour),
+						UpdatedAt:    now.Add(-time.Hour),
+					},
+				}, nil)
+				gomock.InOrder(
+					authRepo.EXPECT().DeleteSessionById(gomock.Any(), "oldest").Return(nil),
+					authRepo.EXPECT().SaveRefreshTokenToBlacklist(gomock.Any(), "oldest refresh", "", gomock.Any()).Return(true, nil),
+					authRepo.EXPECT().DeleteSessionAccessTokensFromWhitelist(gomock.Any(), "oldest").Return(nil),
+					authRepo.EXPECT().DeleteSessionById(gomock.Any(), "older").Return(nil),
+					authRepo.EXPECT().SaveRefreshTokenToBlacklist(gomock.Any(), "older refresh", "", gomock.Any()).Return(true, nil),
+					authRepo.EXPECT().DeleteSessionAccessTokensFromWhitelist(gomock.Any(), "older").Return(nil),
+					authRepo.EXPECT().SaveSession(gomock.Any(), gomock.Any()).Return(nil),
+				)
+				accessTokens.EXPECT().IssueAccessToken(gomock.Any(), gomock.Any()).Return("access", nil)
+				authRepo.EXPECT().SaveAccessTokenToWhitelist(gomock.Any(), gomock.Any(), "access", time.Minute).Return(nil)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Login(context.Background(), user.Email, "password", testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS rehashes an outdated password": {
+			reg: func(_ *gomock.Controller, userRepo *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, accessTokens *_authMock.MockAccessTokenManager) {
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(user, nil)
+				hasher.EXPECT().Verify("password", "hash").Return(true, nil)
+				hasher.EXPECT().NeedsRehash("hash").Return(true)
+				hasher.EXPECT().Hash("password").Return("new hash", nil)
+				userRepo.EXPECT().UpdateById(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, u *domain.User) error {
+						require.Equal(t, userId, u.ID)
+						require.Equal(t, "new hash", u.Password)
+						return nil
+					})
+				authRepo.EXPECT().FindSessionByFingerprint(gomock.Any(), userId, testFingerprint).
+					Return(domain.Session{}, redisrepo.ErrNotFound)
+				authRepo.EXPECT().FindSessionsByUserId(gomock.Any(), userId).Return(nil, nil)
+				authRepo.EXPECT().SaveSession(gomock.Any(), gomock.Any()).Return(nil)
+				accessTokens.EXPECT().IssueAccessToken(gomock.Any(), gomock.Any()).Return("access", nil)
+				authRepo.EXPECT().SaveAccessTokenToWhitelist(gomock.Any(), gomock.Any(), "access", time.Minute).Return(nil)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Login(context.Background(), user.Email, "password", testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS ignores a failed rehash": {
+			reg: func(_ *gomock.Controller, userRepo *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, accessTokens *_authMock.MockAccessTokenManager) {
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(user, nil)
+				hasher.EXPECT().Verify("password", "hash").Return(true, nil)
+				hasher.EXPECT().NeedsRehash("hash").Return(true)
+				hasher.EXPECT().Hash("password").Return("new hash", nil)
+				userRepo.EXPECT().UpdateById(gomock.Any(), gomock.Any()).Return(errors.New(""))
+				authRepo.EXPECT().FindSessionByFingerprint(gomock.Any(), userId, testFingerprint).
+					Return(domain.Session{}, redisrepo.ErrNotFound)
+				authRepo.EXPECT().FindSessionsByUserId(gomock.Any(), userId).Return(nil, nil)
+				authRepo.EXPECT().SaveSession(gomock.Any(), gomock.Any()).Return(nil)
+				accessTokens.EXPECT().IssueAccessToken(gomock.Any(), gomock.Any()).Return("access", nil)
+				authRepo.EXPECT().SaveAccessTokenToWhitelist(gomock.Any(), gomock.Any(), "access", time.Minute).Return(nil)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Login(context.Background(), user.Email, "password", testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED to find sessions to evict": {
+			reg: func(_ *gomock.Controller, userRepo *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(user, nil)
+				hasher.EXPECT().Verify("password", "hash").Return(true, nil)
+				hasher.EXPECT().NeedsRehash("hash").Return(false)
+				authRepo.EXPECT().FindSessionByFingerprint(gomock.Any(), userId, testFingerprint).
+					Return(domain.Session{}, redisrepo.ErrNotFound)
+				authRepo.EXPECT().FindSessionsByUserId(gomock.Any(), userId).Return(nil, errors.New(""))
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Login(context.Background(), user.Email, "password", testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"FAILED the sessions of the user are locked": {
+			reg: func(_ *gomock.Controller, userRepo *_repoMock.MockUserRepository, _ *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(user, nil)
+				hasher.EXPECT().Verify("password", "hash").Return(true, nil)
+				hasher.EXPECT().NeedsRehash("hash").Return(false)
+			},
+			cmd: func(svc *Service) error {
+				ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+				defer cancel()
+
+				// Another login of the user holds the lock
+				held, err := svc.locker.(*lock.Locker).TryAcquire(ctx, fmt.Sprintf(sessionsLockFormat, userId))
+				require.NoError(t, err)
+				defer held.Release(context.Background())
+
+				_, err = svc.Login(ctx, user.Email, "password", testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+		"FAILED unknown email": {
+			reg: func(_ *gomock.Controller, userRepo *_repoMock.MockUserRepository, _ *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).
+					Return(domain.User{}, proxerr.New(pgrepo.ErrUserNotFound, ""))
+
+				// The dummy hash is verified, as a real one would be
+				hasher.EXPECT().Hash(dummyPassword).Times(1).Return("dummy hash", nil)
+				hasher.EXPECT().Verify("password", "dummy hash").Return(false, nil)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Login(context.Background(), user.Email, "password", testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrInvalidCredentials, err)
+			},
+		},
+		"FAILED password hash of an unsupported algorithm": {
+			reg: func(_ *gomock.Controller, userRepo *_repoMock.MockUserRepository, _ *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).
+					Return(domain.User{ID: userId, Email: user.Email, Password: "plaintext"}, nil)
+				hasher.EXPECT().Verify("password", "plaintext").Return(false, passwordhasher.ErrUnsupportedAlgorithm)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Login(context.Background(), user.Email, "password", testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrInvalidCredentials, err)
+			},
+		},
+		"FAILED invalid password": {
+			reg: func(_ *gomock.Controller, userRepo *_repoMock.MockUserRepository, _ *_repoMock.MockAuthRepository,
+				hasher *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(user, nil)
+				hasher.EXPECT().Verify("invalid", "hash").Return(false, nil)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Login(context.Background(), user.Email, "invalid", testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrInvalidCredentials, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runServiceTestCase(t, &tc)
+		})
+	}
+}
+
+func TestService_Refresh(t *testing.T) {
+	const refreshToken = "refresh"
+	session := domain.Session{
+		ID:          "0194f9a2-2c3d-7e4f-8a5b-6c7d8e9f0a1b",
+		FamilyID:    "0194f9a2-2c3d-7e4f-8a5b-6c7d8e9f0a1b",
+		User:        domain.User{ID: "0194f9a2-3d4e-7f5a-8b6c-7d8e9f0a1b2c"},
+		Fingerprint: testFingerprint,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	tcs := map[string]serviceTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, accessTokens *_authMock.MockAccessTokenManager) {
+				authRepo.EXPECT().FindRefreshTokenFamilyInBlacklist(gomock.Any(), refreshToken).
+					Return("", proxerr.New(redisrepo.ErrNotFound, ""))
+				authRepo.EXPECT().FindSessionByRefreshToken(gomock.Any(), refreshToken).Return(session, nil)
+				authRepo.EXPECT().SaveRefreshTokenToBlacklist(gomock.Any(), refreshToken, session.FamilyID, gomock.Any()).Return(true, nil)
+				authRepo.EXPECT().UpdateSessionById(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, s *domain.Session) error {
+						require.Equal(t, session.ID, s.ID)
+						require.NotEqual(t, refreshToken, s.RefreshToken)
+						return nil
+					})
+				accessTokens.EXPECT().IssueAccessToken(gomock.Any(), gomock.Any()).Return("access", nil)
+				authRepo.EXPECT().SaveAccessTokenToWhitelist(gomock.Any(), gomock.Any(), "access", time.Minute).Return(nil)
+			},
+			cmd: func(svc *Service) error {
+				tokens, err := svc.Refresh(context.Background(), refreshToken, testFingerprint)
+				require.NotEqual(t, refreshToken, tokens.RefreshToken)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED reused refresh token revokes the family": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				current := session
+				current.RefreshToken = "current"
+
+				authRepo.EXPECT().FindRefreshTokenFamilyInBlacklist(gomock.Any(), refreshToken).Return(session.FamilyID, nil)
+				authRepo.EXPECT().FindSessionsByFamilyId(gomock.Any(), session.FamilyID).
+					Return([]domain.Session{current}, nil)
+				gomock.InOrder(
+					authRepo.EXPECT().DeleteSessionById(gomock.Any(), session.ID).Return(nil),
+					authRepo.EXPECT().SaveRefreshTokenToBlacklist(gomock.Any(), "current", "", gomock.Any()).Return(true, nil),
+					authRepo.EXPECT().DeleteSessionAccessTokensFromWhitelist(gomock.Any(), session.ID).Return(nil),
+				)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Refresh(context.Background(), refreshToken, testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrRefreshTokenReused, err)
+			},
+		},
+		"FAILED concurrent refresh revokes the family": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				gomock.InOrder(
+					authRepo.EXPECT().FindRefreshTokenFamilyInBlacklist(gomock.Any(), refreshToken).
+						Return("", proxerr.New(redisrepo.ErrNotFound, "")),
+					authRepo.EXPECT().FindRefreshTokenFamilyInBlacklist(gomock.Any(), refreshToken).
+						Return(session.FamilyID, nil),
+				)
+				authRepo.EXPECT().FindSessionByRefreshToken(gomock.Any(), refreshToken).Return(session, nil)
+				authRepo.EXPECT().SaveRefreshTokenToBlacklist(gomock.Any(), refreshToken, session.FamilyID, gomock.Any()).
+					Return(false, nil)
+				authRepo.EXPECT().FindSessionsByFamilyId(gomock.Any(), session.FamilyID).Return(nil, nil)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Refresh(context.Background(), refreshToken, testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrRefreshTokenReused, err)
+			},
+		},
+		"FAILED refresh token of a revoked session": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				authRepo.EXPECT().FindRefreshTokenFamilyInBlacklist(gomock.Any(), refreshToken).Return("", nil)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Refresh(context.Background(), refreshToken, testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrInvalidRefreshToken, err)
+			},
+		},
+		"FAILED to revoke the family": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				authRepo.EXPECT().FindRefreshTokenFamilyInBlacklist(gomock.Any(), refreshToken).Return(session.FamilyID, nil)
+				authRepo.EXPECT().FindSessionsByFamilyId(gomock.Any(), session.FamilyID).Return(nil, errors.New(""))
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Refresh(context.Background(), refreshToken, testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+				require.NotEqual(t, ErrRefreshTokenReused, err)
+			},
+		},
+		"FAILED to check the blacklist": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				authRepo.EXPECT().FindRefreshTokenFamilyInBlacklist(gomock.Any(), refreshToken).Return("", errors.New(""))
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Refresh(context.Background(), refreshToken, testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"FAILED session not found": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				authRepo.EXPECT().FindRefreshTokenFamilyInBlacklist(gomock.Any(), refreshToken).
+					Return("", proxerr.New(redisrepo.ErrNotFound, ""))
+				authRepo.EXPECT().FindSessionByRefreshToken(gomock.Any(), refreshToken).
+					Return(domain.Session{}, redisrepo.ErrNotFound)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Refresh(context.Background(), refreshToken, testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrInvalidRefreshToken, err)
+			},
+		},
+		"FAILED session expired": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				expired := session
+				expired.ExpiresAt = time.Now().Add(-time.Second)
+
+				authRepo.EXPECT().FindRefreshTokenFamilyInBlacklist(gomock.Any(), refreshToken).
+					Return("", proxerr.New(redisrepo.ErrNotFound, ""))
+				authRepo.EXPECT().FindSessionByRefreshToken(gomock.Any(), refreshToken).Return(expired, nil)
+				authRepo.EXPECT().DeleteSessionById(gomock.Any(), session.ID).Return(nil)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Refresh(context.Background(), refreshToken, testFingerprint)
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrSessionExpired, err)
+			},
+		},
+		"FAILED fingerprint mismatch": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				authRepo.EXPECT().FindRefreshTokenFamilyInBlacklist(gomock.Any(), refreshToken).
+					Return("", proxerr.New(redisrepo.ErrNotFound, ""))
+				authRepo.EXPECT().FindSessionByRefreshToken(gomock.Any(), refreshToken).Return(session, nil)
+				authRepo.EXPECT().DeleteSessionById(gomock.Any(), session.ID).Return(nil)
+				authRepo.EXPECT().SaveRefreshTokenToBlacklist(gomock.Any(), refreshToken, "", gomock.Any()).Return(true, nil)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Refresh(context.Background(), refreshToken, domain.Fingerprint{ClientIP: "10.0.0.1"})
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrFingerprintMismatch, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runServiceTestCase(t, &tc)
+		})
+	}
+}
+
+func TestService_Logout(t *testing.T) {
+	const refreshToken = "refresh"
+	session := domain.Session{
+		ID:        "0194f9a3-4e5f-7a6b-8c7d-8e9f0a1b2c3d",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	tcs := map[string]serviceTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				authRepo.EXPECT().FindSessionByRefreshToken(gomock.Any(), refreshToken).Return(session, nil)
+				authRepo.EXPECT().DeleteSessionById(gomock.Any(), session.ID).Return(nil)
+				authRepo.EXPECT().SaveRefreshTokenToBlacklist(gomock.Any(), refreshToken, "", gomock.Any()).Return(true, nil)
+				authRepo.EXPECT().DeleteAccessTokenFromWhitelist(gomock.Any(), "access").Return(nil)
+			},
+			cmd: func(svc *Service) error {
+				return svc.Logout(context.Background(), "access", refreshToken)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED session not found": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				authRepo.EXPECT().FindSessionByRefreshToken(gomock.Any(), refreshToken).
+					Return(domain.Session{}, redisrepo.ErrNotFound)
+			},
+			cmd: func(svc *Service) error {
+				return svc.Logout(context.Background(), "access", refreshToken)
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrInvalidRefreshToken, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runServiceTestCase(t, &tc)
+		})
+	}
+}
+
+func TestService_LogoutAll(t *testing.T) {
+	const userId = "0194f9a4-5f6a-7b7c-8d8e-9f0a1b2c3d4e"
+
+	tcs := map[string]serviceTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				authRepo.EXPECT().FindSessionsByUserId(gomock.Any(), userId).Return([]domain.Session{
+					{ID: "a", RefreshToken: "a", ExpiresAt: time.Now().Add(time.Hour)},
+					{ID: "b", RefreshToken: "b", ExpiresAt: time.Now().Add(-time.Hour)},
+				}, nil)
+				authRepo.EXPECT().DeleteSessionById(gomock.Any(), "a").Return(nil)
+				authRepo.EXPECT().SaveRefreshTokenToBlacklist(gomock.Any(), "a", "", gomock.Any()).Return(true, nil)
+				authRepo.EXPECT().DeleteSessionAccessTokensFromWhitelist(gomock.Any(), "a").Return(nil)
+				authRepo.EXPECT().DeleteSessionById(gomock.Any(), "b").Return(nil)
+				authRepo.EXPECT().DeleteSessionAccessTokensFromWhitelist(gomock.Any(), "b").Return(nil)
+			},
+			cmd: func(svc *Service) error {
+				return svc.LogoutAll(context.Background(), userId)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, _ *_authMock.MockAccessTokenManager) {
+				authRepo.EXPECT().FindSessionsByUserId(gomock.Any(), userId).Return(nil, errors.New(""))
+			},
+			cmd: func(svc *Service) error {
+				return svc.LogoutAll(context.Background(), userId)
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runServiceTestCase(t, &tc)
+		})
+	}
+}
+
+func TestService_Authenticate(t *testing.T) {
+	session := domain.Session{ID: "session", User: domain.User{ID: "user"}}
+
+	tcs := map[string]serviceTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, accessTokens *_authMock.MockAccessTokenManager) {
+				accessTokens.EXPECT().ParseAccessToken("access").Return(session, nil)
+				authRepo.EXPECT().FindAccessTokenInWhitelist(gomock.Any(), "access").Return(true, nil)
+			},
+			cmd: func(svc *Service) error {
+				s, err := svc.Authenticate(context.Background(), "access")
+				require.Equal(t, session, s)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED invalid token": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, _ *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, accessTokens *_authMock.MockAccessTokenManager) {
+				accessTokens.EXPECT().ParseAccessToken("access").Return(domain.Session{}, errors.New(""))
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Authenticate(context.Background(), "access")
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrInvalidAccessToken)
+			},
+		},
+		"FAILED revoked token": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, authRepo *_repoMock.MockAuthRepository,
+				_ *_authMock.MockPasswordHasher, accessTokens *_authMock.MockAccessTokenManager) {
+				accessTokens.EXPECT().ParseAccessToken("access").Return(session, nil)
+				authRepo.EXPECT().FindAccessTokenInWhitelist(gomock.Any(), "access").Return(false, nil)
+			},
+			cmd: func(svc *Service) error {
+				_, err := svc.Authenticate(context.Background(), "access")
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrInvalidAccessToken, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runServiceTestCase(t, &tc)
+		})
+	}
+}
+
+// runServiceTestCase should be called by [testing.T.Run]
+func runServiceTestCase(t *testing.T, tc *serviceTestCase) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := _repoMock.NewMockUserRepository(ctrl)
+	authRepo := _repoMock.NewMockAuthRepository(ctrl)
+	hasher := _authMock.NewMockPasswordHasher(ctrl)
+	accessTokens := _authMock.NewMockAccessTokenManager(ctrl)
+	if tc.reg != nil {
+		tc.reg(ctrl, userRepo, authRepo, hasher, accessTokens)
+	}
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	locker := lock.NewLocker(memory.New(logger, nil), logger, nil)
+	svc := NewService(userRepo, authRepo, hasher, accessTokens, locker, logger, &Config{
+		AccessTokenTTL:     time.Minute,
+		RefreshTokenTTL:    time.Hour,
+		MaxSessionsPerUser: testMaxSessionsPerUser,
+	})
+
+	var err error
+	if tc.cmd != nil {
+		err = tc.cmd(svc)
+	}
+
+	if err != nil {
+		var pxErr proxerr.Error
+		if errors.As(err, &pxErr) {
+			err = pxErr.Unwrap()
+		}
+	}
+
+	if tc.exp != nil {
+		tc.exp(err)
+	}
+}
@@ -0,0 +1,223 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"github.com/adanyl0v/pocket-ideas/internal/service/auth"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/ratelimit"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"net"
+	stdhttp "net/http"
+	"strings"
+)
+
+type AuthService interface {
+	Register(ctx context.Context, user *domain.User) error
+	Login(ctx context.Context, email, password string, fp domain.Fingerprint) (auth.Tokens, error)
+	Refresh(ctx context.Context, refreshToken string, fp domain.Fingerprint) (auth.Tokens, error)
+	Logout(ctx context.Context, accessToken, refreshToken string) error
+	LogoutAll(ctx context.Context, userId string) error
+	Authenticate(ctx context.Context, accessToken string) (domain.Session, error)
+}
+
+// RateLimiter counts the requests by the key
+type RateLimiter interface {
+	Allow(ctx context.Context, key ratelimit.Key) (ratelimit.RateLimit, error)
+}
+
+type AuthHandler struct {
+	handler
+	service        AuthService
+	loginLimiter   RateLimiter
+	refreshLimiter RateLimiter
+}
+
+// NewAuthHandler limits the logins by the client ip
+// and by the email, and the refreshes by the client ip
+func NewAuthHandler(
+	service AuthService,
+	loginLimiter RateLimiter,
+	refreshLimiter RateLimiter,
+	logger log.Logger,
+) *AuthHandler {
+	return &AuthHandler{
+		handler: handler{
+			auth:   service,
+			logger: logger,
+		},
+		service:        service,
+		loginLimiter:   loginLimiter,
+		refreshLimiter: refreshLimiter,
+	}
+}
+
+func (h *AuthHandler) Register(mux *stdhttp.ServeMux) {
+	mux.HandleFunc("POST /api/v1/auth/register", h.SignUp)
+	mux.HandleFunc("POST /api/v1/auth/login", h.Login)
+	mux.HandleFunc("POST /api/v1/auth/refresh", h.Refresh)
+	mux.HandleFunc("POST /api/v1/auth/logout", h.Logout)
+	mux.HandleFunc("POST /api/v1/auth/logout-all", h.authenticated(h.LogoutAll))
+}
+
+func (h *AuthHandler) SignUp(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	var req createUserRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var user domain.User
+	req.ToDomain(&user)
+	if err := h.service.Register(r.Context(), &user); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusCreated, newUserResponse(&user))
+}
+
+func (h *AuthHandler) Login(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	var req loginRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	fp := fingerprint(r)
+	if err := h.allow(w, r, h.loginLimiter, ratelimit.ClientIP(fp.ClientIP), ratelimit.Email(req.Email)); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	tokens, err := h.service.Login(r.Context(), req.Email, req.Password, fp)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusOK, newTokensResponse(&tokens))
+}
+
+func (h *AuthHandler) Refresh(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	var req refreshTokenRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	fp := fingerprint(r)
+	if err := h.allow(w, r, h.refreshLimiter, ratelimit.ClientIP(fp.ClientIP)); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	tokens, err := h.service.Refresh(r.Context(), req.RefreshToken, fp)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusOK, newTokensResponse(&tokens))
+}
+
+// Logout revokes the session of the refresh token. The access token
+// from the "Authorization" header is revoked too, if it is specified
+func (h *AuthHandler) Logout(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	var req refreshTokenRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	accessToken, _ := bearerToken(r)
+	if err := h.service.Logout(r.Context(), accessToken, req.RefreshToken); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(stdhttp.StatusNoContent)
+}
+
+// LogoutAll revokes every session of the user authenticated
+// by the access token from the "Authorization" header
+func (h *AuthHandler) LogoutAll(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if err := h.service.LogoutAll(r.Context(), currentSession(r).User.ID); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(stdhttp.StatusNoContent)
+}
+
+// allow counts the request by the keys in order and sets the headers
+// of the most restrictive limit. The keys after an exceeded limit
+// are not counted, so a denied request doesn't drain them
+func (h *AuthHandler) allow(w stdhttp.ResponseWriter, r *stdhttp.Request, limiter RateLimiter, keys ...ratelimit.Key) error {
+	var strictest ratelimit.RateLimit
+	for i, key := range keys {
+		rl, err := limiter.Allow(r.Context(), key)
+		if err != nil {
+			return err
+		}
+
+		if i == 0 || !rl.Allowed || rl.Remaining < strictest.Remaining {
+			strictest = rl
+		}
+		if !rl.Allowed {
+			break
+		}
+	}
+
+	ratelimit.SetHeaders(w.Header(), strictest)
+	if !strictest.Allowed {
+		return ErrTooManyRequests
+	}
+
+	return nil
+}
+
+// bearerToken returns the token from the "Authorization" header
+func bearerToken(r *stdhttp.Request) (string, error) {
+	const prefix = "Bearer "
+
+	v := r.Header.Get("Authorization")
+	if len(v) <= len(prefix) || !strings.EqualFold(v[:len(prefix)], prefix) {
+		return "", fmt.Errorf("%w: missing bearer token", auth.ErrInvalidAccessToken)
+	}
+
+	return v[len(prefix):], nil
+}
+
+// fingerprint identifies the client that sent the request
+func fingerprint(r *stdhttp.Request) domain.Fingerprint {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return domain.Fingerprint{
+		ClientIP:  ip,
+		UserAgent: r.UserAgent(),
+	}
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/internal/service/auth"
+	"time"
+)
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (r *loginRequest) Validate() error {
+	if r.Email == "" || r.Password == "" {
+		return fmt.Errorf("%w: email and password must not be empty", ErrValidation)
+	}
+
+	return nil
+}
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (r *refreshTokenRequest) Validate() error {
+	if r.RefreshToken == "" {
+		return fmt.Errorf("%w: refresh token must not be empty", ErrValidation)
+	}
+
+	return nil
+}
+
+type tokensResponse struct {
+	TokenType             string    `json:"token_type"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+func newTokensResponse(t *auth.Tokens) tokensResponse {
+	return tokensResponse{
+		TokenType:             "Bearer",
+		AccessToken:           t.AccessToken,
+		AccessTokenExpiresAt:  t.AccessTokenExpiresAt,
+		RefreshToken:          t.RefreshToken,
+		RefreshTokenExpiresAt: t.RefreshTokenExpiresAt,
+	}
+}
@@ -0,0 +1,343 @@
+package http
+
+import (
+	"errors"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	pgrepo "github.com/adanyl0v/pocket-ideas/internal/repository/postgres"
+	"github.com/adanyl0v/pocket-ideas/internal/service/auth"
+	_httpMock "github.com/adanyl0v/pocket-ideas/internal/transport/http/mocks"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"github.com/golang/mock/gomock"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type (
+	authHandlerTestCaseRegister func(_ *gomock.Controller, service *_httpMock.MockAuthService)
+	authHandlerTestCaseRequest  func() *stdhttp.Request
+	authHandlerTestCaseExpect   func(rec *httptest.ResponseRecorder)
+
+	authHandlerTestCase struct {
+		reg authHandlerTestCaseRegister
+		req authHandlerTestCaseRequest
+		exp authHandlerTestCaseExpect
+	}
+)
+
+var testFingerprint = domain.Fingerprint{
+	// The address of the requests created by httptest
+	ClientIP:  "192.0.2.1",
+	UserAgent: "test",
+}
+
+func TestAuthHandler_SignUp(t *testing.T) {
+	const body = `{"name":"user","email":"user@example.com","password":"password"}`
+	tcs := map[string]authHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, service *_httpMock.MockAuthService) {
+				service.EXPECT().Register(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(_ any, user *domain.User) error {
+						require.Equal(t, "password", user.Password)
+						user.ID = "0194f9c1-2d3e-7f4a-8b5c-6d7e8f9a0b1c"
+						user.Password = "hash"
+						return nil
+					})
+			},
+			req: func() *stdhttp.Request {
+				return httptest.NewRequest(stdhttp.MethodPost, "/api/v1/auth/register", strings.NewReader(body))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusCreated, rec.Code)
+				require.NotContains(t, rec.Body.String(), "hash")
+			},
+		},
+		"FAILED unknown field": {
+			req: func() *stdhttp.Request {
+				return httptest.NewRequest(stdhttp.MethodPost, "/api/v1/auth/register",
+					strings.NewReader(`{"name":"user","email":"user@example.com","password":"password","admin":true}`))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusBadRequest, rec.Code)
+			},
+		},
+		"FAILED user already exists": {
+			reg: func(_ *gomock.Controller, service *_httpMock.MockAuthService) {
+				service.EXPECT().Register(gomock.Any(), gomock.Any()).Times(1).
+					Return(proxerr.New(pgrepo.ErrUserAlreadyExists, "duplicate key value"))
+			},
+			req: func() *stdhttp.Request {
+				return httptest.NewRequest(stdhttp.MethodPost, "/api/v1/auth/register", strings.NewReader(body))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusConflict, rec.Code)
+				require.NotContains(t, rec.Body.String(), "duplicate key value")
+			},
+		},
+		"FAILED validation": {
+			req: func() *stdhttp.Request {
+				return httptest.NewRequest(stdhttp.MethodPost, "/api/v1/auth/register",
+					strings.NewReader(`{"name":"user","email":"user@example.com","password":"short"}`))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runAuthHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestAuthHandler_Login(t *testing.T) {
+	const body = `{"email":"user@example.com","password":"password"}`
+	tcs := map[string]authHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, service *_httpMock.MockAuthService) {
+				service.EXPECT().Login(gomock.Any(), "user@example.com", "password", testFingerprint).Times(1).
+					Return(auth.Tokens{AccessToken: "access", RefreshToken: "refresh"}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/login", body)
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusOK, rec.Code)
+				require.Equal(t, "4", rec.Header().Get("RateLimit-Remaining"))
+				require.Contains(t, rec.Body.String(), `"access_token":"access"`)
+				require.Contains(t, rec.Body.String(), `"refresh_token":"refresh"`)
+			},
+		},
+		"FAILED invalid credentials": {
+			reg: func(_ *gomock.Controller, service *_httpMock.MockAuthService) {
+				service.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(auth.Tokens{}, auth.ErrInvalidCredentials)
+			},
+			req: func() *stdhttp.Request {
+				return newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/login", body)
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusUnauthorized, rec.Code)
+			},
+		},
+		"FAILED too many requests by email": {
+			req: func() *stdhttp.Request {
+				return newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/login",
+					`{"email":"`+testLimitedEmail+`","password":"password"}`)
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusTooManyRequests, rec.Code)
+				require.Equal(t, "60", rec.Header().Get("Retry-After"))
+				require.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+			},
+		},
+		"FAILED too many requests by client ip": {
+			req: func() *stdhttp.Request {
+				r := newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/login", body)
+				r.RemoteAddr = testLimitedClientIP + ":1234"
+				return r
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusTooManyRequests, rec.Code)
+				require.Equal(t, "60", rec.Header().Get("Retry-After"))
+			},
+		},
+		"FAILED validation": {
+			req: func() *stdhttp.Request {
+				return newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/login", `{"email":"user@example.com"}`)
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runAuthHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	const body = `{"refresh_token":"refresh"}`
+	tcs := map[string]authHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, service *_httpMock.MockAuthService) {
+				service.EXPECT().Refresh(gomock.Any(), "refresh", testFingerprint).Times(1).
+					Return(auth.Tokens{AccessToken: "access", RefreshToken: "rotated"}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/refresh", body)
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusOK, rec.Code)
+				require.Contains(t, rec.Body.String(), `"refresh_token":"rotated"`)
+			},
+		},
+		"FAILED too many requests": {
+			req: func() *stdhttp.Request {
+				r := newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/refresh", body)
+				r.RemoteAddr = testLimitedClientIP + ":1234"
+				return r
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusTooManyRequests, rec.Code)
+				require.Equal(t, "60", rec.Header().Get("Retry-After"))
+			},
+		},
+		"FAILED invalid refresh token": {
+			reg: func(_ *gomock.Controller, service *_httpMock.MockAuthService) {
+				service.EXPECT().Refresh(gomock.Any(), "refresh", testFingerprint).Times(1).
+					Return(auth.Tokens{}, proxerr.New(auth.ErrInvalidRefreshToken, ""))
+			},
+			req: func() *stdhttp.Request {
+				return newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/refresh", body)
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusUnauthorized, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runAuthHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	const body = `{"refresh_token":"refresh"}`
+	tcs := map[string]authHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, service *_httpMock.MockAuthService) {
+				service.EXPECT().Logout(gomock.Any(), "access", "refresh").Times(1).Return(nil)
+			},
+			req: func() *stdhttp.Request {
+				r := newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/logout", body)
+				r.Header.Set("Authorization", "Bearer access")
+				return r
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNoContent, rec.Code)
+			},
+		},
+		"SUCCESS without an access token": {
+			reg: func(_ *gomock.Controller, service *_httpMock.MockAuthService) {
+				service.EXPECT().Logout(gomock.Any(), "", "refresh").Times(1).Return(nil)
+			},
+			req: func() *stdhttp.Request {
+				return newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/logout", body)
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNoContent, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runAuthHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestAuthHandler_LogoutAll(t *testing.T) {
+	const userId = "0194f9c1-2d3e-7f4a-8b5c-6d7e8f9a0b1c"
+	tcs := map[string]authHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, service *_httpMock.MockAuthService) {
+				service.EXPECT().Authenticate(gomock.Any(), "access").Times(1).
+					Return(domain.Session{User: domain.User{ID: userId}}, nil)
+				service.EXPECT().LogoutAll(gomock.Any(), userId).Times(1).Return(nil)
+			},
+			req: func() *stdhttp.Request {
+				r := newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/logout-all", "")
+				r.Header.Set("Authorization", "Bearer access")
+				return r
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNoContent, rec.Code)
+			},
+		},
+		"FAILED missing access token": {
+			req: func() *stdhttp.Request {
+				return newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/logout-all", "")
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusUnauthorized, rec.Code)
+			},
+		},
+		"FAILED revoked access token": {
+			reg: func(_ *gomock.Controller, service *_httpMock.MockAuthService) {
+				service.EXPECT().Authenticate(gomock.Any(), "access").Times(1).
+					Return(domain.Session{}, auth.ErrInvalidAccessToken)
+			},
+			req: func() *stdhttp.Request {
+				r := newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/logout-all", "")
+				r.Header.Set("Authorization", "Bearer access")
+				return r
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusUnauthorized, rec.Code)
+			},
+		},
+		"FAILED": {
+			reg: func(_ *gomock.Controller, service *_httpMock.MockAuthService) {
+				service.EXPECT().Authenticate(gomock.Any(), "access").Times(1).
+					Return(domain.Session{User: domain.User{ID: userId}}, nil)
+				service.EXPECT().LogoutAll(gomock.Any(), userId).Times(1).Return(errors.New("driver error"))
+			},
+			req: func() *stdhttp.Request {
+				r := newAuthRequest(stdhttp.MethodPost, "/api/v1/auth/logout-all", "")
+				r.Header.Set("Authorization", "Bearer access")
+				return r
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusInternalServerError, rec.Code)
+				require.NotContains(t, rec.Body.String(), "driver error")
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runAuthHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func newAuthRequest(method, target, body string) *stdhttp.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("User-Agent", testFingerprint.UserAgent)
+	return r
+}
+
+// runAuthHandlerTestCase should be called by [testing.T.Run]
+func runAuthHandlerTestCase(t *testing.T, tc *authHandlerTestCase) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := _httpMock.NewMockAuthService(ctrl)
+	if tc.reg != nil {
+		tc.reg(ctrl, service)
+	}
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	router := NewRouter(logger, NewAuthHandler(service, testRateLimiter{}, testRateLimiter{}, logger))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, tc.req())
+
+	if tc.exp != nil {
+		tc.exp(rec)
+	}
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	stdhttp "net/http"
+)
+
+// maxRequestBodySize limits the size of a decoded request body, so that
+// a malicious client can't exhaust the memory with a huge payload
+const maxRequestBodySize = 1 << 20
+
+var ErrInvalidRequestBody = errors.New("invalid request body")
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func decodeJSON(w stdhttp.ResponseWriter, r *stdhttp.Request, dest any) error {
+	r.Body = stdhttp.MaxBytesReader(w, r.Body, maxRequestBodySize)
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dest); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRequestBody, err.Error())
+	}
+
+	// The body must contain a single json value
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: unexpected data after the json value", ErrInvalidRequestBody)
+	}
+
+	return nil
+}
+
+func encodeJSON(w stdhttp.ResponseWriter, status int, v any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if v == nil {
+		return nil
+	}
+
+	return json.NewEncoder(w).Encode(v)
+}
+
+func encodeError(w stdhttp.ResponseWriter, status int, message string) error {
+	return encodeJSON(w, status, errorResponse{Error: message})
+}
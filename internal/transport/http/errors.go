@@ -0,0 +1,58 @@
+package http
+
+import (
+	"errors"
+	pgrepo "github.com/adanyl0v/pocket-ideas/internal/repository/postgres"
+	redisrepo "github.com/adanyl0v/pocket-ideas/internal/repository/redis"
+	"github.com/adanyl0v/pocket-ideas/internal/service/auth"
+	stdhttp "net/http"
+)
+
+var (
+	ErrValidation      = errors.New("validation failed")
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// errorStatuses maps known errors onto http status codes. The order matters,
+// because the first matching error wins
+var errorStatuses = []struct {
+	err    error
+	status int
+}{
+	{ErrInvalidRequestBody, stdhttp.StatusBadRequest},
+	{ErrValidation, stdhttp.StatusBadRequest},
+	{ErrForbidden, stdhttp.StatusForbidden},
+	{ErrTooManyRequests, stdhttp.StatusTooManyRequests},
+	{pgrepo.ErrUserNotFound, stdhttp.StatusNotFound},
+	{pgrepo.ErrUserAlreadyExists, stdhttp.StatusConflict},
+	{pgrepo.ErrUserFieldMustNotBeEmpty, stdhttp.StatusUnprocessableEntity},
+	{pgrepo.ErrIdeaNotFound, stdhttp.StatusNotFound},
+	{pgrepo.ErrIdeaOwnerNotFound, stdhttp.StatusUnprocessableEntity},
+	{pgrepo.ErrIdeaFieldMustNotBeEmpty, stdhttp.StatusUnprocessableEntity},
+	{redisrepo.ErrNotFound, stdhttp.StatusNotFound},
+	{auth.ErrInvalidCredentials, stdhttp.StatusUnauthorized},
+	{auth.ErrInvalidAccessToken, stdhttp.StatusUnauthorized},
+	{auth.ErrInvalidRefreshToken, stdhttp.StatusUnauthorized},
+	{auth.ErrSessionExpired, stdhttp.StatusUnauthorized},
+	{auth.ErrFingerprintMismatch, stdhttp.StatusUnauthorized},
+	{auth.ErrRefreshTokenReused, stdhttp.StatusUnauthorized},
+}
+
+// statusFromError returns the http status code and the message that is safe
+// to show to a client. Unknown errors are treated as internal ones, so that
+// driver details never leak into a response
+func statusFromError(err error) (int, string) {
+	for _, e := range errorStatuses {
+		if !errors.Is(err, e.err) {
+			continue
+		}
+
+		if e.status == stdhttp.StatusBadRequest {
+			return e.status, err.Error()
+		}
+
+		return e.status, e.err.Error()
+	}
+
+	return stdhttp.StatusInternalServerError, stdhttp.StatusText(stdhttp.StatusInternalServerError)
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"github.com/google/uuid"
+	stdhttp "net/http"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// Handler registers its routes on the router
+type Handler interface {
+	Register(mux *stdhttp.ServeMux)
+}
+
+// Authenticator returns the session of a valid access token. Only the ids
+// of the session and its user are set
+type Authenticator interface {
+	Authenticate(ctx context.Context, accessToken string) (domain.Session, error)
+}
+
+type handler struct {
+	auth   Authenticator
+	logger log.Logger
+}
+
+type sessionContextKey struct{}
+
+// authenticated rejects the requests without a valid bearer token
+// and puts the session of the token into the request context
+func (h *handler) authenticated(next stdhttp.HandlerFunc) stdhttp.HandlerFunc {
+	return func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		accessToken, err := bearerToken(r)
+		if err != nil {
+			h.respondError(w, err)
+			return
+		}
+
+		session, err := h.auth.Authenticate(r.Context(), accessToken)
+		if err != nil {
+			h.respondError(w, err)
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), sessionContextKey{}, session)))
+	}
+}
+
+// currentSession must only be called by the handlers wrapped with [handler.authenticated]
+func currentSession(r *stdhttp.Request) domain.Session {
+	return r.Context().Value(sessionContextKey{}).(domain.Session)
+}
+
+func (h *handler) respond(w stdhttp.ResponseWriter, status int, v any) {
+	if err := encodeJSON(w, status, v); err != nil {
+		h.logger.WithError(err).Error("failed to encode a response")
+	}
+}
+
+func (h *handler) respondError(w stdhttp.ResponseWriter, err error) {
+	status, message := statusFromError(err)
+	if status >= stdhttp.StatusInternalServerError {
+		h.logger.WithError(err).Error("failed to handle a request")
+	}
+
+	if err = encodeError(w, status, message); err != nil {
+		h.logger.WithError(err).Error("failed to encode an error response")
+	}
+}
+
+// pathID returns the path parameter if it is a valid uuid
+func pathID(r *stdhttp.Request, name string) (string, error) {
+	v := r.PathValue(name)
+	if _, err := uuid.Parse(v); err != nil {
+		return "", fmt.Errorf("%w: %s must be a valid uuid", ErrValidation, name)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"github.com/adanyl0v/pocket-ideas/internal/service/auth"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/ratelimit"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessToken = "access token"
+	testUserId      = "0194f8a0-1b2c-7d3e-8f4a-5b6c7d8e9f0a"
+	testSessionId   = "0194f8a1-2c3d-7e4f-9a5b-6c7d8e9f0a1b"
+
+	// testLimitedEmail and testLimitedClientIP have exceeded their limits
+	testLimitedEmail    = "limited@example.com"
+	testLimitedClientIP = "192.0.2.2"
+)
+
+// testAuthenticator only accepts [testAccessToken]
+type testAuthenticator struct{}
+
+func (testAuthenticator) Authenticate(_ context.Context, accessToken string) (domain.Session, error) {
+	if accessToken != testAccessToken {
+		return domain.Session{}, auth.ErrInvalidAccessToken
+	}
+
+	return domain.Session{
+		ID:   testSessionId,
+		User: domain.User{ID: testUserId},
+	}, nil
+}
+
+// testTxRunner runs the function without a transaction
+type testTxRunner struct{}
+
+func (testTxRunner) RunInTx(ctx context.Context, _ *database.TxOptions, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// testRateLimiter only denies [testLimitedEmail] and [testLimitedClientIP]
+type testRateLimiter struct{}
+
+func (testRateLimiter) Allow(_ context.Context, key ratelimit.Key) (ratelimit.RateLimit, error) {
+	if key == ratelimit.Email(testLimitedEmail) || key == ratelimit.ClientIP(testLimitedClientIP) {
+		return ratelimit.RateLimit{Limit: 5, RetryAfter: time.Minute, ResetAfter: time.Minute}, nil
+	}
+
+	return ratelimit.RateLimit{Allowed: true, Limit: 5, Remaining: 4, ResetAfter: time.Minute}, nil
+}
+
+// authenticate sets the bearer [testAccessToken] to the request
+func authenticate(r *stdhttp.Request) *stdhttp.Request {
+	r.Header.Set("Authorization", "Bearer "+testAccessToken)
+	return r
+}
+
+func TestHandler_Authenticated(t *testing.T) {
+	type testCase struct {
+		authorization string
+		exp           int
+	}
+
+	tcs := map[string]testCase{
+		"SUCCESS":                     {authorization: "Bearer " + testAccessToken, exp: stdhttp.StatusOK},
+		"SUCCESS case-insensitive":    {authorization: "bearer " + testAccessToken, exp: stdhttp.StatusOK},
+		"FAILED missing header":       {exp: stdhttp.StatusUnauthorized},
+		"FAILED missing bearer":       {authorization: testAccessToken, exp: stdhttp.StatusUnauthorized},
+		"FAILED invalid access token": {authorization: "Bearer invalid", exp: stdhttp.StatusUnauthorized},
+	}
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	h := handler{
+		auth:   testAuthenticator{},
+		logger: logger,
+	}
+	next := h.authenticated(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		require.Equal(t, testUserId, currentSession(r).User.ID)
+		w.WriteHeader(stdhttp.StatusOK)
+	})
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(stdhttp.MethodGet, "/", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			next(rec, req)
+			require.Equal(t, tc.exp, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"github.com/adanyl0v/pocket-ideas/internal/repository"
+	postgresdb "github.com/adanyl0v/pocket-ideas/pkg/database/postgres/pgx"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	stdhttp "net/http"
+)
+
+type IdeaHandler struct {
+	handler
+	repo repository.IdeaRepository
+}
+
+func NewIdeaHandler(repo repository.IdeaRepository, auth Authenticator, logger log.Logger) *IdeaHandler {
+	return &IdeaHandler{
+		handler: handler{
+			auth:   auth,
+			logger: logger,
+		},
+		repo: repo,
+	}
+}
+
+func (h *IdeaHandler) Register(mux *stdhttp.ServeMux) {
+	mux.HandleFunc("POST /api/v1/ideas", h.authenticated(h.Create))
+	mux.HandleFunc("GET /api/v1/ideas", h.authenticated(h.List))
+	mux.HandleFunc("GET /api/v1/ideas/{id}", h.authenticated(h.Get))
+	mux.HandleFunc("PATCH /api/v1/ideas/{id}", h.authenticated(h.Update))
+	mux.HandleFunc("DELETE /api/v1/ideas/{id}", h.authenticated(h.Delete))
+	mux.HandleFunc("GET /api/v1/users/{id}/ideas", h.authenticated(h.ListByUser))
+}
+
+// Create saves the idea owned by the authenticated user
+func (h *IdeaHandler) Create(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	var req createIdeaRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	idea := domain.Idea{User: domain.User{ID: currentSession(r).User.ID}}
+	req.ToDomain(&idea)
+	if err := h.repo.Save(r.Context(), &idea); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusCreated, newIdeaResponse(&idea))
+}
+
+func (h *IdeaHandler) List(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	ideas, err := h.repo.FindAll(r.Context())
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusOK, newIdeaResponses(ideas))
+}
+
+func (h *IdeaHandler) ListByUser(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	userId, err := pathID(r, "id")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	ideas, err := h.repo.FindByUserId(r.Context(), userId)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusOK, newIdeaResponses(ideas))
+}
+
+func (h *IdeaHandler) Get(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	idea, err := h.repo.FindById(r.Context(), id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusOK, newIdeaResponse(&idea))
+}
+
+func (h *IdeaHandler) Update(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	var req updateIdeaRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	idea, err := h.findOwnIdea(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	req.ToDomain(&idea)
+	if err = h.repo.UpdateById(r.Context(), &idea); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusOK, newIdeaResponse(&idea))
+}
+
+func (h *IdeaHandler) Delete(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	idea, err := h.findOwnIdea(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err = h.repo.DeleteById(r.Context(), idea.ID); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(stdhttp.StatusNoContent)
+}
+
+// findOwnIdea returns [ErrForbidden] if the idea from the path belongs
+// to another user. It reads from the primary, so that the update or
+// the deletion isn't based on a stale replica
+func (h *IdeaHandler) findOwnIdea(r *stdhttp.Request) (domain.Idea, error) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		return domain.Idea{}, err
+	}
+
+	idea, err := h.repo.FindById(postgresdb.WithPrimary(r.Context()), id)
+	if err != nil {
+		return domain.Idea{}, err
+	}
+
+	if idea.User.ID != currentSession(r).User.ID {
+		return domain.Idea{}, ErrForbidden
+	}
+
+	return idea, nil
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxIdeaTitleLength = 128
+
+// createIdeaRequest has no owner, because the
+// idea is owned by the authenticated user
+type createIdeaRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func (r *createIdeaRequest) Validate() error {
+	if err := validateIdeaTitle(r.Title); err != nil {
+		return err
+	}
+
+	return validateIdeaBody(r.Body)
+}
+
+func (r *createIdeaRequest) ToDomain(i *domain.Idea) {
+	i.Title = r.Title
+	i.Body = r.Body
+}
+
+// updateIdeaRequest contains optional fields, so only the
+// specified ones will be changed
+type updateIdeaRequest struct {
+	Title *string `json:"title"`
+	Body  *string `json:"body"`
+}
+
+func (r *updateIdeaRequest) Validate() error {
+	if r.Title != nil {
+		if err := validateIdeaTitle(*r.Title); err != nil {
+			return err
+		}
+	}
+
+	if r.Body != nil {
+		return validateIdeaBody(*r.Body)
+	}
+
+	return nil
+}
+
+func (r *updateIdeaRequest) ToDomain(i *domain.Idea) {
+	if r.Title != nil {
+		i.Title = *r.Title
+	}
+
+	if r.Body != nil {
+		i.Body = *r.Body
+	}
+}
+
+type ideaResponse struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newIdeaResponse(i *domain.Idea) ideaResponse {
+	return ideaResponse{
+		ID:        i.ID,
+		UserID:    i.User.ID,
+		Title:     i.Title,
+		Body:      i.Body,
+		CreatedAt: i.CreatedAt,
+		UpdatedAt: i.UpdatedAt,
+	}
+}
+
+func newIdeaResponses(ideas []domain.Idea) []ideaResponse {
+	res := make([]ideaResponse, 0, len(ideas))
+	for i := range ideas {
+		res = append(res, newIdeaResponse(&ideas[i]))
+	}
+
+	return res
+}
+
+func validateIdeaTitle(title string) error {
+	n := utf8.RuneCountInString(strings.TrimSpace(title))
+	if n == 0 || utf8.RuneCountInString(title) > maxIdeaTitleLength {
+		return fmt.Errorf("%w: title must contain from 1 to %d characters", ErrValidation, maxIdeaTitleLength)
+	}
+
+	return nil
+}
+
+func validateIdeaBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("%w: body must not be empty", ErrValidation)
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package http
+
+import (
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	_repoMock "github.com/adanyl0v/pocket-ideas/internal/repository/mocks"
+	pgrepo "github.com/adanyl0v/pocket-ideas/internal/repository/postgres"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"github.com/golang/mock/gomock"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type (
+	ideaHandlerTestCaseRegister func(_ *gomock.Controller, repo *_repoMock.MockIdeaRepository)
+	ideaHandlerTestCaseRequest  func() *stdhttp.Request
+	ideaHandlerTestCaseExpect   func(rec *httptest.ResponseRecorder)
+
+	ideaHandlerTestCase struct {
+		reg ideaHandlerTestCaseRegister
+		req ideaHandlerTestCaseRequest
+		exp ideaHandlerTestCaseExpect
+	}
+)
+
+func TestIdeaHandler_Create(t *testing.T) {
+	const body = `{"title":"idea","body":"text"}`
+	tcs := map[string]ideaHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(_ any, idea *domain.Idea) error {
+						require.Equal(t, testUserId, idea.User.ID)
+						return nil
+					})
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodPost, "/api/v1/ideas", strings.NewReader(body)))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusCreated, rec.Code)
+			},
+		},
+		"FAILED empty title": {
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodPost, "/api/v1/ideas",
+					strings.NewReader(`{"title":" ","body":"text"}`)))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusBadRequest, rec.Code)
+			},
+		},
+		"FAILED idea owner not found": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Times(1).
+					Return(proxerr.New(pgrepo.ErrIdeaOwnerNotFound, ""))
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodPost, "/api/v1/ideas", strings.NewReader(body)))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusUnprocessableEntity, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestIdeaHandler_ListByUser(t *testing.T) {
+	const userId = "0194f8c2-4d5e-7f6a-8b7c-9d0e1f2a3b4c"
+	tcs := map[string]ideaHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindByUserId(gomock.Any(), userId).Times(1).Return([]domain.Idea{{}}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet, "/api/v1/users/"+userId+"/ideas", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusOK, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestIdeaHandler_Update(t *testing.T) {
+	const id = "0194f8c3-5e6f-7a8b-9c0d-1e2f3a4b5c6d"
+	tcs := map[string]ideaHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindById(gomock.Any(), id).Times(1).
+					Return(domain.Idea{ID: id, User: domain.User{ID: testUserId}, Title: "idea", Body: "text"}, nil)
+				repo.EXPECT().UpdateById(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(_ any, idea *domain.Idea) error {
+						require.Equal(t, "idea", idea.Title)
+						require.Equal(t, "updated", idea.Body)
+						return nil
+					})
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodPatch, "/api/v1/ideas/"+id,
+					strings.NewReader(`{"body":"updated"}`)))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusOK, rec.Code)
+			},
+		},
+		"FAILED owned by another user": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindById(gomock.Any(), id).Times(1).
+					Return(domain.Idea{ID: id, User: domain.User{ID: "0194f8c5-7a8b-7c9d-8e0f-1a2b3c4d5e6f"}}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodPatch, "/api/v1/ideas/"+id,
+					strings.NewReader(`{"body":"updated"}`)))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusForbidden, rec.Code)
+			},
+		},
+		"FAILED idea not found": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindById(gomock.Any(), id).Times(1).
+					Return(domain.Idea{}, proxerr.New(pgrepo.ErrIdeaNotFound, ""))
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodPatch, "/api/v1/ideas/"+id,
+					strings.NewReader(`{"body":"updated"}`)))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestIdeaHandler_Delete(t *testing.T) {
+	const id = "0194f8c4-6f7a-7b8c-9d0e-1f2a3b4c5d6e"
+	tcs := map[string]ideaHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindById(gomock.Any(), id).Times(1).
+					Return(domain.Idea{ID: id, User: domain.User{ID: testUserId}}, nil)
+				repo.EXPECT().DeleteById(gomock.Any(), id).Times(1).Return(nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodDelete, "/api/v1/ideas/"+id, nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNoContent, rec.Code)
+			},
+		},
+		"FAILED owned by another user": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindById(gomock.Any(), id).Times(1).
+					Return(domain.Idea{ID: id, User: domain.User{ID: "0194f8c5-7a8b-7c9d-8e0f-1a2b3c4d5e6f"}}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodDelete, "/api/v1/ideas/"+id, nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusForbidden, rec.Code)
+			},
+		},
+		"FAILED unauthenticated": {
+			req: func() *stdhttp.Request {
+				return httptest.NewRequest(stdhttp.MethodDelete, "/api/v1/ideas/"+id, nil)
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusUnauthorized, rec.Code)
+			},
+		},
+		"FAILED invalid id": {
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodDelete, "/api/v1/ideas/abc", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runIdeaHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+// runIdeaHandlerTestCase should be called by [testing.T.Run]
+func runIdeaHandlerTestCase(t *testing.T, tc *ideaHandlerTestCase) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := _repoMock.NewMockIdeaRepository(ctrl)
+	if tc.reg != nil {
+		tc.reg(ctrl, repo)
+	}
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	router := NewRouter(logger, NewIdeaHandler(repo, testAuthenticator{}, logger))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, tc.req())
+
+	if tc.exp != nil {
+		tc.exp(rec)
+	}
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	stdhttp "net/http"
+	"time"
+)
+
+func NewRouter(logger log.Logger, handlers ...Handler) stdhttp.Handler {
+	mux := stdhttp.NewServeMux()
+	for _, h := range handlers {
+		h.Register(mux)
+	}
+
+	return recoverer(logger, requestLogger(logger, mux))
+}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	stdhttp.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func requestLogger(logger log.Logger, next stdhttp.Handler) stdhttp.Handler {
+	return stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: stdhttp.StatusOK}
+
+		now := time.Now()
+		next.ServeHTTP(rec, r)
+		t := time.Since(now)
+
+		logger.With(log.Fields{
+			"method":      r.Method,
+			"path":        r.URL.Path,
+			"status":      rec.status,
+			"remote_addr": r.RemoteAddr,
+			"duration":    t,
+		}).Debug("handled an http request")
+	})
+}
+
+func recoverer(logger log.Logger, next stdhttp.Handler) stdhttp.Handler {
+	return stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				logger.With(log.Fields{
+					"method": r.Method,
+					"path":   r.URL.Path,
+					"panic":  v,
+				}).Error("recovered from a panic in an http handler")
+
+				_ = encodeError(w, stdhttp.StatusInternalServerError,
+					stdhttp.StatusText(stdhttp.StatusInternalServerError))
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	stdhttp "net/http"
+	"time"
+)
+
+type Config struct {
+	Address      string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+}
+
+type Server struct {
+	server *stdhttp.Server
+	logger log.Logger
+}
+
+func NewServer(logger log.Logger, config *Config, handler stdhttp.Handler) *Server {
+	return &Server{
+		server: &stdhttp.Server{
+			Addr:              config.Address,
+			Handler:           handler,
+			ReadTimeout:       config.ReadTimeout,
+			ReadHeaderTimeout: config.ReadTimeout,
+			WriteTimeout:      config.WriteTimeout,
+			IdleTimeout:       config.IdleTimeout,
+		},
+		logger: logger,
+	}
+}
+
+// ListenAndServe blocks until the server is shut down
+func (s *Server) ListenAndServe() error {
+	s.logger.With(log.Fields{
+		"address":       s.server.Addr,
+		"read_timeout":  s.server.ReadTimeout,
+		"write_timeout": s.server.WriteTimeout,
+		"idle_timeout":  s.server.IdleTimeout,
+	}).Info("started the http server")
+
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, stdhttp.ErrServerClosed) {
+		s.logger.WithError(err).Error("failed to serve http")
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.logger.WithError(err).Error("failed to shut down the http server")
+		return err
+	}
+
+	s.logger.Info("shut down the http server")
+	return nil
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"github.com/adanyl0v/pocket-ideas/internal/repository"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	stdhttp "net/http"
+)
+
+// SessionHandler only serves the sessions of the authenticated user
+type SessionHandler struct {
+	handler
+	repo repository.AuthRepository
+}
+
+func NewSessionHandler(repo repository.AuthRepository, auth Authenticator, logger log.Logger) *SessionHandler {
+	return &SessionHandler{
+		handler: handler{
+			auth:   auth,
+			logger: logger,
+		},
+		repo: repo,
+	}
+}
+
+func (h *SessionHandler) Register(mux *stdhttp.ServeMux) {
+	mux.HandleFunc("GET /api/v1/sessions", h.authenticated(h.List))
+	mux.HandleFunc("GET /api/v1/sessions/{id}", h.authenticated(h.Get))
+	mux.HandleFunc("DELETE /api/v1/sessions/{id}", h.authenticated(h.Delete))
+}
+
+func (h *SessionHandler) Get(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	session, err := h.findOwnSession(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusOK, newSessionResponse(&session))
+}
+
+func (h *SessionHandler) List(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	sessions, err := h.repo.FindSessionsByUserId(r.Context(), currentSession(r).User.ID)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusOK, newSessionResponses(sessions))
+}
+
+// Delete revokes the session and its access tokens
+func (h *SessionHandler) Delete(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	session, err := h.findOwnSession(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err = h.repo.DeleteSessionById(r.Context(), session.ID); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err = h.repo.DeleteSessionAccessTokensFromWhitelist(r.Context(), session.ID); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(stdhttp.StatusNoContent)
+}
+
+// findOwnSession returns [ErrForbidden] if the session
+// from the path belongs to another user
+func (h *SessionHandler) findOwnSession(r *stdhttp.Request) (domain.Session, error) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	session, err := h.repo.FindSessionById(r.Context(), id)
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	if session.User.ID != currentSession(r).User.ID {
+		return domain.Session{}, ErrForbidden
+	}
+
+	return session, nil
+}
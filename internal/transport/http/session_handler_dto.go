@@ -0,0 +1,44 @@
+package http
+
+import (
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"time"
+)
+
+type fingerprintResponse struct {
+	ClientIP  string `json:"client_ip"`
+	UserAgent string `json:"user_agent"`
+}
+
+// sessionResponse intentionally omits the refresh token
+type sessionResponse struct {
+	ID          string              `json:"id"`
+	UserID      string              `json:"user_id"`
+	Fingerprint fingerprintResponse `json:"fingerprint"`
+	ExpiresAt   time.Time           `json:"expires_at"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+func newSessionResponse(s *domain.Session) sessionResponse {
+	return sessionResponse{
+		ID:     s.ID,
+		UserID: s.User.ID,
+		Fingerprint: fingerprintResponse{
+			ClientIP:  s.Fingerprint.ClientIP,
+			UserAgent: s.Fingerprint.UserAgent,
+		},
+		ExpiresAt: s.ExpiresAt,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func newSessionResponses(sessions []domain.Session) []sessionResponse {
+	res := make([]sessionResponse, 0, len(sessions))
+	for i := range sessions {
+		res = append(res, newSessionResponse(&sessions[i]))
+	}
+
+	return res
+}
@@ -0,0 +1,189 @@
+package http
+
+import (
+	"errors"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	_repoMock "github.com/adanyl0v/pocket-ideas/internal/repository/mocks"
+	redisrepo "github.com/adanyl0v/pocket-ideas/internal/repository/redis"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"github.com/golang/mock/gomock"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type (
+	sessionHandlerTestCaseRegister func(_ *gomock.Controller, repo *_repoMock.MockAuthRepository)
+	sessionHandlerTestCaseRequest  func() *stdhttp.Request
+	sessionHandlerTestCaseExpect   func(rec *httptest.ResponseRecorder)
+
+	sessionHandlerTestCase struct {
+		reg sessionHandlerTestCaseRegister
+		req sessionHandlerTestCaseRequest
+		exp sessionHandlerTestCaseExpect
+	}
+)
+
+func TestSessionHandler_Get(t *testing.T) {
+	const id = "0194f8d1-7a8b-7c9d-8e0f-1a2b3c4d5e6f"
+	tcs := map[string]sessionHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockAuthRepository) {
+				repo.EXPECT().FindSessionById(gomock.Any(), id).Times(1).
+					Return(domain.Session{ID: id, User: domain.User{ID: testUserId}, RefreshToken: "secret"}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet, "/api/v1/sessions/"+id, nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusOK, rec.Code)
+				require.NotContains(t, rec.Body.String(), "secret")
+			},
+		},
+		"FAILED session of another user": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockAuthRepository) {
+				repo.EXPECT().FindSessionById(gomock.Any(), id).Times(1).
+					Return(domain.Session{ID: id, User: domain.User{ID: "0194f8d4-0d1e-7f2a-9b3c-4d5e6f7a8b9c"}}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet, "/api/v1/sessions/"+id, nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusForbidden, rec.Code)
+			},
+		},
+		"FAILED session not found": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockAuthRepository) {
+				repo.EXPECT().FindSessionById(gomock.Any(), id).Times(1).
+					Return(domain.Session{}, proxerr.New(redisrepo.ErrNotFound, ""))
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet, "/api/v1/sessions/"+id, nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNotFound, rec.Code)
+			},
+		},
+		"FAILED unauthenticated": {
+			req: func() *stdhttp.Request {
+				return httptest.NewRequest(stdhttp.MethodGet, "/api/v1/sessions/"+id, nil)
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusUnauthorized, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runSessionHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestSessionHandler_List(t *testing.T) {
+	tcs := map[string]sessionHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockAuthRepository) {
+				repo.EXPECT().FindSessionsByUserId(gomock.Any(), testUserId).Times(1).
+					Return([]domain.Session{{}, {}}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet, "/api/v1/sessions", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusOK, rec.Code)
+			},
+		},
+		"FAILED": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockAuthRepository) {
+				repo.EXPECT().FindSessionsByUserId(gomock.Any(), testUserId).Times(1).
+					Return(nil, errors.New(""))
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet, "/api/v1/sessions", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusInternalServerError, rec.Code)
+			},
+		},
+		"FAILED sessions of other users are not served": {
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet,
+					"/api/v1/users/0194f8d2-8b9c-7d0e-9f1a-2b3c4d5e6f7a/sessions", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runSessionHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestSessionHandler_Delete(t *testing.T) {
+	const id = "0194f8d3-9c0d-7e1f-8a2b-3c4d5e6f7a8b"
+	tcs := map[string]sessionHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockAuthRepository) {
+				repo.EXPECT().FindSessionById(gomock.Any(), id).Times(1).
+					Return(domain.Session{ID: id, User: domain.User{ID: testUserId}}, nil)
+				repo.EXPECT().DeleteSessionById(gomock.Any(), id).Times(1).Return(nil)
+				repo.EXPECT().DeleteSessionAccessTokensFromWhitelist(gomock.Any(), id).Times(1).Return(nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodDelete, "/api/v1/sessions/"+id, nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNoContent, rec.Code)
+			},
+		},
+		"FAILED session of another user": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockAuthRepository) {
+				repo.EXPECT().FindSessionById(gomock.Any(), id).Times(1).
+					Return(domain.Session{ID: id, User: domain.User{ID: "0194f8d4-0d1e-7f2a-9b3c-4d5e6f7a8b9c"}}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodDelete, "/api/v1/sessions/"+id, nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusForbidden, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runSessionHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+// runSessionHandlerTestCase should be called by [testing.T.Run]
+func runSessionHandlerTestCase(t *testing.T, tc *sessionHandlerTestCase) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := _repoMock.NewMockAuthRepository(ctrl)
+	if tc.reg != nil {
+		tc.reg(ctrl, repo)
+	}
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	router := NewRouter(logger, NewSessionHandler(repo, testAuthenticator{}, logger))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, tc.req())
+
+	if tc.exp != nil {
+		tc.exp(rec)
+	}
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"context"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"github.com/adanyl0v/pocket-ideas/internal/repository"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	postgresdb "github.com/adanyl0v/pocket-ideas/pkg/database/postgres/pgx"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"github.com/adanyl0v/pocket-ideas/pkg/password"
+	stdhttp "net/http"
+)
+
+// TxRunner runs the function in a transaction, which the repositories
+// pick up from the context, so their writes are atomic
+type TxRunner interface {
+	RunInTx(ctx context.Context, opts *database.TxOptions, fn func(ctx context.Context) error) error
+}
+
+type UserHandler struct {
+	handler
+	repo     repository.UserRepository
+	ideaRepo repository.IdeaRepository
+	txRunner TxRunner
+	hasher   password.Hasher
+}
+
+func NewUserHandler(
+	repo repository.UserRepository,
+	ideaRepo repository.IdeaRepository,
+	txRunner TxRunner,
+	hasher password.Hasher,
+	auth Authenticator,
+	logger log.Logger,
+) *UserHandler {
+	return &UserHandler{
+		handler: handler{
+			auth:   auth,
+			logger: logger,
+		},
+		repo:     repo,
+		ideaRepo: ideaRepo,
+		txRunner: txRunner,
+		hasher:   hasher,
+	}
+}
+
+func (h *UserHandler) Register(mux *stdhttp.ServeMux) {
+	mux.HandleFunc("GET /api/v1/users", h.authenticated(h.List))
+	mux.HandleFunc("GET /api/v1/users/{id}", h.authenticated(h.Get))
+	mux.HandleFunc("PATCH /api/v1/users/me", h.authenticated(h.Update))
+	mux.HandleFunc("DELETE /api/v1/users/me", h.authenticated(h.Delete))
+}
+
+// List returns all users or only the ones with the name
+// specified in the "name" query parameter
+func (h *UserHandler) List(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	var (
+		users []domain.User
+		err   error
+	)
+	if name := r.URL.Query().Get("name"); name != "" {
+		users, err = h.repo.FindByName(r.Context(), name)
+	} else {
+		users, err = h.repo.FindAll(r.Context())
+	}
+
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusOK, newUserResponses(users))
+}
+
+func (h *UserHandler) Get(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	user, err := h.repo.FindById(r.Context(), id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusOK, newUserResponse(&user))
+}
+
+// Update changes the authenticated user
+func (h *UserHandler) Update(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	var req updateUserRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	// The user is read from the primary, so that
+	// the update isn't based on a stale replica
+	user, err := h.repo.FindById(postgresdb.WithPrimary(r.Context()), currentSession(r).User.ID)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	req.ToDomain(&user)
+	if req.Password != nil {
+		if err = h.hashPassword(&user); err != nil {
+			h.respondError(w, err)
+			return
+		}
+	}
+
+	if err = h.repo.UpdateById(r.Context(), &user); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respond(w, stdhttp.StatusOK, newUserResponse(&user))
+}
+
+// Delete deletes the authenticated user together with their ideas
+func (h *UserHandler) Delete(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	userId := currentSession(r).User.ID
+	err := h.txRunner.RunInTx(r.Context(), nil, func(ctx context.Context) error {
+		if err := h.ideaRepo.DeleteByUserId(ctx, userId); err != nil {
+			return err
+		}
+
+		return h.repo.DeleteById(ctx, userId)
+	})
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(stdhttp.StatusNoContent)
+}
+
+// hashPassword replaces the plaintext password of the user with its hash
+func (h *UserHandler) hashPassword(user *domain.User) error {
+	hash, err := h.hasher.Hash(user.Password)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to hash a password")
+		return err
+	}
+
+	user.Password = hash
+	return nil
+}
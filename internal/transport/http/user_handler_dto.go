@@ -0,0 +1,145 @@
+package http
+
+import (
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	"net/mail"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxUserNameLength  = 64
+	maxUserEmailLength = 254
+	minPasswordLength  = 8
+
+	// maxPasswordBytes is the bcrypt input limit
+	maxPasswordBytes = 72
+)
+
+type createUserRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (r *createUserRequest) Validate() error {
+	if err := validateUserName(r.Name); err != nil {
+		return err
+	}
+
+	if err := validateUserEmail(r.Email); err != nil {
+		return err
+	}
+
+	return validateUserPassword(r.Password)
+}
+
+func (r *createUserRequest) ToDomain(u *domain.User) {
+	u.Name = r.Name
+	u.Email = r.Email
+	u.Password = r.Password
+}
+
+// updateUserRequest contains optional fields, so only the
+// specified ones will be changed
+type updateUserRequest struct {
+	Name     *string `json:"name"`
+	Email    *string `json:"email"`
+	Password *string `json:"password"`
+}
+
+func (r *updateUserRequest) Validate() error {
+	if r.Name != nil {
+		if err := validateUserName(*r.Name); err != nil {
+			return err
+		}
+	}
+
+	if r.Email != nil {
+		if err := validateUserEmail(*r.Email); err != nil {
+			return err
+		}
+	}
+
+	if r.Password != nil {
+		return validateUserPassword(*r.Password)
+	}
+
+	return nil
+}
+
+func (r *updateUserRequest) ToDomain(u *domain.User) {
+	if r.Name != nil {
+		u.Name = *r.Name
+	}
+
+	if r.Email != nil {
+		u.Email = *r.Email
+	}
+
+	if r.Password != nil {
+		u.Password = *r.Password
+	}
+}
+
+type userResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newUserResponse(u *domain.User) userResponse {
+	return userResponse{
+		ID:        u.ID,
+		Name:      u.Name,
+		Email:     u.Email,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+func newUserResponses(users []domain.User) []userResponse {
+	res := make([]userResponse, 0, len(users))
+	for i := range users {
+		res = append(res, newUserResponse(&users[i]))
+	}
+
+	return res
+}
+
+func validateUserName(name string) error {
+	n := utf8.RuneCountInString(name)
+	if n == 0 || n > maxUserNameLength {
+		return fmt.Errorf("%w: name must contain from 1 to %d characters", ErrValidation, maxUserNameLength)
+	}
+
+	return nil
+}
+
+func validateUserEmail(email string) error {
+	if len(email) > maxUserEmailLength {
+		return fmt.Errorf("%w: email must not be longer than %d characters", ErrValidation, maxUserEmailLength)
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return fmt.Errorf("%w: invalid email", ErrValidation)
+	}
+
+	return nil
+}
+
+func validateUserPassword(password string) error {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return fmt.Errorf("%w: password must contain at least %d characters", ErrValidation, minPasswordLength)
+	}
+
+	if len(password) > maxPasswordBytes {
+		return fmt.Errorf("%w: password must not be longer than %d bytes", ErrValidation, maxPasswordBytes)
+	}
+
+	return nil
+}
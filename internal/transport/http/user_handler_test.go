@@ -0,0 +1,278 @@
+package http
+
+import (
+	"errors"
+	"github.com/adanyl0v/pocket-ideas/internal/domain"
+	_repoMock "github.com/adanyl0v/pocket-ideas/internal/repository/mocks"
+	pgrepo "github.com/adanyl0v/pocket-ideas/internal/repository/postgres"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/adanyl0v/pocket-ideas/pkg/password/bcrypt"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"github.com/golang/mock/gomock"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type (
+	userHandlerTestCaseRegister func(_ *gomock.Controller, repo *_repoMock.MockUserRepository, ideaRepo *_repoMock.MockIdeaRepository)
+	userHandlerTestCaseRequest  func() *stdhttp.Request
+	userHandlerTestCaseExpect   func(rec *httptest.ResponseRecorder)
+
+	userHandlerTestCase struct {
+		reg userHandlerTestCaseRegister
+		req userHandlerTestCaseRequest
+		exp userHandlerTestCaseExpect
+	}
+)
+
+func TestUserHandler_List(t *testing.T) {
+	tcs := map[string]userHandlerTestCase{
+		"SUCCESS all": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockUserRepository, _ *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindAll(gomock.Any()).Times(1).Return([]domain.User{{}, {}}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet, "/api/v1/users", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusOK, rec.Code)
+			},
+		},
+		"SUCCESS by name": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockUserRepository, _ *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindByName(gomock.Any(), "user").Times(1).Return([]domain.User{}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet, "/api/v1/users?name=user", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusOK, rec.Code)
+				require.Equal(t, "[]\n", rec.Body.String())
+			},
+		},
+		"FAILED users are only created by the registration": {
+			req: func() *stdhttp.Request {
+				return httptest.NewRequest(stdhttp.MethodPost, "/api/v1/users",
+					strings.NewReader(`{"name":"user","email":"user@example.com","password":"password"}`))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusMethodNotAllowed, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runUserHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestUserHandler_Get(t *testing.T) {
+	const id = "0194f8b2-6a1e-7b3c-8d4f-5e6a7b8c9d0e"
+	tcs := map[string]userHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockUserRepository, _ *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindById(gomock.Any(), id).Times(1).Return(domain.User{ID: id}, nil)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet, "/api/v1/users/"+id, nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusOK, rec.Code)
+			},
+		},
+		"FAILED invalid id": {
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet, "/api/v1/users/123", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusBadRequest, rec.Code)
+			},
+		},
+		"FAILED user not found": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockUserRepository, _ *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindById(gomock.Any(), id).Times(1).
+					Return(domain.User{}, proxerr.New(pgrepo.ErrUserNotFound, ""))
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodGet, "/api/v1/users/"+id, nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runUserHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestUserHandler_Update(t *testing.T) {
+	const id = testUserId
+	tcs := map[string]userHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockUserRepository, _ *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindById(gomock.Any(), id).Times(1).
+					Return(domain.User{ID: id, Name: "user", Email: "user@example.com"}, nil)
+				repo.EXPECT().UpdateById(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(_ any, user *domain.User) error {
+						require.Equal(t, "renamed", user.Name)
+						require.Equal(t, "user@example.com", user.Email)
+						return nil
+					})
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodPatch, "/api/v1/users/me",
+					strings.NewReader(`{"name":"renamed"}`)))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusOK, rec.Code)
+			},
+		},
+		"SUCCESS hashes a password": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockUserRepository, _ *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindById(gomock.Any(), id).Times(1).
+					Return(domain.User{ID: id, Name: "user", Email: "user@example.com", Password: "hash"}, nil)
+				repo.EXPECT().UpdateById(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(_ any, user *domain.User) error {
+						require.True(t, strings.HasPrefix(user.Password, "$2a$"))
+						return nil
+					})
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodPatch, "/api/v1/users/me",
+					strings.NewReader(`{"password":"new password"}`)))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusOK, rec.Code)
+				require.NotContains(t, rec.Body.String(), "password")
+			},
+		},
+		"FAILED user not found": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockUserRepository, _ *_repoMock.MockIdeaRepository) {
+				repo.EXPECT().FindById(gomock.Any(), id).Times(1).
+					Return(domain.User{}, proxerr.New(pgrepo.ErrUserNotFound, ""))
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodPatch, "/api/v1/users/me",
+					strings.NewReader(`{"name":"renamed"}`)))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNotFound, rec.Code)
+			},
+		},
+		"FAILED validation": {
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodPatch, "/api/v1/users/me",
+					strings.NewReader(`{"password":"short"}`)))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runUserHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestUserHandler_Delete(t *testing.T) {
+	const id = testUserId
+	tcs := map[string]userHandlerTestCase{
+		"SUCCESS": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockUserRepository, ideaRepo *_repoMock.MockIdeaRepository) {
+				gomock.InOrder(
+					ideaRepo.EXPECT().DeleteByUserId(gomock.Any(), id).Times(1).Return(nil),
+					repo.EXPECT().DeleteById(gomock.Any(), id).Times(1).Return(nil),
+				)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodDelete, "/api/v1/users/me", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNoContent, rec.Code)
+			},
+		},
+		"FAILED user not found": {
+			reg: func(_ *gomock.Controller, repo *_repoMock.MockUserRepository, ideaRepo *_repoMock.MockIdeaRepository) {
+				ideaRepo.EXPECT().DeleteByUserId(gomock.Any(), id).Times(1).Return(nil)
+				repo.EXPECT().DeleteById(gomock.Any(), id).Times(1).Return(pgrepo.ErrUserNotFound)
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodDelete, "/api/v1/users/me", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusNotFound, rec.Code)
+			},
+		},
+		"FAILED to delete ideas": {
+			reg: func(_ *gomock.Controller, _ *_repoMock.MockUserRepository, ideaRepo *_repoMock.MockIdeaRepository) {
+				ideaRepo.EXPECT().DeleteByUserId(gomock.Any(), id).Times(1).Return(errors.New(""))
+			},
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodDelete, "/api/v1/users/me", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusInternalServerError, rec.Code)
+			},
+		},
+		"FAILED unauthenticated": {
+			req: func() *stdhttp.Request {
+				return httptest.NewRequest(stdhttp.MethodDelete, "/api/v1/users/me", nil)
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusUnauthorized, rec.Code)
+			},
+		},
+		"FAILED another user": {
+			req: func() *stdhttp.Request {
+				return authenticate(httptest.NewRequest(stdhttp.MethodDelete,
+					"/api/v1/users/0194f8b4-3c5d-7e6f-8a9b-0c1d2e3f4a5b", nil))
+			},
+			exp: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, stdhttp.StatusMethodNotAllowed, rec.Code)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runUserHandlerTestCase(t, &tc)
+		})
+	}
+}
+
+// runUserHandlerTestCase should be called by [testing.T.Run]
+func runUserHandlerTestCase(t *testing.T, tc *userHandlerTestCase) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := _repoMock.NewMockUserRepository(ctrl)
+	ideaRepo := _repoMock.NewMockIdeaRepository(ctrl)
+	if tc.reg != nil {
+		tc.reg(ctrl, repo, ideaRepo)
+	}
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	handler := NewUserHandler(repo, ideaRepo, testTxRunner{}, bcrypt.New(bcrypt.MinCost), testAuthenticator{}, logger)
+	router := NewRouter(logger, handler)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, tc.req())
+
+	if tc.exp != nil {
+		tc.exp(rec)
+	}
+}
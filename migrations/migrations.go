@@ -0,0 +1,21 @@
+// Package migrations embeds the SQL migrations, so that
+// they are shipped within the application binary
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed postgres/*.sql
+var postgres embed.FS
+
+// Postgres returns the migrations of the postgres database
+func Postgres() fs.FS {
+	sub, err := fs.Sub(postgres, "postgres")
+	if err != nil {
+		panic(err)
+	}
+
+	return sub
+}
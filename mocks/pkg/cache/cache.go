@@ -78,243 +78,355 @@ func (mr *MockScanIteratorMockRecorder) Val() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Val", reflect.TypeOf((*MockScanIterator)(nil).Val))
 }
 
-// MockScanner is a mock of Scanner interface.
-type MockScanner struct {
+// MockConn is a mock of Conn interface.
+type MockConn struct {
 	ctrl     *gomock.Controller
-	recorder *MockScannerMockRecorder
+	recorder *MockConnMockRecorder
 }
 
-// MockScannerMockRecorder is the mock recorder for MockScanner.
-type MockScannerMockRecorder struct {
-	mock *MockScanner
+// MockConnMockRecorder is the mock recorder for MockConn.
+type MockConnMockRecorder struct {
+	mock *MockConn
 }
 
-// NewMockScanner creates a new mock instance.
-func NewMockScanner(ctrl *gomock.Controller) *MockScanner {
-	mock := &MockScanner{ctrl: ctrl}
-	mock.recorder = &MockScannerMockRecorder{mock}
+// NewMockConn creates a new mock instance.
+func NewMockConn(ctrl *gomock.Controller) *MockConn {
+	mock := &MockConn{ctrl: ctrl}
+	mock.recorder = &MockConnMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockScanner) EXPECT() *MockScannerMockRecorder {
+func (m *MockConn) EXPECT() *MockConnMockRecorder {
 	return m.recorder
 }
 
-// Scan mocks base method.
-func (m *MockScanner) Scan(ctx context.Context) cache.ScanIterator {
+// Begin mocks base method.
+func (m *MockConn) Begin(ctx context.Context) cache.Tx {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Scan", ctx)
-	ret0, _ := ret[0].(cache.ScanIterator)
+	ret := m.ctrl.Call(m, "Begin", ctx)
+	ret0, _ := ret[0].(cache.Tx)
 	return ret0
 }
 
-// Scan indicates an expected call of Scan.
-func (mr *MockScannerMockRecorder) Scan(ctx interface{}) *gomock.Call {
+// Begin indicates an expected call of Begin.
+func (mr *MockConnMockRecorder) Begin(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockScanner)(nil).Scan), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockConn)(nil).Begin), ctx)
 }
 
-// MockCursorScanner is a mock of CursorScanner interface.
-type MockCursorScanner struct {
-	ctrl     *gomock.Controller
-	recorder *MockCursorScannerMockRecorder
+// Delete mocks base method.
+func (m *MockConn) Delete(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockCursorScannerMockRecorder is the mock recorder for MockCursorScanner.
-type MockCursorScannerMockRecorder struct {
-	mock *MockCursorScanner
+// Delete indicates an expected call of Delete.
+func (mr *MockConnMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockConn)(nil).Delete), ctx, key)
 }
 
-// NewMockCursorScanner creates a new mock instance.
-func NewMockCursorScanner(ctrl *gomock.Controller) *MockCursorScanner {
-	mock := &MockCursorScanner{ctrl: ctrl}
-	mock.recorder = &MockCursorScannerMockRecorder{mock}
-	return mock
+// Exists mocks base method.
+func (m *MockConn) Exists(ctx context.Context, keys ...string) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Exists", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockCursorScanner) EXPECT() *MockCursorScannerMockRecorder {
-	return m.recorder
+// Exists indicates an expected call of Exists.
+func (mr *MockConnMockRecorder) Exists(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockConn)(nil).Exists), varargs...)
 }
 
-// Scan mocks base method.
-func (m *MockCursorScanner) Scan(ctx context.Context) cache.ScanIterator {
+// Expire mocks base method.
+func (m *MockConn) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Scan", ctx)
-	ret0, _ := ret[0].(cache.ScanIterator)
-	return ret0
+	ret := m.ctrl.Call(m, "Expire", ctx, key, expiration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Scan indicates an expected call of Scan.
-func (mr *MockCursorScannerMockRecorder) Scan(ctx interface{}) *gomock.Call {
+// Expire indicates an expected call of Expire.
+func (mr *MockConnMockRecorder) Expire(ctx, key, expiration interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockCursorScanner)(nil).Scan), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockConn)(nil).Expire), ctx, key, expiration)
 }
 
-// WithArgs mocks base method.
-func (m *MockCursorScanner) WithArgs(cursor uint64, match string, count int64) cache.CursorScanner {
+// Get mocks base method.
+func (m *MockConn) Get(ctx context.Context, key string, dest any) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WithArgs", cursor, match, count)
-	ret0, _ := ret[0].(cache.CursorScanner)
+	ret := m.ctrl.Call(m, "Get", ctx, key, dest)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// WithArgs indicates an expected call of WithArgs.
-func (mr *MockCursorScannerMockRecorder) WithArgs(cursor, match, count interface{}) *gomock.Call {
+// Get indicates an expected call of Get.
+func (mr *MockConnMockRecorder) Get(ctx, key, dest interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithArgs", reflect.TypeOf((*MockCursorScanner)(nil).WithArgs), cursor, match, count)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockConn)(nil).Get), ctx, key, dest)
 }
 
-// MockKeyCursorScanner is a mock of KeyCursorScanner interface.
-type MockKeyCursorScanner struct {
-	ctrl     *gomock.Controller
-	recorder *MockKeyCursorScannerMockRecorder
+// HDel mocks base method.
+func (m *MockConn) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HDel", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockKeyCursorScannerMockRecorder is the mock recorder for MockKeyCursorScanner.
-type MockKeyCursorScannerMockRecorder struct {
-	mock *MockKeyCursorScanner
+// HDel indicates an expected call of HDel.
+func (mr *MockConnMockRecorder) HDel(ctx, key interface{}, fields ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockConn)(nil).HDel), varargs...)
 }
 
-// NewMockKeyCursorScanner creates a new mock instance.
-func NewMockKeyCursorScanner(ctrl *gomock.Controller) *MockKeyCursorScanner {
-	mock := &MockKeyCursorScanner{ctrl: ctrl}
-	mock.recorder = &MockKeyCursorScannerMockRecorder{mock}
-	return mock
+// HGet mocks base method.
+func (m *MockConn) HGet(ctx context.Context, key, field string, dest any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGet", ctx, key, field, dest)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockKeyCursorScanner) EXPECT() *MockKeyCursorScannerMockRecorder {
-	return m.recorder
+// HGet indicates an expected call of HGet.
+func (mr *MockConnMockRecorder) HGet(ctx, key, field, dest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockConn)(nil).HGet), ctx, key, field, dest)
+}
+
+// HGetAll mocks base method.
+func (m *MockConn) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGetAll", ctx, key)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGetAll indicates an expected call of HGetAll.
+func (mr *MockConnMockRecorder) HGetAll(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGetAll", reflect.TypeOf((*MockConn)(nil).HGetAll), ctx, key)
 }
 
-// Scan mocks base method.
-func (m *MockKeyCursorScanner) Scan(ctx context.Context) cache.ScanIterator {
+// HScan mocks base method.
+func (m *MockConn) HScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Scan", ctx)
+	ret := m.ctrl.Call(m, "HScan", ctx, key, match, count)
 	ret0, _ := ret[0].(cache.ScanIterator)
 	return ret0
 }
 
-// Scan indicates an expected call of Scan.
-func (mr *MockKeyCursorScannerMockRecorder) Scan(ctx interface{}) *gomock.Call {
+// HScan indicates an expected call of HScan.
+func (mr *MockConnMockRecorder) HScan(ctx, key, match, count interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockKeyCursorScanner)(nil).Scan), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HScan", reflect.TypeOf((*MockConn)(nil).HScan), ctx, key, match, count)
 }
 
-// WithArgs mocks base method.
-func (m *MockKeyCursorScanner) WithArgs(key string, cursor uint64, match string, count int64) cache.KeyCursorScanner {
+// HSet mocks base method.
+func (m *MockConn) HSet(ctx context.Context, key string, values ...any) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WithArgs", key, cursor, match, count)
-	ret0, _ := ret[0].(cache.KeyCursorScanner)
-	return ret0
+	varargs := []interface{}{ctx, key}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HSet", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// WithArgs indicates an expected call of WithArgs.
-func (mr *MockKeyCursorScannerMockRecorder) WithArgs(key, cursor, match, count interface{}) *gomock.Call {
+// HSet indicates an expected call of HSet.
+func (mr *MockConnMockRecorder) HSet(ctx, key interface{}, values ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithArgs", reflect.TypeOf((*MockKeyCursorScanner)(nil).WithArgs), key, cursor, match, count)
+	varargs := append([]interface{}{ctx, key}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockConn)(nil).HSet), varargs...)
 }
 
-// MockConn is a mock of Conn interface.
-type MockConn struct {
-	ctrl     *gomock.Controller
-	recorder *MockConnMockRecorder
+// Incr mocks base method.
+func (m *MockConn) Incr(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockConnMockRecorder is the mock recorder for MockConn.
-type MockConnMockRecorder struct {
-	mock *MockConn
+// Incr indicates an expected call of Incr.
+func (mr *MockConnMockRecorder) Incr(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockConn)(nil).Incr), ctx, key)
 }
 
-// NewMockConn creates a new mock instance.
-func NewMockConn(ctrl *gomock.Controller) *MockConn {
-	mock := &MockConn{ctrl: ctrl}
-	mock.recorder = &MockConnMockRecorder{mock}
-	return mock
+// IncrBy mocks base method.
+func (m *MockConn) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrBy", ctx, key, value)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockConn) EXPECT() *MockConnMockRecorder {
-	return m.recorder
+// IncrBy indicates an expected call of IncrBy.
+func (mr *MockConnMockRecorder) IncrBy(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrBy", reflect.TypeOf((*MockConn)(nil).IncrBy), ctx, key, value)
 }
 
-// Begin mocks base method.
-func (m *MockConn) Begin(ctx context.Context) cache.Tx {
+// MGet mocks base method.
+func (m *MockConn) MGet(ctx context.Context, keys ...string) ([]any, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Begin", ctx)
-	ret0, _ := ret[0].(cache.Tx)
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MGet", varargs...)
+	ret0, _ := ret[0].([]any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MGet indicates an expected call of MGet.
+func (mr *MockConnMockRecorder) MGet(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MGet", reflect.TypeOf((*MockConn)(nil).MGet), varargs...)
+}
+
+// MSet mocks base method.
+func (m *MockConn) MSet(ctx context.Context, values ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MSet", varargs...)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Begin indicates an expected call of Begin.
-func (mr *MockConnMockRecorder) Begin(ctx interface{}) *gomock.Call {
+// MSet indicates an expected call of MSet.
+func (mr *MockConnMockRecorder) MSet(ctx interface{}, values ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockConn)(nil).Begin), ctx)
+	varargs := append([]interface{}{ctx}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MSet", reflect.TypeOf((*MockConn)(nil).MSet), varargs...)
 }
 
-// Delete mocks base method.
-func (m *MockConn) Delete(ctx context.Context, key string) (int64, error) {
+// Persist mocks base method.
+func (m *MockConn) Persist(ctx context.Context, key string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret := m.ctrl.Call(m, "Persist", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Persist indicates an expected call of Persist.
+func (mr *MockConnMockRecorder) Persist(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Persist", reflect.TypeOf((*MockConn)(nil).Persist), ctx, key)
+}
+
+// SAdd mocks base method.
+func (m *MockConn) SAdd(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SAdd", varargs...)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockConnMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+// SAdd indicates an expected call of SAdd.
+func (mr *MockConnMockRecorder) SAdd(ctx, key interface{}, members ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockConn)(nil).Delete), ctx, key)
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockConn)(nil).SAdd), varargs...)
 }
 
-// Exists mocks base method.
-func (m *MockConn) Exists(ctx context.Context, keys ...string) (int64, error) {
+// SMembers mocks base method.
+func (m *MockConn) SMembers(ctx context.Context, key string) ([]string, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx}
-	for _, a := range keys {
+	ret := m.ctrl.Call(m, "SMembers", ctx, key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMembers indicates an expected call of SMembers.
+func (mr *MockConnMockRecorder) SMembers(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockConn)(nil).SMembers), ctx, key)
+}
+
+// SRem mocks base method.
+func (m *MockConn) SRem(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "Exists", varargs...)
+	ret := m.ctrl.Call(m, "SRem", varargs...)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Exists indicates an expected call of Exists.
-func (mr *MockConnMockRecorder) Exists(ctx interface{}, keys ...interface{}) *gomock.Call {
+// SRem indicates an expected call of SRem.
+func (mr *MockConnMockRecorder) SRem(ctx, key interface{}, members ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx}, keys...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockConn)(nil).Exists), varargs...)
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRem", reflect.TypeOf((*MockConn)(nil).SRem), varargs...)
 }
 
-// Get mocks base method.
-func (m *MockConn) Get(ctx context.Context, key string, dest any) error {
+// SScan mocks base method.
+func (m *MockConn) SScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, key, dest)
-	ret0, _ := ret[0].(error)
+	ret := m.ctrl.Call(m, "SScan", ctx, key, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
 	return ret0
 }
 
-// Get indicates an expected call of Get.
-func (mr *MockConnMockRecorder) Get(ctx, key, dest interface{}) *gomock.Call {
+// SScan indicates an expected call of SScan.
+func (mr *MockConnMockRecorder) SScan(ctx, key, match, count interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockConn)(nil).Get), ctx, key, dest)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SScan", reflect.TypeOf((*MockConn)(nil).SScan), ctx, key, match, count)
 }
 
-// Scan mocks base method.
-func (m *MockConn) Scan(ctx context.Context, scanner cache.Scanner) cache.ScanIterator {
+// ScanKeys mocks base method.
+func (m *MockConn) ScanKeys(ctx context.Context, match string, count int64) cache.ScanIterator {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Scan", ctx, scanner)
+	ret := m.ctrl.Call(m, "ScanKeys", ctx, match, count)
 	ret0, _ := ret[0].(cache.ScanIterator)
 	return ret0
 }
 
-// Scan indicates an expected call of Scan.
-func (mr *MockConnMockRecorder) Scan(ctx, scanner interface{}) *gomock.Call {
+// ScanKeys indicates an expected call of ScanKeys.
+func (mr *MockConnMockRecorder) ScanKeys(ctx, match, count interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockConn)(nil).Scan), ctx, scanner)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanKeys", reflect.TypeOf((*MockConn)(nil).ScanKeys), ctx, match, count)
 }
 
 // Set mocks base method.
@@ -331,6 +443,90 @@ func (mr *MockConnMockRecorder) Set(ctx, key, value, expiration interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockConn)(nil).Set), ctx, key, value, expiration)
 }
 
+// TTL mocks base method.
+func (m *MockConn) TTL(ctx context.Context, key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TTL", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TTL indicates an expected call of TTL.
+func (mr *MockConnMockRecorder) TTL(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TTL", reflect.TypeOf((*MockConn)(nil).TTL), ctx, key)
+}
+
+// ZAdd mocks base method.
+func (m *MockConn) ZAdd(ctx context.Context, key string, members ...cache.Z) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZAdd", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZAdd indicates an expected call of ZAdd.
+func (mr *MockConnMockRecorder) ZAdd(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockConn)(nil).ZAdd), varargs...)
+}
+
+// ZRangeByScore mocks base method.
+func (m *MockConn) ZRangeByScore(ctx context.Context, key string, opt *cache.ZRangeBy) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScore", ctx, key, opt)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRangeByScore indicates an expected call of ZRangeByScore.
+func (mr *MockConnMockRecorder) ZRangeByScore(ctx, key, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScore", reflect.TypeOf((*MockConn)(nil).ZRangeByScore), ctx, key, opt)
+}
+
+// ZRem mocks base method.
+func (m *MockConn) ZRem(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZRem", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRem indicates an expected call of ZRem.
+func (mr *MockConnMockRecorder) ZRem(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockConn)(nil).ZRem), varargs...)
+}
+
+// ZScan mocks base method.
+func (m *MockConn) ZScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZScan", ctx, key, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// ZScan indicates an expected call of ZScan.
+func (mr *MockConnMockRecorder) ZScan(ctx, key, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZScan", reflect.TypeOf((*MockConn)(nil).ZScan), ctx, key, match, count)
+}
+
 // MockTx is a mock of Tx interface.
 type MockTx struct {
 	ctrl     *gomock.Controller
@@ -431,6 +627,21 @@ func (mr *MockTxMockRecorder) Exists(ctx interface{}, keys ...interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockTx)(nil).Exists), varargs...)
 }
 
+// Expire mocks base method.
+func (m *MockTx) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx, key, expiration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockTxMockRecorder) Expire(ctx, key, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockTx)(nil).Expire), ctx, key, expiration)
+}
+
 // Get mocks base method.
 func (m *MockTx) Get(ctx context.Context, key string, dest any) error {
 	m.ctrl.T.Helper()
@@ -445,30 +656,1286 @@ func (mr *MockTxMockRecorder) Get(ctx, key, dest interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTx)(nil).Get), ctx, key, dest)
 }
 
-// Scan mocks base method.
-func (m *MockTx) Scan(ctx context.Context, scanner cache.Scanner) cache.ScanIterator {
+// HDel mocks base method.
+func (m *MockTx) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Scan", ctx, scanner)
-	ret0, _ := ret[0].(cache.ScanIterator)
-	return ret0
+	varargs := []interface{}{ctx, key}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HDel", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Scan indicates an expected call of Scan.
-func (mr *MockTxMockRecorder) Scan(ctx, scanner interface{}) *gomock.Call {
+// HDel indicates an expected call of HDel.
+func (mr *MockTxMockRecorder) HDel(ctx, key interface{}, fields ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockTx)(nil).Scan), ctx, scanner)
+	varargs := append([]interface{}{ctx, key}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockTx)(nil).HDel), varargs...)
 }
 
-// Set mocks base method.
-func (m *MockTx) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+// HGet mocks base method.
+func (m *MockTx) HGet(ctx context.Context, key, field string, dest any) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", ctx, key, value, expiration)
+	ret := m.ctrl.Call(m, "HGet", ctx, key, field, dest)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Set indicates an expected call of Set.
-func (mr *MockTxMockRecorder) Set(ctx, key, value, expiration interface{}) *gomock.Call {
+// HGet indicates an expected call of HGet.
+func (mr *MockTxMockRecorder) HGet(ctx, key, field, dest interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockTx)(nil).Set), ctx, key, value, expiration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockTx)(nil).HGet), ctx, key, field, dest)
+}
+
+// HGetAll mocks base method.
+func (m *MockTx) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGetAll", ctx, key)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGetAll indicates an expected call of HGetAll.
+func (mr *MockTxMockRecorder) HGetAll(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGetAll", reflect.TypeOf((*MockTx)(nil).HGetAll), ctx, key)
+}
+
+// HScan mocks base method.
+func (m *MockTx) HScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HScan", ctx, key, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// HScan indicates an expected call of HScan.
+func (mr *MockTxMockRecorder) HScan(ctx, key, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HScan", reflect.TypeOf((*MockTx)(nil).HScan), ctx, key, match, count)
+}
+
+// HSet mocks base method.
+func (m *MockTx) HSet(ctx context.Context, key string, values ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HSet", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HSet indicates an expected call of HSet.
+func (mr *MockTxMockRecorder) HSet(ctx, key interface{}, values ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockTx)(nil).HSet), varargs...)
+}
+
+// Incr mocks base method.
+func (m *MockTx) Incr(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Incr indicates an expected call of Incr.
+func (mr *MockTxMockRecorder) Incr(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockTx)(nil).Incr), ctx, key)
+}
+
+// IncrBy mocks base method.
+func (m *MockTx) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrBy", ctx, key, value)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrBy indicates an expected call of IncrBy.
+func (mr *MockTxMockRecorder) IncrBy(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrBy", reflect.TypeOf((*MockTx)(nil).IncrBy), ctx, key, value)
+}
+
+// MGet mocks base method.
+func (m *MockTx) MGet(ctx context.Context, keys ...string) ([]any, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MGet", varargs...)
+	ret0, _ := ret[0].([]any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MGet indicates an expected call of MGet.
+func (mr *MockTxMockRecorder) MGet(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MGet", reflect.TypeOf((*MockTx)(nil).MGet), varargs...)
+}
+
+// MSet mocks base method.
+func (m *MockTx) MSet(ctx context.Context, values ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MSet", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MSet indicates an expected call of MSet.
+func (mr *MockTxMockRecorder) MSet(ctx interface{}, values ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MSet", reflect.TypeOf((*MockTx)(nil).MSet), varargs...)
+}
+
+// Persist mocks base method.
+func (m *MockTx) Persist(ctx context.Context, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Persist", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Persist indicates an expected call of Persist.
+func (mr *MockTxMockRecorder) Persist(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Persist", reflect.TypeOf((*MockTx)(nil).Persist), ctx, key)
+}
+
+// SAdd mocks base method.
+func (m *MockTx) SAdd(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SAdd", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SAdd indicates an expected call of SAdd.
+func (mr *MockTxMockRecorder) SAdd(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockTx)(nil).SAdd), varargs...)
+}
+
+// SMembers mocks base method.
+func (m *MockTx) SMembers(ctx context.Context, key string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMembers", ctx, key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMembers indicates an expected call of SMembers.
+func (mr *MockTxMockRecorder) SMembers(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockTx)(nil).SMembers), ctx, key)
+}
+
+// SRem mocks base method.
+func (m *MockTx) SRem(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SRem", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SRem indicates an expected call of SRem.
+func (mr *MockTxMockRecorder) SRem(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRem", reflect.TypeOf((*MockTx)(nil).SRem), varargs...)
+}
+
+// SScan mocks base method.
+func (m *MockTx) SScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SScan", ctx, key, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// SScan indicates an expected call of SScan.
+func (mr *MockTxMockRecorder) SScan(ctx, key, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SScan", reflect.TypeOf((*MockTx)(nil).SScan), ctx, key, match, count)
+}
+
+// ScanKeys mocks base method.
+func (m *MockTx) ScanKeys(ctx context.Context, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanKeys", ctx, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// ScanKeys indicates an expected call of ScanKeys.
+func (mr *MockTxMockRecorder) ScanKeys(ctx, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanKeys", reflect.TypeOf((*MockTx)(nil).ScanKeys), ctx, match, count)
+}
+
+// Set mocks base method.
+func (m *MockTx) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockTxMockRecorder) Set(ctx, key, value, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockTx)(nil).Set), ctx, key, value, expiration)
+}
+
+// TTL mocks base method.
+func (m *MockTx) TTL(ctx context.Context, key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TTL", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TTL indicates an expected call of TTL.
+func (mr *MockTxMockRecorder) TTL(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TTL", reflect.TypeOf((*MockTx)(nil).TTL), ctx, key)
+}
+
+// ZAdd mocks base method.
+func (m *MockTx) ZAdd(ctx context.Context, key string, members ...cache.Z) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZAdd", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZAdd indicates an expected call of ZAdd.
+func (mr *MockTxMockRecorder) ZAdd(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockTx)(nil).ZAdd), varargs...)
+}
+
+// ZRangeByScore mocks base method.
+func (m *MockTx) ZRangeByScore(ctx context.Context, key string, opt *cache.ZRangeBy) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScore", ctx, key, opt)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRangeByScore indicates an expected call of ZRangeByScore.
+func (mr *MockTxMockRecorder) ZRangeByScore(ctx, key, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScore", reflect.TypeOf((*MockTx)(nil).ZRangeByScore), ctx, key, opt)
+}
+
+// ZRem mocks base method.
+func (m *MockTx) ZRem(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZRem", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRem indicates an expected call of ZRem.
+func (mr *MockTxMockRecorder) ZRem(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockTx)(nil).ZRem), varargs...)
+}
+
+// ZScan mocks base method.
+func (m *MockTx) ZScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZScan", ctx, key, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// ZScan indicates an expected call of ZScan.
+func (mr *MockTxMockRecorder) ZScan(ctx, key, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZScan", reflect.TypeOf((*MockTx)(nil).ZScan), ctx, key, match, count)
+}
+
+// MockWatcher is a mock of Watcher interface.
+type MockWatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatcherMockRecorder
+}
+
+// MockWatcherMockRecorder is the mock recorder for MockWatcher.
+type MockWatcherMockRecorder struct {
+	mock *MockWatcher
+}
+
+// NewMockWatcher creates a new mock instance.
+func NewMockWatcher(ctrl *gomock.Controller) *MockWatcher {
+	mock := &MockWatcher{ctrl: ctrl}
+	mock.recorder = &MockWatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatcher) EXPECT() *MockWatcherMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockWatcher) Begin(ctx context.Context) cache.Tx {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx)
+	ret0, _ := ret[0].(cache.Tx)
+	return ret0
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockWatcherMockRecorder) Begin(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockWatcher)(nil).Begin), ctx)
+}
+
+// Delete mocks base method.
+func (m *MockWatcher) Delete(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWatcherMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWatcher)(nil).Delete), ctx, key)
+}
+
+// Exists mocks base method.
+func (m *MockWatcher) Exists(ctx context.Context, keys ...string) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Exists", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockWatcherMockRecorder) Exists(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockWatcher)(nil).Exists), varargs...)
+}
+
+// Expire mocks base method.
+func (m *MockWatcher) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx, key, expiration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockWatcherMockRecorder) Expire(ctx, key, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockWatcher)(nil).Expire), ctx, key, expiration)
+}
+
+// Get mocks base method.
+func (m *MockWatcher) Get(ctx context.Context, key string, dest any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key, dest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWatcherMockRecorder) Get(ctx, key, dest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWatcher)(nil).Get), ctx, key, dest)
+}
+
+// HDel mocks base method.
+func (m *MockWatcher) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HDel", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HDel indicates an expected call of HDel.
+func (mr *MockWatcherMockRecorder) HDel(ctx, key interface{}, fields ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockWatcher)(nil).HDel), varargs...)
+}
+
+// HGet mocks base method.
+func (m *MockWatcher) HGet(ctx context.Context, key, field string, dest any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGet", ctx, key, field, dest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HGet indicates an expected call of HGet.
+func (mr *MockWatcherMockRecorder) HGet(ctx, key, field, dest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockWatcher)(nil).HGet), ctx, key, field, dest)
+}
+
+// HGetAll mocks base method.
+func (m *MockWatcher) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGetAll", ctx, key)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGetAll indicates an expected call of HGetAll.
+func (mr *MockWatcherMockRecorder) HGetAll(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGetAll", reflect.TypeOf((*MockWatcher)(nil).HGetAll), ctx, key)
+}
+
+// HScan mocks base method.
+func (m *MockWatcher) HScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HScan", ctx, key, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// HScan indicates an expected call of HScan.
+func (mr *MockWatcherMockRecorder) HScan(ctx, key, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HScan", reflect.TypeOf((*MockWatcher)(nil).HScan), ctx, key, match, count)
+}
+
+// HSet mocks base method.
+func (m *MockWatcher) HSet(ctx context.Context, key string, values ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HSet", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HSet indicates an expected call of HSet.
+func (mr *MockWatcherMockRecorder) HSet(ctx, key interface{}, values ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockWatcher)(nil).HSet), varargs...)
+}
+
+// Incr mocks base method.
+func (m *MockWatcher) Incr(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Incr indicates an expected call of Incr.
+func (mr *MockWatcherMockRecorder) Incr(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockWatcher)(nil).Incr), ctx, key)
+}
+
+// IncrBy mocks base method.
+func (m *MockWatcher) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrBy", ctx, key, value)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrBy indicates an expected call of IncrBy.
+func (mr *MockWatcherMockRecorder) IncrBy(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrBy", reflect.TypeOf((*MockWatcher)(nil).IncrBy), ctx, key, value)
+}
+
+// MGet mocks base method.
+func (m *MockWatcher) MGet(ctx context.Context, keys ...string) ([]any, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MGet", varargs...)
+	ret0, _ := ret[0].([]any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MGet indicates an expected call of MGet.
+func (mr *MockWatcherMockRecorder) MGet(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MGet", reflect.TypeOf((*MockWatcher)(nil).MGet), varargs...)
+}
+
+// MSet mocks base method.
+func (m *MockWatcher) MSet(ctx context.Context, values ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MSet", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MSet indicates an expected call of MSet.
+func (mr *MockWatcherMockRecorder) MSet(ctx interface{}, values ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MSet", reflect.TypeOf((*MockWatcher)(nil).MSet), varargs...)
+}
+
+// Persist mocks base method.
+func (m *MockWatcher) Persist(ctx context.Context, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Persist", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Persist indicates an expected call of Persist.
+func (mr *MockWatcherMockRecorder) Persist(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Persist", reflect.TypeOf((*MockWatcher)(nil).Persist), ctx, key)
+}
+
+// SAdd mocks base method.
+func (m *MockWatcher) SAdd(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SAdd", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SAdd indicates an expected call of SAdd.
+func (mr *MockWatcherMockRecorder) SAdd(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockWatcher)(nil).SAdd), varargs...)
+}
+
+// SMembers mocks base method.
+func (m *MockWatcher) SMembers(ctx context.Context, key string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMembers", ctx, key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMembers indicates an expected call of SMembers.
+func (mr *MockWatcherMockRecorder) SMembers(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockWatcher)(nil).SMembers), ctx, key)
+}
+
+// SRem mocks base method.
+func (m *MockWatcher) SRem(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SRem", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SRem indicates an expected call of SRem.
+func (mr *MockWatcherMockRecorder) SRem(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRem", reflect.TypeOf((*MockWatcher)(nil).SRem), varargs...)
+}
+
+// SScan mocks base method.
+func (m *MockWatcher) SScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SScan", ctx, key, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// SScan indicates an expected call of SScan.
+func (mr *MockWatcherMockRecorder) SScan(ctx, key, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SScan", reflect.TypeOf((*MockWatcher)(nil).SScan), ctx, key, match, count)
+}
+
+// ScanKeys mocks base method.
+func (m *MockWatcher) ScanKeys(ctx context.Context, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanKeys", ctx, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// ScanKeys indicates an expected call of ScanKeys.
+func (mr *MockWatcherMockRecorder) ScanKeys(ctx, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanKeys", reflect.TypeOf((*MockWatcher)(nil).ScanKeys), ctx, match, count)
+}
+
+// Set mocks base method.
+func (m *MockWatcher) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockWatcherMockRecorder) Set(ctx, key, value, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockWatcher)(nil).Set), ctx, key, value, expiration)
+}
+
+// TTL mocks base method.
+func (m *MockWatcher) TTL(ctx context.Context, key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TTL", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TTL indicates an expected call of TTL.
+func (mr *MockWatcherMockRecorder) TTL(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TTL", reflect.TypeOf((*MockWatcher)(nil).TTL), ctx, key)
+}
+
+// Watch mocks base method.
+func (m *MockWatcher) Watch(ctx context.Context, fn func(cache.WatchConn) error, keys ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, fn}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Watch", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockWatcherMockRecorder) Watch(ctx, fn interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, fn}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockWatcher)(nil).Watch), varargs...)
+}
+
+// ZAdd mocks base method.
+func (m *MockWatcher) ZAdd(ctx context.Context, key string, members ...cache.Z) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZAdd", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZAdd indicates an expected call of ZAdd.
+func (mr *MockWatcherMockRecorder) ZAdd(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockWatcher)(nil).ZAdd), varargs...)
+}
+
+// ZRangeByScore mocks base method.
+func (m *MockWatcher) ZRangeByScore(ctx context.Context, key string, opt *cache.ZRangeBy) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScore", ctx, key, opt)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRangeByScore indicates an expected call of ZRangeByScore.
+func (mr *MockWatcherMockRecorder) ZRangeByScore(ctx, key, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScore", reflect.TypeOf((*MockWatcher)(nil).ZRangeByScore), ctx, key, opt)
+}
+
+// ZRem mocks base method.
+func (m *MockWatcher) ZRem(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZRem", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRem indicates an expected call of ZRem.
+func (mr *MockWatcherMockRecorder) ZRem(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockWatcher)(nil).ZRem), varargs...)
+}
+
+// ZScan mocks base method.
+func (m *MockWatcher) ZScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZScan", ctx, key, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// ZScan indicates an expected call of ZScan.
+func (mr *MockWatcherMockRecorder) ZScan(ctx, key, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZScan", reflect.TypeOf((*MockWatcher)(nil).ZScan), ctx, key, match, count)
+}
+
+// MockWatchConn is a mock of WatchConn interface.
+type MockWatchConn struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchConnMockRecorder
+}
+
+// MockWatchConnMockRecorder is the mock recorder for MockWatchConn.
+type MockWatchConnMockRecorder struct {
+	mock *MockWatchConn
+}
+
+// NewMockWatchConn creates a new mock instance.
+func NewMockWatchConn(ctrl *gomock.Controller) *MockWatchConn {
+	mock := &MockWatchConn{ctrl: ctrl}
+	mock.recorder = &MockWatchConnMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchConn) EXPECT() *MockWatchConnMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockWatchConn) Begin(ctx context.Context) cache.Tx {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx)
+	ret0, _ := ret[0].(cache.Tx)
+	return ret0
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockWatchConnMockRecorder) Begin(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockWatchConn)(nil).Begin), ctx)
+}
+
+// Delete mocks base method.
+func (m *MockWatchConn) Delete(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWatchConnMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWatchConn)(nil).Delete), ctx, key)
+}
+
+// Exists mocks base method.
+func (m *MockWatchConn) Exists(ctx context.Context, keys ...string) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Exists", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockWatchConnMockRecorder) Exists(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockWatchConn)(nil).Exists), varargs...)
+}
+
+// Expire mocks base method.
+func (m *MockWatchConn) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx, key, expiration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockWatchConnMockRecorder) Expire(ctx, key, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockWatchConn)(nil).Expire), ctx, key, expiration)
+}
+
+// Get mocks base method.
+func (m *MockWatchConn) Get(ctx context.Context, key string, dest any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key, dest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWatchConnMockRecorder) Get(ctx, key, dest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWatchConn)(nil).Get), ctx, key, dest)
+}
+
+// HDel mocks base method.
+func (m *MockWatchConn) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HDel", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HDel indicates an expected call of HDel.
+func (mr *MockWatchConnMockRecorder) HDel(ctx, key interface{}, fields ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockWatchConn)(nil).HDel), varargs...)
+}
+
+// HGet mocks base method.
+func (m *MockWatchConn) HGet(ctx context.Context, key, field string, dest any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGet", ctx, key, field, dest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HGet indicates an expected call of HGet.
+func (mr *MockWatchConnMockRecorder) HGet(ctx, key, field, dest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockWatchConn)(nil).HGet), ctx, key, field, dest)
+}
+
+// HGetAll mocks base method.
+func (m *MockWatchConn) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGetAll", ctx, key)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGetAll indicates an expected call of HGetAll.
+func (mr *MockWatchConnMockRecorder) HGetAll(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGetAll", reflect.TypeOf((*MockWatchConn)(nil).HGetAll), ctx, key)
+}
+
+// HScan mocks base method.
+func (m *MockWatchConn) HScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HScan", ctx, key, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// HScan indicates an expected call of HScan.
+func (mr *MockWatchConnMockRecorder) HScan(ctx, key, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HScan", reflect.TypeOf((*MockWatchConn)(nil).HScan), ctx, key, match, count)
+}
+
+// HSet mocks base method.
+func (m *MockWatchConn) HSet(ctx context.Context, key string, values ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HSet", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HSet indicates an expected call of HSet.
+func (mr *MockWatchConnMockRecorder) HSet(ctx, key interface{}, values ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockWatchConn)(nil).HSet), varargs...)
+}
+
+// Incr mocks base method.
+func (m *MockWatchConn) Incr(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Incr indicates an expected call of Incr.
+func (mr *MockWatchConnMockRecorder) Incr(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockWatchConn)(nil).Incr), ctx, key)
+}
+
+// IncrBy mocks base method.
+func (m *MockWatchConn) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrBy", ctx, key, value)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrBy indicates an expected call of IncrBy.
+func (mr *MockWatchConnMockRecorder) IncrBy(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrBy", reflect.TypeOf((*MockWatchConn)(nil).IncrBy), ctx, key, value)
+}
+
+// MGet mocks base method.
+func (m *MockWatchConn) MGet(ctx context.Context, keys ...string) ([]any, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MGet", varargs...)
+	ret0, _ := ret[0].([]any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MGet indicates an expected call of MGet.
+func (mr *MockWatchConnMockRecorder) MGet(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MGet", reflect.TypeOf((*MockWatchConn)(nil).MGet), varargs...)
+}
+
+// MSet mocks base method.
+func (m *MockWatchConn) MSet(ctx context.Context, values ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MSet", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MSet indicates an expected call of MSet.
+func (mr *MockWatchConnMockRecorder) MSet(ctx interface{}, values ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MSet", reflect.TypeOf((*MockWatchConn)(nil).MSet), varargs...)
+}
+
+// Persist mocks base method.
+func (m *MockWatchConn) Persist(ctx context.Context, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Persist", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Persist indicates an expected call of Persist.
+func (mr *MockWatchConnMockRecorder) Persist(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Persist", reflect.TypeOf((*MockWatchConn)(nil).Persist), ctx, key)
+}
+
+// SAdd mocks base method.
+func (m *MockWatchConn) SAdd(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SAdd", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SAdd indicates an expected call of SAdd.
+func (mr *MockWatchConnMockRecorder) SAdd(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockWatchConn)(nil).SAdd), varargs...)
+}
+
+// SMembers mocks base method.
+func (m *MockWatchConn) SMembers(ctx context.Context, key string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMembers", ctx, key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMembers indicates an expected call of SMembers.
+func (mr *MockWatchConnMockRecorder) SMembers(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockWatchConn)(nil).SMembers), ctx, key)
+}
+
+// SRem mocks base method.
+func (m *MockWatchConn) SRem(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SRem", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SRem indicates an expected call of SRem.
+func (mr *MockWatchConnMockRecorder) SRem(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRem", reflect.TypeOf((*MockWatchConn)(nil).SRem), varargs...)
+}
+
+// SScan mocks base method.
+func (m *MockWatchConn) SScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SScan", ctx, key, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// SScan indicates an expected call of SScan.
+func (mr *MockWatchConnMockRecorder) SScan(ctx, key, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SScan", reflect.TypeOf((*MockWatchConn)(nil).SScan), ctx, key, match, count)
+}
+
+// ScanKeys mocks base method.
+func (m *MockWatchConn) ScanKeys(ctx context.Context, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanKeys", ctx, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// ScanKeys indicates an expected call of ScanKeys.
+func (mr *MockWatchConnMockRecorder) ScanKeys(ctx, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanKeys", reflect.TypeOf((*MockWatchConn)(nil).ScanKeys), ctx, match, count)
+}
+
+// Set mocks base method.
+func (m *MockWatchConn) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockWatchConnMockRecorder) Set(ctx, key, value, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockWatchConn)(nil).Set), ctx, key, value, expiration)
+}
+
+// TTL mocks base method.
+func (m *MockWatchConn) TTL(ctx context.Context, key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TTL", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TTL indicates an expected call of TTL.
+func (mr *MockWatchConnMockRecorder) TTL(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TTL", reflect.TypeOf((*MockWatchConn)(nil).TTL), ctx, key)
+}
+
+// Watch mocks base method.
+func (m *MockWatchConn) Watch(ctx context.Context, keys ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Watch", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockWatchConnMockRecorder) Watch(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockWatchConn)(nil).Watch), varargs...)
+}
+
+// ZAdd mocks base method.
+func (m *MockWatchConn) ZAdd(ctx context.Context, key string, members ...cache.Z) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZAdd", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZAdd indicates an expected call of ZAdd.
+func (mr *MockWatchConnMockRecorder) ZAdd(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockWatchConn)(nil).ZAdd), varargs...)
+}
+
+// ZRangeByScore mocks base method.
+func (m *MockWatchConn) ZRangeByScore(ctx context.Context, key string, opt *cache.ZRangeBy) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScore", ctx, key, opt)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRangeByScore indicates an expected call of ZRangeByScore.
+func (mr *MockWatchConnMockRecorder) ZRangeByScore(ctx, key, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScore", reflect.TypeOf((*MockWatchConn)(nil).ZRangeByScore), ctx, key, opt)
+}
+
+// ZRem mocks base method.
+func (m *MockWatchConn) ZRem(ctx context.Context, key string, members ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZRem", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRem indicates an expected call of ZRem.
+func (mr *MockWatchConnMockRecorder) ZRem(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockWatchConn)(nil).ZRem), varargs...)
+}
+
+// ZScan mocks base method.
+func (m *MockWatchConn) ZScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZScan", ctx, key, match, count)
+	ret0, _ := ret[0].(cache.ScanIterator)
+	return ret0
+}
+
+// ZScan indicates an expected call of ZScan.
+func (mr *MockWatchConnMockRecorder) ZScan(ctx, key, match, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZScan", reflect.TypeOf((*MockWatchConn)(nil).ZScan), ctx, key, match, count)
 }
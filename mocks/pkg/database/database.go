@@ -256,6 +256,87 @@ func (mr *MockRowsMockRecorder) Values() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Values", reflect.TypeOf((*MockRows)(nil).Values))
 }
 
+// MockBatchResults is a mock of BatchResults interface.
+type MockBatchResults struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchResultsMockRecorder
+}
+
+// MockBatchResultsMockRecorder is the mock recorder for MockBatchResults.
+type MockBatchResultsMockRecorder struct {
+	mock *MockBatchResults
+}
+
+// NewMockBatchResults creates a new mock instance.
+func NewMockBatchResults(ctrl *gomock.Controller) *MockBatchResults {
+	mock := &MockBatchResults{ctrl: ctrl}
+	mock.recorder = &MockBatchResultsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchResults) EXPECT() *MockBatchResultsMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockBatchResults) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockBatchResultsMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBatchResults)(nil).Close))
+}
+
+// Execute mocks base method.
+func (m *MockBatchResults) Execute() (database.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(database.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockBatchResultsMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockBatchResults)(nil).Execute))
+}
+
+// Query mocks base method.
+func (m *MockBatchResults) Query() (database.Rows, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Query")
+	ret0, _ := ret[0].(database.Rows)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockBatchResultsMockRecorder) Query() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockBatchResults)(nil).Query))
+}
+
+// QueryRow mocks base method.
+func (m *MockBatchResults) QueryRow() database.Row {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryRow")
+	ret0, _ := ret[0].(database.Row)
+	return ret0
+}
+
+// QueryRow indicates an expected call of QueryRow.
+func (mr *MockBatchResultsMockRecorder) QueryRow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRow", reflect.TypeOf((*MockBatchResults)(nil).QueryRow))
+}
+
 // MockConn is a mock of Conn interface.
 type MockConn struct {
 	ctrl     *gomock.Controller
@@ -294,6 +375,21 @@ func (mr *MockConnMockRecorder) Begin(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockConn)(nil).Begin), ctx)
 }
 
+// CopyFrom mocks base method.
+func (m *MockConn) CopyFrom(ctx context.Context, table, columns []string, src database.CopySource) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFrom", ctx, table, columns, src)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFrom indicates an expected call of CopyFrom.
+func (mr *MockConnMockRecorder) CopyFrom(ctx, table, columns, src interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFrom", reflect.TypeOf((*MockConn)(nil).CopyFrom), ctx, table, columns, src)
+}
+
 // Execute mocks base method.
 func (m *MockConn) Execute(ctx context.Context, query string, args ...any) (database.Result, error) {
 	m.ctrl.T.Helper()
@@ -353,6 +449,20 @@ func (mr *MockConnMockRecorder) QueryRow(ctx, query interface{}, args ...interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRow", reflect.TypeOf((*MockConn)(nil).QueryRow), varargs...)
 }
 
+// SendBatch mocks base method.
+func (m *MockConn) SendBatch(ctx context.Context, batch *database.Batch) database.BatchResults {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendBatch", ctx, batch)
+	ret0, _ := ret[0].(database.BatchResults)
+	return ret0
+}
+
+// SendBatch indicates an expected call of SendBatch.
+func (mr *MockConnMockRecorder) SendBatch(ctx, batch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBatch", reflect.TypeOf((*MockConn)(nil).SendBatch), ctx, batch)
+}
+
 // MockTx is a mock of Tx interface.
 type MockTx struct {
 	ctrl     *gomock.Controller
@@ -405,6 +515,21 @@ func (mr *MockTxMockRecorder) Commit(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockTx)(nil).Commit), ctx)
 }
 
+// CopyFrom mocks base method.
+func (m *MockTx) CopyFrom(ctx context.Context, table, columns []string, src database.CopySource) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFrom", ctx, table, columns, src)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFrom indicates an expected call of CopyFrom.
+func (mr *MockTxMockRecorder) CopyFrom(ctx, table, columns, src interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFrom", reflect.TypeOf((*MockTx)(nil).CopyFrom), ctx, table, columns, src)
+}
+
 // Execute mocks base method.
 func (m *MockTx) Execute(ctx context.Context, query string, args ...any) (database.Result, error) {
 	m.ctrl.T.Helper()
@@ -464,6 +589,20 @@ func (mr *MockTxMockRecorder) QueryRow(ctx, query interface{}, args ...interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRow", reflect.TypeOf((*MockTx)(nil).QueryRow), varargs...)
 }
 
+// Release mocks base method.
+func (m *MockTx) Release(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockTxMockRecorder) Release(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockTx)(nil).Release), ctx, name)
+}
+
 // Rollback mocks base method.
 func (m *MockTx) Rollback(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -477,3 +616,45 @@ func (mr *MockTxMockRecorder) Rollback(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockTx)(nil).Rollback), ctx)
 }
+
+// RollbackTo mocks base method.
+func (m *MockTx) RollbackTo(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackTo", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackTo indicates an expected call of RollbackTo.
+func (mr *MockTxMockRecorder) RollbackTo(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackTo", reflect.TypeOf((*MockTx)(nil).RollbackTo), ctx, name)
+}
+
+// Savepoint mocks base method.
+func (m *MockTx) Savepoint(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Savepoint", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Savepoint indicates an expected call of Savepoint.
+func (mr *MockTxMockRecorder) Savepoint(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Savepoint", reflect.TypeOf((*MockTx)(nil).Savepoint), ctx, name)
+}
+
+// SendBatch mocks base method.
+func (m *MockTx) SendBatch(ctx context.Context, batch *database.Batch) database.BatchResults {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendBatch", ctx, batch)
+	ret0, _ := ret[0].(database.BatchResults)
+	return ret0
+}
+
+// SendBatch indicates an expected call of SendBatch.
+func (mr *MockTxMockRecorder) SendBatch(ctx, batch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBatch", reflect.TypeOf((*MockTx)(nil).SendBatch), ctx, batch)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/database/postgres/pgx/listener.go
+
+// Package mock_postgres is a generated GoMock package.
+package mock_postgres
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+)
+
+// MockDriverListenConn is a mock of DriverListenConn interface.
+type MockDriverListenConn struct {
+	ctrl     *gomock.Controller
+	recorder *MockDriverListenConnMockRecorder
+}
+
+// MockDriverListenConnMockRecorder is the mock recorder for MockDriverListenConn.
+type MockDriverListenConnMockRecorder struct {
+	mock *MockDriverListenConn
+}
+
+// NewMockDriverListenConn creates a new mock instance.
+func NewMockDriverListenConn(ctrl *gomock.Controller) *MockDriverListenConn {
+	mock := &MockDriverListenConn{ctrl: ctrl}
+	mock.recorder = &MockDriverListenConnMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDriverListenConn) EXPECT() *MockDriverListenConnMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockDriverListenConn) Close(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockDriverListenConnMockRecorder) Close(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDriverListenConn)(nil).Close), arg0)
+}
+
+// Exec mocks base method.
+func (m *MockDriverListenConn) Exec(arg0 context.Context, arg1 string, arg2 ...any) (pgconn.CommandTag, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Exec", varargs...)
+	ret0, _ := ret[0].(pgconn.CommandTag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exec indicates an expected call of Exec.
+func (mr *MockDriverListenConnMockRecorder) Exec(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockDriverListenConn)(nil).Exec), varargs...)
+}
+
+// WaitForNotification mocks base method.
+func (m *MockDriverListenConn) WaitForNotification(arg0 context.Context) (*pgconn.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForNotification", arg0)
+	ret0, _ := ret[0].(*pgconn.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForNotification indicates an expected call of WaitForNotification.
+func (mr *MockDriverListenConnMockRecorder) WaitForNotification(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForNotification", reflect.TypeOf((*MockDriverListenConn)(nil).WaitForNotification), arg0)
+}
@@ -206,6 +206,87 @@ func (mr *MockDriverRowsMockRecorder) Values() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Values", reflect.TypeOf((*MockDriverRows)(nil).Values))
 }
 
+// MockDriverBatchResults is a mock of DriverBatchResults interface.
+type MockDriverBatchResults struct {
+	ctrl     *gomock.Controller
+	recorder *MockDriverBatchResultsMockRecorder
+}
+
+// MockDriverBatchResultsMockRecorder is the mock recorder for MockDriverBatchResults.
+type MockDriverBatchResultsMockRecorder struct {
+	mock *MockDriverBatchResults
+}
+
+// NewMockDriverBatchResults creates a new mock instance.
+func NewMockDriverBatchResults(ctrl *gomock.Controller) *MockDriverBatchResults {
+	mock := &MockDriverBatchResults{ctrl: ctrl}
+	mock.recorder = &MockDriverBatchResultsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDriverBatchResults) EXPECT() *MockDriverBatchResultsMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockDriverBatchResults) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockDriverBatchResultsMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDriverBatchResults)(nil).Close))
+}
+
+// Exec mocks base method.
+func (m *MockDriverBatchResults) Exec() (pgconn.CommandTag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exec")
+	ret0, _ := ret[0].(pgconn.CommandTag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exec indicates an expected call of Exec.
+func (mr *MockDriverBatchResultsMockRecorder) Exec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockDriverBatchResults)(nil).Exec))
+}
+
+// Query mocks base method.
+func (m *MockDriverBatchResults) Query() (pgx.Rows, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Query")
+	ret0, _ := ret[0].(pgx.Rows)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockDriverBatchResultsMockRecorder) Query() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockDriverBatchResults)(nil).Query))
+}
+
+// QueryRow mocks base method.
+func (m *MockDriverBatchResults) QueryRow() pgx.Row {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryRow")
+	ret0, _ := ret[0].(pgx.Row)
+	return ret0
+}
+
+// QueryRow indicates an expected call of QueryRow.
+func (mr *MockDriverBatchResultsMockRecorder) QueryRow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRow", reflect.TypeOf((*MockDriverBatchResults)(nil).QueryRow))
+}
+
 // MockDriverConn is a mock of DriverConn interface.
 type MockDriverConn struct {
 	ctrl     *gomock.Controller
@@ -244,6 +325,21 @@ func (mr *MockDriverConnMockRecorder) Begin(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockDriverConn)(nil).Begin), arg0)
 }
 
+// CopyFrom mocks base method.
+func (m *MockDriverConn) CopyFrom(arg0 context.Context, arg1 pgx.Identifier, arg2 []string, arg3 pgx.CopyFromSource) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFrom", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFrom indicates an expected call of CopyFrom.
+func (mr *MockDriverConnMockRecorder) CopyFrom(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFrom", reflect.TypeOf((*MockDriverConn)(nil).CopyFrom), arg0, arg1, arg2, arg3)
+}
+
 // Exec mocks base method.
 func (m *MockDriverConn) Exec(arg0 context.Context, arg1 string, arg2 ...any) (pgconn.CommandTag, error) {
 	m.ctrl.T.Helper()
@@ -303,6 +399,20 @@ func (mr *MockDriverConnMockRecorder) QueryRow(arg0, arg1 interface{}, arg2 ...i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRow", reflect.TypeOf((*MockDriverConn)(nil).QueryRow), varargs...)
 }
 
+// SendBatch mocks base method.
+func (m *MockDriverConn) SendBatch(arg0 context.Context, arg1 *pgx.Batch) pgx.BatchResults {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendBatch", arg0, arg1)
+	ret0, _ := ret[0].(pgx.BatchResults)
+	return ret0
+}
+
+// SendBatch indicates an expected call of SendBatch.
+func (mr *MockDriverConnMockRecorder) SendBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBatch", reflect.TypeOf((*MockDriverConn)(nil).SendBatch), arg0, arg1)
+}
+
 // MockDriverTx is a mock of DriverTx interface.
 type MockDriverTx struct {
 	ctrl     *gomock.Controller
@@ -355,6 +465,21 @@ func (mr *MockDriverTxMockRecorder) Commit(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockDriverTx)(nil).Commit), arg0)
 }
 
+// CopyFrom mocks base method.
+func (m *MockDriverTx) CopyFrom(arg0 context.Context, arg1 pgx.Identifier, arg2 []string, arg3 pgx.CopyFromSource) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFrom", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFrom indicates an expected call of CopyFrom.
+func (mr *MockDriverTxMockRecorder) CopyFrom(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFrom", reflect.TypeOf((*MockDriverTx)(nil).CopyFrom), arg0, arg1, arg2, arg3)
+}
+
 // Exec mocks base method.
 func (m *MockDriverTx) Exec(arg0 context.Context, arg1 string, arg2 ...any) (pgconn.CommandTag, error) {
 	m.ctrl.T.Helper()
@@ -427,3 +552,17 @@ func (mr *MockDriverTxMockRecorder) Rollback(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockDriverTx)(nil).Rollback), arg0)
 }
+
+// SendBatch mocks base method.
+func (m *MockDriverTx) SendBatch(arg0 context.Context, arg1 *pgx.Batch) pgx.BatchResults {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendBatch", arg0, arg1)
+	ret0, _ := ret[0].(pgx.BatchResults)
+	return ret0
+}
+
+// SendBatch indicates an expected call of SendBatch.
+func (mr *MockDriverTxMockRecorder) SendBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBatch", reflect.TypeOf((*MockDriverTx)(nil).SendBatch), arg0, arg1)
+}
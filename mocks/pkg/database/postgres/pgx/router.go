@@ -0,0 +1,165 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/database/postgres/pgx/router.go
+
+// Package mock_postgres is a generated GoMock package.
+package mock_postgres
+
+import (
+	context "context"
+	reflect "reflect"
+
+	database "github.com/adanyl0v/pocket-ideas/pkg/database"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRouterConn is a mock of RouterConn interface.
+type MockRouterConn struct {
+	ctrl     *gomock.Controller
+	recorder *MockRouterConnMockRecorder
+}
+
+// MockRouterConnMockRecorder is the mock recorder for MockRouterConn.
+type MockRouterConnMockRecorder struct {
+	mock *MockRouterConn
+}
+
+// NewMockRouterConn creates a new mock instance.
+func NewMockRouterConn(ctrl *gomock.Controller) *MockRouterConn {
+	mock := &MockRouterConn{ctrl: ctrl}
+	mock.recorder = &MockRouterConnMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRouterConn) EXPECT() *MockRouterConnMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockRouterConn) Begin(ctx context.Context) (database.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx)
+	ret0, _ := ret[0].(database.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockRouterConnMockRecorder) Begin(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockRouterConn)(nil).Begin), ctx)
+}
+
+// Close mocks base method.
+func (m *MockRouterConn) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockRouterConnMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRouterConn)(nil).Close))
+}
+
+// CopyFrom mocks base method.
+func (m *MockRouterConn) CopyFrom(ctx context.Context, table, columns []string, src database.CopySource) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFrom", ctx, table, columns, src)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFrom indicates an expected call of CopyFrom.
+func (mr *MockRouterConnMockRecorder) CopyFrom(ctx, table, columns, src interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFrom", reflect.TypeOf((*MockRouterConn)(nil).CopyFrom), ctx, table, columns, src)
+}
+
+// Execute mocks base method.
+func (m *MockRouterConn) Execute(ctx context.Context, query string, args ...any) (database.Result, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, query}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Execute", varargs...)
+	ret0, _ := ret[0].(database.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockRouterConnMockRecorder) Execute(ctx, query interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, query}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockRouterConn)(nil).Execute), varargs...)
+}
+
+// Ping mocks base method.
+func (m *MockRouterConn) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockRouterConnMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockRouterConn)(nil).Ping), ctx)
+}
+
+// Query mocks base method.
+func (m *MockRouterConn) Query(ctx context.Context, query string, args ...any) (database.Rows, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, query}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Query", varargs...)
+	ret0, _ := ret[0].(database.Rows)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockRouterConnMockRecorder) Query(ctx, query interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, query}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockRouterConn)(nil).Query), varargs...)
+}
+
+// QueryRow mocks base method.
+func (m *MockRouterConn) QueryRow(ctx context.Context, query string, args ...any) database.Row {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, query}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryRow", varargs...)
+	ret0, _ := ret[0].(database.Row)
+	return ret0
+}
+
+// QueryRow indicates an expected call of QueryRow.
+func (mr *MockRouterConnMockRecorder) QueryRow(ctx, query interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, query}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRow", reflect.TypeOf((*MockRouterConn)(nil).QueryRow), varargs...)
+}
+
+// SendBatch mocks base method.
+func (m *MockRouterConn) SendBatch(ctx context.Context, batch *database.Batch) database.BatchResults {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendBatch", ctx, batch)
+	ret0, _ := ret[0].(database.BatchResults)
+	return ret0
+}
+
+// SendBatch indicates an expected call of SendBatch.
+func (mr *MockRouterConnMockRecorder) SendBatch(ctx, batch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBatch", reflect.TypeOf((*MockRouterConn)(nil).SendBatch), ctx, batch)
+}
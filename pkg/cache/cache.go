@@ -6,65 +6,107 @@ import (
 	"time"
 )
 
-var ErrKeyDoesNotExist = errors.New("key does not exist")
+var (
+	ErrKeyDoesNotExist = errors.New("key does not exist")
+	ErrTxFailed        = errors.New("a watched key was modified")
+)
 
-type (
-	ScanIterator interface {
-		Err() error
-		Val() string
-		Next(ctx context.Context) bool
-	}
+// NoExpiration is returned by TTL for the keys, which never expire
+const NoExpiration time.Duration = -1
 
-	Scanner interface {
-		Scan(ctx context.Context) ScanIterator
-	}
+// ScanIterator iterates all the elements matched by a scan. It fetches
+// the next batch of the elements, when the current one is exhausted
+type ScanIterator interface {
+	Err() error
+	Val() string
+	Next(ctx context.Context) bool
+}
 
-	CursorScanner interface {
-		Scanner
-		WithArgs(cursor uint64, match string, count int64) CursorScanner
+type (
+	// Z is a member of a sorted set
+	Z struct {
+		Score  float64
+		Member any
 	}
 
-	KeyCursorScanner interface {
-		Scanner
-		WithArgs(key string, cursor uint64, match string, count int64) KeyCursorScanner
+	// ZRangeBy limits the scores with Min and Max, which are either numbers,
+	// exclusive numbers prefixed with "(", "-inf" or "+inf". The members
+	// are not limited if both Offset and Count are 0, and the negative
+	// Count returns all the members after the Offset
+	ZRangeBy struct {
+		Min    string
+		Max    string
+		Offset int64
+		Count  int64
 	}
 )
 
-var (
-	// DefaultScanner corresponds to the Redis [SCAN] command
-	//
-	// [SCAN]: https://redis.io/docs/latest/commands/scan
-	DefaultScanner CursorScanner
-
-	// DefaultSetScanner corresponds to the Redis [SSCAN] command
-	//
-	// [SSCAN]: https://redis.io/docs/latest/commands/sscan
-	DefaultSetScanner KeyCursorScanner
-
-	// DefaultHashScanner corresponds to the Redis [HSCAN] command
-	//
-	// [HSCAN]: https://redis.io/docs/latest/commands/hscan
-	DefaultHashScanner KeyCursorScanner
-
-	// DefaultSortedSetScanner corresponds to the Redis [ZSCAN] command
-	//
-	// [ZSCAN]: https://redis.io/docs/latest/commands/zscan
-	DefaultSortedSetScanner KeyCursorScanner
-)
-
 type (
+	// Conn is a key-value store. Within a [Tx], the commands are queued
+	// and return zero values until [Tx.Exec] is called
 	Conn interface {
 		Get(ctx context.Context, key string, dest any) error
 		Set(ctx context.Context, key string, value any, expiration time.Duration) error
-		Scan(ctx context.Context, scanner Scanner) ScanIterator
+		MGet(ctx context.Context, keys ...string) ([]any, error)
+		MSet(ctx context.Context, values ...any) error
 		Delete(ctx context.Context, key string) (int64, error)
 		Exists(ctx context.Context, keys ...string) (int64, error)
+		Incr(ctx context.Context, key string) (int64, error)
+		IncrBy(ctx context.Context, key string, value int64) (int64, error)
+		HSet(ctx context.Context, key string, values ...any) (int64, error)
+		HGet(ctx context.Context, key, field string, dest any) error
+		HGetAll(ctx context.Context, key string) (map[string]string, error)
+		HDel(ctx context.Context, key string, fields ...string) (int64, error)
+		SAdd(ctx context.Context, key string, members ...any) (int64, error)
+		SRem(ctx context.Context, key string, members ...any) (int64, error)
+		SMembers(ctx context.Context, key string) ([]string, error)
+		ZAdd(ctx context.Context, key string, members ...Z) (int64, error)
+		ZRangeByScore(ctx context.Context, key string, opt *ZRangeBy) ([]string, error)
+		ZRem(ctx context.Context, key string, members ...any) (int64, error)
+		Expire(ctx context.Context, key string, expiration time.Duration) (bool, error)
+		TTL(ctx context.Context, key string) (time.Duration, error)
+		Persist(ctx context.Context, key string) (bool, error)
+
+		// ScanKeys corresponds to the Redis [SCAN] command. The keys are matched
+		// by the glob-style pattern, and the count is a hint of the batch size.
+		// A key may be returned more than once, if it is modified during the scan
+		//
+		// [SCAN]: https://redis.io/docs/latest/commands/scan
+		ScanKeys(ctx context.Context, match string, count int64) ScanIterator
+
+		// SScan iterates the members of the set
+		SScan(ctx context.Context, key, match string, count int64) ScanIterator
+
+		// HScan iterates the fields and the values of the hash in turns
+		HScan(ctx context.Context, key, match string, count int64) ScanIterator
+
+		// ZScan iterates the members and the scores of the sorted set in turns
+		ZScan(ctx context.Context, key, match string, count int64) ScanIterator
+
 		Begin(ctx context.Context) Tx
 	}
 
+	// Tx fails with [ErrTxFailed] on Exec, if it was begun on
+	// a [WatchConn] and any of the watched keys was modified
 	Tx interface {
 		Conn
 		Exec(ctx context.Context) error
 		Discard(ctx context.Context) error
 	}
+
+	// Watcher runs the optimistic transactions, as Redis does with WATCH
+	Watcher interface {
+		Conn
+
+		// Watch calls the function with a connection watching the keys. The
+		// watched keys are only checked by the [Tx] begun on the connection
+		Watch(ctx context.Context, fn func(conn WatchConn) error, keys ...string) error
+	}
+
+	// WatchConn may watch more keys, e.g. the ones
+	// read from the watched keys, until a [Tx] is begun
+	WatchConn interface {
+		Conn
+		Watch(ctx context.Context, keys ...string) error
+	}
 )
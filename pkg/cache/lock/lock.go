@@ -0,0 +1,379 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/internal/backoff"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+)
+
+const (
+	DefaultTTL        = 30 * time.Second
+	DefaultMinBackoff = 50 * time.Millisecond
+	DefaultMaxBackoff = time.Second
+
+	// keyFormat will be interpreted as "lock:<name>".
+	// The key holds the value of the current owner
+	keyFormat = "lock:%s"
+
+	// fenceKeyFormat will be interpreted as "lock_fence:<name>".
+	// The key holds the latest fencing token and never expires
+	fenceKeyFormat = "lock_fence:%s"
+
+	releaseTimeout = 5 * time.Second
+)
+
+var (
+	ErrNotAcquired = errors.New("the lock is held by another owner")
+	ErrLockLost    = errors.New("the lock was lost")
+)
+
+type Config struct {
+	// TTL is the lease of the lock. If the owner crashes,
+	// the lock is released once the lease expires
+	TTL time.Duration
+
+	// RenewInterval is TTL/3 by default. The negative
+	// interval disables the automatic renewal
+	RenewInterval time.Duration
+
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Store is implemented by the clients of the cache backends. Its commands
+// are atomic on their own, so they are not a part of cache.Conn and
+// can't be queued within a cache.Tx
+type Store interface {
+	Incr(ctx context.Context, key string) (int64, error)
+
+	// SetNX sets the key only if it does not exist
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error)
+
+	// CompareAndDelete atomically deletes the key only if it holds the value
+	CompareAndDelete(ctx context.Context, key string, value any) (bool, error)
+
+	// CompareAndExpire atomically sets the expiration only if the key holds the value
+	CompareAndExpire(ctx context.Context, key string, value any, expiration time.Duration) (bool, error)
+}
+
+// Locker acquires the locks, which are shared by all the instances
+// connected to the same cache. Each lock is identified by a name
+type Locker struct {
+	conn   Store
+	config Config
+	logger log.Logger
+}
+
+// NewLocker replaces the zero fields of the config with the defaults
+func NewLocker(conn Store, logger log.Logger, config *Config) *Locker {
+	l := &Locker{
+		conn:   conn,
+		logger: logger,
+	}
+	if config != nil {
+		l.config = *config
+	}
+	if l.config.TTL <= 0 {
+		l.config.TTL = DefaultTTL
+	}
+	if l.config.RenewInterval == 0 {
+		l.config.RenewInterval = l.config.TTL / 3
+	}
+	if l.config.MinBackoff <= 0 {
+		l.config.MinBackoff = DefaultMinBackoff
+	}
+	if l.config.MaxBackoff <= 0 {
+		l.config.MaxBackoff = DefaultMaxBackoff
+	}
+
+	return l
+}
+
+// TryAcquire makes a single attempt to acquire the lock.
+// It returns [ErrNotAcquired] if the lock is held
+func (l *Locker) TryAcquire(ctx context.Context, name string) (*Lock, error) {
+	logger := l.logger.With(log.Fields{"lock": name})
+
+	// The random value identifies the owner, even if the fencing tokens were reset
+	key := fmt.Sprintf(keyFormat, name)
+	value := strconv.FormatUint(rand.Uint64(), 16) + strconv.FormatUint(rand.Uint64(), 16)
+
+	ok, err := l.conn.SetNX(ctx, key, value, l.config.TTL)
+	if err != nil {
+		logger.WithError(err).Error("failed to acquire the lock")
+		return nil, err
+	}
+	if !ok {
+		logger.Debug("the lock is held by another owner")
+		return nil, ErrNotAcquired
+	}
+
+	token, err := l.takeToken(ctx, name, key, value)
+	if err != nil {
+		l.abandon(ctx, key, value, logger)
+		if !errors.Is(err, ErrNotAcquired) {
+			logger.WithError(err).Error("failed to get a fencing token")
+		}
+		return nil, err
+	}
+
+	lock := newLock(l, key, value, token, logger.With(log.Fields{"token": token}))
+	lock.logger.Debug("acquired the lock")
+	return lock, nil
+}
+
+// takeToken is called only by the owner of the lock, so the failed attempts
+// don't use up the tokens. The lease is extended after the token is taken,
+// which fails if the lease expired in between and the lock was taken by
+// another owner, whose token might be lower
+func (l *Locker) takeToken(ctx context.Context, name, key, value string) (int64, error) {
+	token, err := l.conn.Incr(ctx, fmt.Sprintf(fenceKeyFormat, name))
+	if err != nil {
+		return 0, err
+	}
+
+	ok, err := l.conn.CompareAndExpire(ctx, key, value, l.config.TTL)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrNotAcquired
+	}
+
+	return token, nil
+}
+
+// abandon releases the lock, which was acquired without a token,
+// so that it isn't held until the lease expires
+func (l *Locker) abandon(ctx context.Context, key, value string, logger log.Logger) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+
+	if _, err := l.conn.CompareAndDelete(ctx, key, value); err != nil {
+		logger.WithError(err).Error("failed to release the lock without a fencing token")
+	}
+}
+
+// Acquire retries with a jittered exponential backoff, until
+// the lock is acquired or the context is done
+func (l *Locker) Acquire(ctx context.Context, name string) (*Lock, error) {
+	for attempt := 0; ; attempt++ {
+		lock, err := l.TryAcquire(ctx, name)
+		if !errors.Is(err, ErrNotAcquired) {
+			return lock, err
+		}
+
+		timer := time.NewTimer(backoff.Jittered(attempt, l.config.MinBackoff, l.config.MaxBackoff))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Do acquires the lock, calls fn and releases the lock. The context
+// passed to fn is canceled, if the lock is lost in the meantime
+func (l *Locker) Do(ctx context.Context, name string, fn func(ctx context.Context, token int64) error) error {
+	lock, err := l.Acquire(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	fnCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-lock.Done():
+			cancel()
+		case <-fnCtx.Done():
+		}
+	}()
+
+	err = fn(fnCtx, lock.Token())
+
+	releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer releaseCancel()
+
+	if releaseErr := lock.Release(releaseCtx); releaseErr != nil {
+		return errors.Join(err, releaseErr)
+	}
+
+	return err
+}
+
+// Lock is renewed in the background until it is released or lost
+type Lock struct {
+	locker *Locker
+	key    string
+	value  string
+	token  int64
+	logger log.Logger
+
+	stop chan struct{}
+	done chan struct{}
+
+	mu       sync.Mutex
+	err      error
+	released bool
+}
+
+func newLock(locker *Locker, key, value string, token int64, logger log.Logger) *Lock {
+	lock := &Lock{
+		locker: locker,
+		key:    key,
+		value:  value,
+		token:  token,
+		logger: logger,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	if locker.config.RenewInterval > 0 {
+		go lock.renew()
+	}
+
+	return lock
+}
+
+// Token is the fencing token, which grows with every acquisition of the
+// lock. The writes guarded by the lock should carry the token, so that
+// the resource rejects the writes with a token lower than it has seen
+func (l *Lock) Token() int64 {
+	return l.token
+}
+
+// Done is closed when the lock is released or lost
+func (l *Lock) Done() <-chan struct{} {
+	return l.done
+}
+
+// Err returns [ErrLockLost] if the lock has been lost
+func (l *Lock) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.err
+}
+
+// Renew extends the lease of the lock. It returns [ErrLockLost]
+// if the lock has expired or has been acquired by another owner
+func (l *Lock) Renew(ctx context.Context) error {
+	if err := l.Err(); err != nil {
+		return err
+	}
+
+	ok, err := l.locker.conn.CompareAndExpire(ctx, l.key, l.value, l.locker.config.TTL)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to renew the lock")
+		return err
+	}
+	if !ok {
+		l.lose()
+		return ErrLockLost
+	}
+
+	l.logger.Debug("renewed the lock")
+	return nil
+}
+
+// Release deletes the lock only if it is still held by this owner.
+// It returns [ErrLockLost] if the lock has been lost before
+func (l *Lock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	if l.released {
+		l.mu.Unlock()
+		return l.Err()
+	}
+
+	l.released = true
+	close(l.stop)
+	l.mu.Unlock()
+
+	ok, err := l.locker.conn.CompareAndDelete(ctx, l.key, l.value)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to release the lock")
+		l.finish(nil)
+		return err
+	}
+	if !ok {
+		l.logger.Warn("the lock was lost before it was released")
+		l.finish(ErrLockLost)
+		return ErrLockLost
+	}
+
+	l.logger.Debug("released the lock")
+	l.finish(nil)
+	return nil
+}
+
+// renew extends the lease periodically. The lock is considered lost, if
+// it wasn't renewed successfully within the TTL, since the lease might
+// have expired and been taken by another owner
+func (l *Lock) renew() {
+	ticker := time.NewTicker(l.locker.config.RenewInterval)
+	defer ticker.Stop()
+
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-l.done:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), l.locker.config.RenewInterval)
+		err := l.Renew(ctx)
+		cancel()
+
+		switch {
+		case err == nil:
+			renewedAt = time.Now()
+		case errors.Is(err, ErrLockLost):
+			return
+		case time.Since(renewedAt) >= l.locker.config.TTL:
+			l.lose()
+			return
+		}
+	}
+}
+
+// lose is a no-op after [Lock.Release], which deletes the key itself
+func (l *Lock) lose() {
+	l.mu.Lock()
+	released := l.released
+	l.mu.Unlock()
+
+	if released {
+		return
+	}
+
+	l.logger.Warn("lost the lock")
+	l.finish(ErrLockLost)
+}
+
+// finish closes done once
+func (l *Lock) finish(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return
+	default:
+	}
+
+	l.err = err
+	close(l.done)
+}
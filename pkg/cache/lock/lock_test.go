@@ -0,0 +1,329 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	stdslog "log/slog"
+	"testing"
+	"time"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/memory"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/redis"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+)
+
+const testLockName = "migrations"
+
+// Both of the cache backends implement the store
+var (
+	_ Store = (*memory.Client)(nil)
+	_ Store = (*redis.Client)(nil)
+)
+
+var testLockerConfig = Config{
+	TTL:           50 * time.Millisecond,
+	RenewInterval: 10 * time.Millisecond,
+	MinBackoff:    time.Millisecond,
+	MaxBackoff:    5 * time.Millisecond,
+}
+
+type (
+	lockerTestCaseCommand func(locker *Locker, conn *memory.Client) error
+	lockerTestCaseExpect  func(err error)
+
+	lockerTestCase struct {
+		cmd lockerTestCaseCommand
+		exp lockerTestCaseExpect
+	}
+)
+
+func TestLocker_Acquire(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]lockerTestCase{
+		"SUCCESS tokens grow": {
+			cmd: func(locker *Locker, _ *memory.Client) error {
+				first, err := locker.TryAcquire(ctx, testLockName)
+				if err != nil {
+					return err
+				}
+				if err = first.Release(ctx); err != nil {
+					return err
+				}
+
+				second, err := locker.TryAcquire(ctx, testLockName)
+				if err != nil {
+					return err
+				}
+				require.Greater(t, second.Token(), first.Token())
+
+				return second.Release(ctx)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS failed attempts don't take tokens": {
+			cmd: func(locker *Locker, _ *memory.Client) error {
+				first, err := locker.TryAcquire(ctx, testLockName)
+				if err != nil {
+					return err
+				}
+
+				for range 3 {
+					_, err = locker.TryAcquire(ctx, testLockName)
+					require.ErrorIs(t, err, ErrNotAcquired)
+				}
+				if err = first.Release(ctx); err != nil {
+					return err
+				}
+
+				second, err := locker.TryAcquire(ctx, testLockName)
+				if err != nil {
+					return err
+				}
+				require.Equal(t, first.Token()+1, second.Token())
+
+				return second.Release(ctx)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS waits for the release": {
+			cmd: func(locker *Locker, _ *memory.Client) error {
+				held, err := locker.TryAcquire(ctx, testLockName)
+				if err != nil {
+					return err
+				}
+
+				time.AfterFunc(20*time.Millisecond, func() { _ = held.Release(ctx) })
+
+				acquireCtx, cancel := context.WithTimeout(ctx, time.Second)
+				defer cancel()
+
+				lock, err := locker.Acquire(acquireCtx, testLockName)
+				if err != nil {
+					return err
+				}
+				require.Greater(t, lock.Token(), held.Token())
+
+				return lock.Release(ctx)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS renews the lease": {
+			cmd: func(locker *Locker, _ *memory.Client) error {
+				lock, err := locker.TryAcquire(ctx, testLockName)
+				if err != nil {
+					return err
+				}
+
+				time.Sleep(3 * testLockerConfig.TTL)
+				require.NoError(t, lock.Err())
+
+				_, err = locker.TryAcquire(ctx, testLockName)
+				require.ErrorIs(t, err, ErrNotAcquired)
+
+				return lock.Release(ctx)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED held by another owner": {
+			cmd: func(locker *Locker, _ *memory.Client) error {
+				if _, err := locker.TryAcquire(ctx, testLockName); err != nil {
+					return err
+				}
+
+				_, err := locker.TryAcquire(ctx, testLockName)
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrNotAcquired)
+			},
+		},
+		"FAILED context deadline": {
+			cmd: func(locker *Locker, _ *memory.Client) error {
+				if _, err := locker.TryAcquire(ctx, testLockName); err != nil {
+					return err
+				}
+
+				acquireCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+				defer cancel()
+
+				_, err := locker.Acquire(acquireCtx, testLockName)
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runLockerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestLock_Release(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]lockerTestCase{
+		"SUCCESS twice": {
+			cmd: func(locker *Locker, _ *memory.Client) error {
+				lock, err := locker.TryAcquire(ctx, testLockName)
+				if err != nil {
+					return err
+				}
+				if err = lock.Release(ctx); err != nil {
+					return err
+				}
+
+				select {
+				case <-lock.Done():
+				default:
+					t.Fatal("done is not closed")
+				}
+
+				return lock.Release(ctx)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED does not delete the lock of another owner": {
+			cmd: func(locker *Locker, conn *memory.Client) error {
+				lock, err := locker.TryAcquire(ctx, "cleanup")
+				if err != nil {
+					return err
+				}
+
+				// The lease has expired and the lock was taken by another owner
+				if _, err = conn.Delete(ctx, "lock:cleanup"); err != nil {
+					return err
+				}
+				other, err := locker.TryAcquire(ctx, "cleanup")
+				if err != nil {
+					return err
+				}
+
+				err = lock.Release(ctx)
+				require.NoError(t, other.Err())
+
+				n, existsErr := conn.Exists(ctx, "lock:cleanup")
+				require.NoError(t, existsErr)
+				require.Equal(t, int64(1), n)
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrLockLost)
+			},
+		},
+		"FAILED lost while renewing": {
+			cmd: func(locker *Locker, conn *memory.Client) error {
+				lock, err := locker.TryAcquire(ctx, testLockName)
+				if err != nil {
+					return err
+				}
+
+				if _, err = conn.Delete(ctx, "lock:"+testLockName); err != nil {
+					return err
+				}
+
+				select {
+				case <-lock.Done():
+				case <-time.After(time.Second):
+					t.Fatal("the lock loss is not detected")
+				}
+
+				return lock.Err()
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrLockLost)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runLockerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestLocker_Do(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]lockerTestCase{
+		"SUCCESS": {
+			cmd: func(locker *Locker, conn *memory.Client) error {
+				return locker.Do(ctx, testLockName, func(ctx context.Context, token int64) error {
+					require.Equal(t, int64(1), token)
+					return nil
+				})
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED fn": {
+			cmd: func(locker *Locker, conn *memory.Client) error {
+				err := locker.Do(ctx, testLockName, func(context.Context, int64) error {
+					return errors.New("fn")
+				})
+
+				// The lock is released anyway
+				n, existsErr := conn.Exists(ctx, "lock:"+testLockName)
+				require.NoError(t, existsErr)
+				require.Zero(t, n)
+				return err
+			},
+			exp: func(err error) {
+				require.EqualError(t, err, "fn")
+			},
+		},
+		"FAILED cancels the context on loss": {
+			cmd: func(locker *Locker, conn *memory.Client) error {
+				return locker.Do(ctx, testLockName, func(ctx context.Context, _ int64) error {
+					if _, err := conn.Delete(ctx, "lock:"+testLockName); err != nil {
+						return err
+					}
+
+					<-ctx.Done()
+					return ctx.Err()
+				})
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, context.Canceled)
+				require.ErrorIs(t, err, ErrLockLost)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runLockerTestCase(t, &tc)
+		})
+	}
+}
+
+// runLockerTestCase should be called by [testing.T.Run]
+func runLockerTestCase(t *testing.T, tc *lockerTestCase) {
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	conn := memory.New(logger, nil)
+	locker := NewLocker(conn, logger, &testLockerConfig)
+
+	var err error
+	if tc.cmd != nil {
+		err = tc.cmd(locker, conn)
+	}
+
+	if tc.exp != nil {
+		tc.exp(err)
+	}
+}
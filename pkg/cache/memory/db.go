@@ -0,0 +1,408 @@
+package memory
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/cache"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrWrongType     = errors.New("operation against a key holding the wrong kind of value")
+	ErrNotInteger    = errors.New("value is not an integer or out of range")
+	ErrNotFloat      = errors.New("min or max is not a float")
+	ErrWrongArgCount = errors.New("wrong number of arguments")
+)
+
+type (
+	hash      map[string]string
+	set       map[string]struct{}
+	sortedSet map[string]float64
+)
+
+// entry holds either a string, a hash, a set or a sorted set.
+// The zero expiresAt means that the entry never expires
+type entry struct {
+	value     any
+	expiresAt time.Time
+}
+
+type db struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+	now     func() time.Time
+}
+
+func newDB() *db {
+	return &db{
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+// get evicts the entry, if it has expired
+func (d *db) get(key string) *entry {
+	e, ok := d.entries[key]
+	if !ok {
+		return nil
+	}
+
+	if d.expired(e) {
+		delete(d.entries, key)
+		return nil
+	}
+
+	return e
+}
+
+// snapshot copies the entry, so that it can be compared by [db.modified]
+func (d *db) snapshot(key string) *entry {
+	e := d.get(key)
+	if e == nil {
+		return nil
+	}
+
+	c := *e
+	switch v := e.value.(type) {
+	case hash:
+		c.value = maps.Clone(v)
+	case set:
+		c.value = maps.Clone(v)
+	case sortedSet:
+		c.value = maps.Clone(v)
+	}
+
+	return &c
+}
+
+// modified reports whether the value or the expiration of
+// the key differs from the snapshot. The expired key is modified
+func (d *db) modified(key string, snapshot *entry) bool {
+	return !reflect.DeepEqual(d.get(key), snapshot)
+}
+
+func (d *db) expired(e *entry) bool {
+	return !e.expiresAt.IsZero() && !e.expiresAt.After(d.now())
+}
+
+// evictExpired deletes all the expired entries and returns their number
+func (d *db) evictExpired() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n := 0
+	for key, e := range d.entries {
+		if d.expired(e) {
+			delete(d.entries, key)
+			n++
+		}
+	}
+
+	return n
+}
+
+// keys returns the sorted keys, which match the pattern
+func (d *db) keys(pattern string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	keys := make([]string, 0, len(d.entries))
+	for key := range d.entries {
+		if d.get(key) != nil && match(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+// lookup returns the zero value if the key does not exist
+func lookup[T any](d *db, key string) (T, error) {
+	var zero T
+
+	e := d.get(key)
+	if e == nil {
+		return zero, nil
+	}
+
+	v, ok := e.value.(T)
+	if !ok {
+		return zero, ErrWrongType
+	}
+
+	return v, nil
+}
+
+// lookupOrCreate creates an empty value, if the key does not exist
+func lookupOrCreate[T ~map[K]V, K comparable, V any](d *db, key string) (T, error) {
+	v, err := lookup[T](d, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		v = make(T)
+		d.entries[key] = &entry{value: v}
+	}
+
+	return v, nil
+}
+
+// deleteIfEmpty deletes the emptied collections, as Redis does
+func deleteIfEmpty[T ~map[K]V, K comparable, V any](d *db, key string, v T) {
+	if len(v) == 0 {
+		delete(d.entries, key)
+	}
+}
+
+// setString replaces the value of any type. The expiration equal
+// to [redis.KeepTTL] keeps the current expiration of the key
+func (d *db) setString(key, value string, expiration time.Duration) {
+	e := &entry{value: value}
+	if expiration > 0 {
+		e.expiresAt = d.now().Add(expiration)
+	} else if old := d.get(key); expiration == redis.KeepTTL && old != nil {
+		e.expiresAt = old.expiresAt
+	}
+
+	d.entries[key] = e
+}
+
+func (d *db) getString(key string) (string, bool, error) {
+	e := d.get(key)
+	if e == nil {
+		return "", false, nil
+	}
+
+	s, ok := e.value.(string)
+	if !ok {
+		return "", false, ErrWrongType
+	}
+
+	return s, true, nil
+}
+
+func (d *db) incrBy(key string, value int64) (int64, error) {
+	s, ok, err := d.getString(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	if ok {
+		if n, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return 0, ErrNotInteger
+		}
+	}
+
+	if (value > 0 && n > math.MaxInt64-value) || (value < 0 && n < math.MinInt64-value) {
+		return 0, ErrNotInteger
+	}
+
+	n += value
+	if e := d.get(key); e != nil {
+		e.value = strconv.FormatInt(n, 10)
+	} else {
+		d.entries[key] = &entry{value: strconv.FormatInt(n, 10)}
+	}
+
+	return n, nil
+}
+
+// expire deletes the key, if the expiration is not positive
+func (d *db) expire(key string, expiration time.Duration) bool {
+	e := d.get(key)
+	if e == nil {
+		return false
+	}
+
+	if expiration <= 0 {
+		delete(d.entries, key)
+		return true
+	}
+
+	e.expiresAt = d.now().Add(expiration)
+	return true
+}
+
+// ttl returns -2 if the key does not exist, as Redis does
+func (d *db) ttl(key string) time.Duration {
+	e := d.get(key)
+	if e == nil {
+		return -2
+	}
+
+	if e.expiresAt.IsZero() {
+		return cache.NoExpiration
+	}
+
+	// Redis rounds the TTL to seconds
+	return e.expiresAt.Sub(d.now()).Round(time.Second)
+}
+
+// zRangeByScore returns the members ordered by their scores and then lexicographically
+func (d *db) zRangeByScore(key string, opt *cache.ZRangeBy) ([]string, error) {
+	minScore, minExclusive, err := parseScore(opt.Min)
+	if err != nil {
+		return nil, err
+	}
+
+	maxScore, maxExclusive, err := parseScore(opt.Max)
+	if err != nil {
+		return nil, err
+	}
+
+	zs, err := lookup[sortedSet](d, key)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]string, 0, len(zs))
+	for member, score := range zs {
+		if score < minScore || (minExclusive && score == minScore) ||
+			score > maxScore || (maxExclusive && score == maxScore) {
+			continue
+		}
+
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if zs[members[i]] != zs[members[j]] {
+			return zs[members[i]] < zs[members[j]]
+		}
+
+		return members[i] < members[j]
+	})
+
+	// Redis applies LIMIT only if any of them is set,
+	// and the negative count means all the members
+	if opt.Offset == 0 && opt.Count == 0 {
+		return members, nil
+	}
+
+	if opt.Offset < 0 || opt.Offset >= int64(len(members)) {
+		return []string{}, nil
+	}
+
+	members = members[opt.Offset:]
+	if opt.Count >= 0 && opt.Count < int64(len(members)) {
+		members = members[:opt.Count]
+	}
+
+	return members, nil
+}
+
+// parseScore parses the "-inf", "+inf" and the exclusive "(" scores
+func parseScore(s string) (float64, bool, error) {
+	exclusive := strings.HasPrefix(s, "(")
+	if exclusive {
+		s = s[1:]
+	}
+
+	switch s {
+	case "-inf":
+		return math.Inf(-1), exclusive, nil
+	case "+inf", "inf":
+		return math.Inf(1), exclusive, nil
+	}
+
+	score, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(score) {
+		return 0, false, proxerr.New(ErrNotFloat, fmt.Sprintf("score %q is not a float", s))
+	}
+
+	return score, exclusive, nil
+}
+
+// formatValue converts the value the same way as the redis client does
+func formatValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.FormatInt(v.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		b, err := v.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return formatFloat(rv.Float()), nil
+	case reflect.Bool:
+		return formatValue(rv.Bool())
+	default:
+		return "", fmt.Errorf("can't marshal %T (implement encoding.BinaryMarshaler)", v)
+	}
+}
+
+func formatValues(vs []any) ([]string, error) {
+	ss := make([]string, len(vs))
+	for i, v := range vs {
+		s, err := formatValue(v)
+		if err != nil {
+			return nil, err
+		}
+
+		ss[i] = s
+	}
+
+	return ss, nil
+}
+
+// formatPairs formats the keys and the values given in turns
+func formatPairs(vs []any) ([]string, error) {
+	if len(vs) == 0 || len(vs)%2 != 0 {
+		return nil, proxerr.New(ErrWrongArgCount, fmt.Sprintf("expected key-value pairs, got %d arguments", len(vs)))
+	}
+
+	return formatValues(vs)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// scanValue scans the value the same way as the redis client does
+func scanValue(s string, dest any) error {
+	return redis.NewStringResult(s, nil).Scan(dest)
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+)
+
+// SetNX and the compare commands implement lock.Store. They are only run on
+// the [Client], as a lock must not be acquired or released within a transaction
+func (c *Client) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	logger := c.logger.With(log.Fields{
+		"key":        key,
+		"expiration": expiration,
+	})
+
+	var ok bool
+	s, err := formatValue(value)
+	if err == nil {
+		err = c.do(func(d *db) error {
+			if ok = d.get(key) == nil; ok {
+				d.setString(key, s, expiration)
+			}
+
+			return nil
+		})
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to set the key if it does not exist")
+		return false, err
+	}
+
+	logger.Debug(fmt.Sprintf("set the key if it does not exist: %t", ok))
+	return ok, nil
+}
+
+func (c *Client) CompareAndDelete(ctx context.Context, key string, value any) (bool, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	var ok bool
+	err := c.compare(key, value, func(d *db) {
+		delete(d.entries, key)
+		ok = true
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to compare and delete the key")
+		return false, err
+	}
+
+	logger.Debug(fmt.Sprintf("compared and deleted the key: %t", ok))
+	return ok, nil
+}
+
+func (c *Client) CompareAndExpire(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	logger := c.logger.With(log.Fields{
+		"key":        key,
+		"expiration": expiration,
+	})
+
+	var ok bool
+	err := c.compare(key, value, func(d *db) {
+		ok = d.expire(key, expiration)
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to compare and expire the key")
+		return false, err
+	}
+
+	logger.Debug(fmt.Sprintf("compared and expired the key: %t", ok))
+	return ok, nil
+}
+
+// compare calls fn only if the key holds the value
+func (c *Client) compare(key string, value any, fn func(d *db)) error {
+	s, err := formatValue(value)
+	if err != nil {
+		return err
+	}
+
+	return c.do(func(d *db) error {
+		current, ok, err := d.getString(key)
+		if err != nil {
+			return err
+		}
+
+		if ok && current == s {
+			fn(d)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,685 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/cache"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+)
+
+const DefaultCleanupPeriod = time.Minute
+
+type command func(d *db) error
+
+// Conn keeps the keys in memory of the process. The values are formatted
+// and scanned the same way as by the redis client, and the expired keys
+// are never returned, so it can replace Redis in the tests and in the
+// single-node deployments
+type Conn struct {
+	db     *db
+	logger log.Logger
+
+	// queue is not nil within a [Tx]
+	queue *[]command
+
+	// watched is not nil within [Client.Watch]. It holds
+	// the snapshots of the keys taken when they were watched
+	watched map[string]*entry
+}
+
+// do runs the command at once or queues it within a [Tx]
+func (c *Conn) do(cmd command) error {
+	if c.queue != nil {
+		*c.queue = append(*c.queue, cmd)
+		return nil
+	}
+
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	return cmd(c.db)
+}
+
+// Get returns [cache.ErrKeyDoesNotExist] if the key does not exist.
+// Within a [Tx], the destination is set by [Tx.Exec]
+func (c *Conn) Get(ctx context.Context, key string, dest any) error {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	if err := c.do(func(d *db) error {
+		s, ok, err := d.getString(key)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return proxerr.New(cache.ErrKeyDoesNotExist, fmt.Sprintf("key %q does not exist", key))
+		}
+
+		return scanValue(s, dest)
+	}); err != nil {
+		logger.WithError(err).Error("failed to get the key")
+		return err
+	}
+
+	logger.Debug("got the key")
+	return nil
+}
+
+func (c *Conn) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	logger := c.logger.With(log.Fields{
+		"key":        key,
+		"expiration": expiration,
+	})
+
+	s, err := formatValue(value)
+	if err == nil {
+		err = c.do(func(d *db) error {
+			d.setString(key, s, expiration)
+			return nil
+		})
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to set the key")
+		return err
+	}
+
+	logger.Debug("set the key")
+	return nil
+}
+
+// MGet returns nil values for the keys, which do not exist or are not strings
+func (c *Conn) MGet(ctx context.Context, keys ...string) ([]any, error) {
+	logger := c.logger.With(log.Fields{"keys": keys})
+
+	var values []any
+	_ = c.do(func(d *db) error {
+		values = make([]any, len(keys))
+		for i, key := range keys {
+			if s, ok, err := d.getString(key); ok && err == nil {
+				values[i] = s
+			}
+		}
+
+		return nil
+	})
+
+	logger.Debug("got the keys")
+	return values, nil
+}
+
+// MSet takes the keys and the values in turns
+func (c *Conn) MSet(ctx context.Context, values ...any) error {
+	pairs, err := formatPairs(values)
+	if err == nil {
+		err = c.do(func(d *db) error {
+			for i := 0; i < len(pairs); i += 2 {
+				d.setString(pairs[i], pairs[i+1], 0)
+			}
+
+			return nil
+		})
+	}
+	if err != nil {
+		c.logger.WithError(err).Error("failed to set the keys")
+		return err
+	}
+
+	c.logger.Debug(fmt.Sprintf("set %d keys", len(values)/2))
+	return nil
+}
+
+func (c *Conn) Delete(ctx context.Context, key string) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	var n int64
+	_ = c.do(func(d *db) error {
+		if d.get(key) != nil {
+			delete(d.entries, key)
+			n = 1
+		}
+
+		return nil
+	})
+
+	logger.Debug("deleted the key")
+	return n, nil
+}
+
+func (c *Conn) Exists(ctx context.Context, keys ...string) (int64, error) {
+	logger := c.logger.With(log.Fields{"keys": keys})
+
+	var n int64
+	_ = c.do(func(d *db) error {
+		for _, key := range keys {
+			if d.get(key) != nil {
+				n++
+			}
+		}
+
+		return nil
+	})
+
+	logger.Debug(fmt.Sprintf("%d out of %d keys exist", n, len(keys)))
+	return n, nil
+}
+
+// Incr considers the key, which does not exist, to be 0
+func (c *Conn) Incr(ctx context.Context, key string) (int64, error) {
+	return c.IncrBy(ctx, key, 1)
+}
+
+// IncrBy considers the key, which does not exist, to be 0
+func (c *Conn) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	logger := c.logger.With(log.Fields{
+		"key":   key,
+		"value": value,
+	})
+
+	var n int64
+	if err := c.do(func(d *db) (err error) {
+		n, err = d.incrBy(key, value)
+		return err
+	}); err != nil {
+		logger.WithError(err).Error("failed to increment the key")
+		return 0, err
+	}
+
+	logger.Debug("incremented the key")
+	return n, nil
+}
+
+// HSet takes the fields and the values in turns. It returns
+// the number of the fields, which were added
+func (c *Conn) HSet(ctx context.Context, key string, values ...any) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	var n int64
+	pairs, err := formatPairs(values)
+	if err == nil {
+		err = c.do(func(d *db) error {
+			h, err := lookupOrCreate[hash](d, key)
+			if err != nil {
+				return err
+			}
+
+			for i := 0; i < len(pairs); i += 2 {
+				if _, ok := h[pairs[i]]; !ok {
+					n++
+				}
+				h[pairs[i]] = pairs[i+1]
+			}
+
+			return nil
+		})
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to set fields of the hash")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("added %d out of %d fields to the hash", n, len(values)/2))
+	return n, nil
+}
+
+// HGet returns [cache.ErrKeyDoesNotExist] if either the hash
+// or the field does not exist
+func (c *Conn) HGet(ctx context.Context, key, field string, dest any) error {
+	logger := c.logger.With(log.Fields{
+		"key":   key,
+		"field": field,
+	})
+
+	if err := c.do(func(d *db) error {
+		h, err := lookup[hash](d, key)
+		if err != nil {
+			return err
+		}
+
+		s, ok := h[field]
+		if !ok {
+			return proxerr.New(cache.ErrKeyDoesNotExist, fmt.Sprintf("field %q of key %q does not exist", field, key))
+		}
+
+		return scanValue(s, dest)
+	}); err != nil {
+		logger.WithError(err).Error("failed to get the field of the hash")
+		return err
+	}
+
+	logger.Debug("got the field of the hash")
+	return nil
+}
+
+// HGetAll returns an empty map if the hash does not exist
+func (c *Conn) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	fields := make(map[string]string)
+	if err := c.do(func(d *db) error {
+		h, err := lookup[hash](d, key)
+		for field, value := range h {
+			fields[field] = value
+		}
+
+		return err
+	}); err != nil {
+		logger.WithError(err).Error("failed to get fields of the hash")
+		return nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("got %d fields of the hash", len(fields)))
+	return fields, nil
+}
+
+func (c *Conn) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	var n int64
+	if err := c.do(func(d *db) error {
+		h, err := lookup[hash](d, key)
+		if err != nil {
+			return err
+		}
+
+		for _, field := range fields {
+			if _, ok := h[field]; ok {
+				delete(h, field)
+				n++
+			}
+		}
+
+		deleteIfEmpty(d, key, h)
+		return nil
+	}); err != nil {
+		logger.WithError(err).Error("failed to delete fields of the hash")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("deleted %d out of %d fields of the hash", n, len(fields)))
+	return n, nil
+}
+
+func (c *Conn) SAdd(ctx context.Context, key string, members ...any) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	var n int64
+	ms, err := formatValues(members)
+	if err == nil {
+		err = c.do(func(d *db) error {
+			s, err := lookupOrCreate[set](d, key)
+			if err != nil {
+				return err
+			}
+
+			for _, m := range ms {
+				if _, ok := s[m]; !ok {
+					s[m] = struct{}{}
+					n++
+				}
+			}
+
+			return nil
+		})
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to add members to the set")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("added %d out of %d members to the set", n, len(members)))
+	return n, nil
+}
+
+func (c *Conn) SRem(ctx context.Context, key string, members ...any) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	var n int64
+	ms, err := formatValues(members)
+	if err == nil {
+		err = c.do(func(d *db) error {
+			s, err := lookup[set](d, key)
+			if err != nil {
+				return err
+			}
+
+			for _, m := range ms {
+				if _, ok := s[m]; ok {
+					delete(s, m)
+					n++
+				}
+			}
+
+			deleteIfEmpty(d, key, s)
+			return nil
+		})
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to remove members from the set")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("removed %d out of %d members from the set", n, len(members)))
+	return n, nil
+}
+
+// SMembers returns an empty slice if the set does not exist
+func (c *Conn) SMembers(ctx context.Context, key string) ([]string, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	members := make([]string, 0)
+	if err := c.do(func(d *db) error {
+		s, err := lookup[set](d, key)
+		for m := range s {
+			members = append(members, m)
+		}
+
+		return err
+	}); err != nil {
+		logger.WithError(err).Error("failed to get members of the set")
+		return nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("got %d members of the set", len(members)))
+	return members, nil
+}
+
+// ZAdd updates the scores of the existing members. It returns
+// the number of the members, which were added
+func (c *Conn) ZAdd(ctx context.Context, key string, members ...cache.Z) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	var n int64
+	ms := make([]string, len(members))
+	var err error
+	for i := 0; i < len(members) && err == nil; i++ {
+		ms[i], err = formatValue(members[i].Member)
+	}
+	if err == nil {
+		err = c.do(func(d *db) error {
+			zs, err := lookupOrCreate[sortedSet](d, key)
+			if err != nil {
+				return err
+			}
+
+			for i, m := range ms {
+				if _, ok := zs[m]; !ok {
+					n++
+				}
+				zs[m] = members[i].Score
+			}
+
+			return nil
+		})
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to add members to the sorted set")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("added %d out of %d members to the sorted set", n, len(members)))
+	return n, nil
+}
+
+// ZRangeByScore returns the members ordered by their scores
+func (c *Conn) ZRangeByScore(ctx context.Context, key string, opt *cache.ZRangeBy) ([]string, error) {
+	logger := c.logger.With(log.Fields{
+		"key": key,
+		"min": opt.Min,
+		"max": opt.Max,
+	})
+
+	var members []string
+	if err := c.do(func(d *db) (err error) {
+		members, err = d.zRangeByScore(key, opt)
+		return err
+	}); err != nil {
+		logger.WithError(err).Error("failed to get members of the sorted set")
+		return nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("got %d members of the sorted set", len(members)))
+	return members, nil
+}
+
+func (c *Conn) ZRem(ctx context.Context, key string, members ...any) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	var n int64
+	ms, err := formatValues(members)
+	if err == nil {
+		err = c.do(func(d *db) error {
+			zs, err := lookup[sortedSet](d, key)
+			if err != nil {
+				return err
+			}
+
+			for _, m := range ms {
+				if _, ok := zs[m]; ok {
+					delete(zs, m)
+					n++
+				}
+			}
+
+			deleteIfEmpty(d, key, zs)
+			return nil
+		})
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to remove members from the sorted set")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("removed %d out of %d members from the sorted set", n, len(members)))
+	return n, nil
+}
+
+// Expire returns false if the key does not exist. The key
+// is deleted, if the expiration is not positive
+func (c *Conn) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	logger := c.logger.With(log.Fields{
+		"key":        key,
+		"expiration": expiration,
+	})
+
+	var ok bool
+	_ = c.do(func(d *db) error {
+		ok = d.expire(key, expiration)
+		return nil
+	})
+
+	logger.Debug("set the key expiration")
+	return ok, nil
+}
+
+// TTL returns [cache.NoExpiration] if the key never expires
+// and [cache.ErrKeyDoesNotExist] if it does not exist
+func (c *Conn) TTL(ctx context.Context, key string) (time.Duration, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	var ttl time.Duration
+	if err := c.do(func(d *db) error {
+		if ttl = d.ttl(key); ttl == -2 {
+			ttl = 0
+			return proxerr.New(cache.ErrKeyDoesNotExist, fmt.Sprintf("key %q does not exist", key))
+		}
+
+		return nil
+	}); err != nil {
+		logger.WithError(err).Error("failed to get the key ttl")
+		return 0, err
+	}
+
+	logger.Debug("got the key ttl")
+	return ttl, nil
+}
+
+// Persist returns false if the key does not exist or never expires
+func (c *Conn) Persist(ctx context.Context, key string) (bool, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	var ok bool
+	_ = c.do(func(d *db) error {
+		if e := d.get(key); e != nil && !e.expiresAt.IsZero() {
+			e.expiresAt = time.Time{}
+			ok = true
+		}
+
+		return nil
+	})
+
+	logger.Debug("removed the key expiration")
+	return ok, nil
+}
+
+func (c *Conn) Begin(_ context.Context) cache.Tx {
+	tx := &Tx{
+		Conn: Conn{
+			db:      c.db,
+			logger:  c.logger,
+			watched: c.watched,
+		},
+	}
+	tx.Conn.queue = &tx.commands
+
+	return tx
+}
+
+// Tx queues the commands, as Redis does within MULTI, and executes
+// them at once, so that no other command is run in between. Like in
+// Redis, the failed command doesn't prevent the rest from executing
+type Tx struct {
+	Conn
+	commands []command
+}
+
+// Exec returns the error of the first failed command. None of the commands
+// are executed, if a watched key was modified, as Redis does
+func (t *Tx) Exec(_ context.Context) error {
+	commands := t.commands
+	t.commands = nil
+
+	t.db.mu.Lock()
+	for key, snapshot := range t.watched {
+		if t.db.modified(key, snapshot) {
+			clear(t.watched)
+			t.db.mu.Unlock()
+
+			t.logger.With(log.Fields{"key": key}).Debug("a watched key was modified, the commands were not executed")
+			return proxerr.New(cache.ErrTxFailed, fmt.Sprintf("watched key %q was modified", key))
+		}
+	}
+	clear(t.watched)
+
+	var firstErr error
+	for _, cmd := range commands {
+		if err := cmd(t.db); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	t.db.mu.Unlock()
+
+	if firstErr != nil {
+		t.logger.WithError(firstErr).Error("failed to execute commands")
+		return firstErr
+	}
+
+	t.logger.Debug("executed commands")
+	return nil
+}
+
+func (t *Tx) Discard(_ context.Context) error {
+	t.commands = nil
+
+	t.logger.Debug("discarded commands")
+	return nil
+}
+
+// Watch snapshots the keys and compares them on [Tx.Exec], so unlike
+// Redis, it misses a key modified and then restored to the same value
+func (c *Client) Watch(ctx context.Context, fn func(conn cache.WatchConn) error, keys ...string) error {
+	conn := &watchConn{
+		Conn: Conn{
+			db:      c.db,
+			logger:  c.logger,
+			watched: make(map[string]*entry),
+		},
+	}
+	if err := conn.Watch(ctx, keys...); err != nil {
+		return err
+	}
+
+	if err := fn(conn); err != nil {
+		c.logger.With(log.Fields{"keys": keys}).WithError(err).Debug("failed to run a transaction watching the keys")
+		return err
+	}
+
+	c.logger.With(log.Fields{"keys": keys}).Debug("ran a transaction watching the keys")
+	return nil
+}
+
+type watchConn struct {
+	Conn
+}
+
+// Watch keeps the snapshot of the key, if it is already watched
+func (c *watchConn) Watch(_ context.Context, keys ...string) error {
+	c.db.mu.Lock()
+	for _, key := range keys {
+		if _, ok := c.watched[key]; !ok {
+			c.watched[key] = c.db.snapshot(key)
+		}
+	}
+	c.db.mu.Unlock()
+
+	c.logger.With(log.Fields{"keys": keys}).Debug("watched the keys")
+	return nil
+}
+
+type Config struct {
+	// CleanupPeriod is the period of the eviction of the expired keys
+	CleanupPeriod time.Duration
+}
+
+type Client struct {
+	Conn
+	config Config
+}
+
+// New returns an empty store. The expired keys are never returned,
+// but they are only freed on access or by [Client.Run]
+func New(logger log.Logger, config *Config) *Client {
+	c := &Client{
+		Conn: Conn{
+			db:     newDB(),
+			logger: logger,
+		},
+	}
+	if config != nil {
+		c.config = *config
+	}
+	if c.config.CleanupPeriod <= 0 {
+		c.config.CleanupPeriod = DefaultCleanupPeriod
+	}
+
+	return c
+}
+
+// Run evicts the expired keys periodically
+// and blocks until the context is done
+func (c *Client) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.config.CleanupPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if n := c.db.evictExpired(); n > 0 {
+				c.logger.Debug(fmt.Sprintf("evicted %d expired keys", n))
+			}
+		}
+	}
+}
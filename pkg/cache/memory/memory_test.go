@@ -0,0 +1,793 @@
+package memory
+
+import (
+	"context"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/ratelimit"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/redis/go-redis/v9"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	"testing"
+	"time"
+)
+
+type (
+	connTestCaseCommand func(conn *Conn, clock *testClock) error
+	connTestCaseExpect  func(conn *Conn, err error)
+
+	connTestCase struct {
+		cmd connTestCaseCommand
+		exp connTestCaseExpect
+	}
+
+	clientTestCaseCommand func(client *Client, clock *testClock) error
+	clientTestCaseExpect  func(client *Client, err error)
+
+	clientTestCase struct {
+		cmd clientTestCaseCommand
+		exp clientTestCaseExpect
+	}
+)
+
+// testClock is moved forward by the test cases to expire the keys
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestConn_Strings(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]connTestCase{
+		"SUCCESS set and get": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				return conn.Set(ctx, "a", 42, 0)
+			},
+			exp: func(conn *Conn, err error) {
+				require.NoError(t, err)
+
+				var n int
+				require.NoError(t, conn.Get(ctx, "a", &n))
+				require.Equal(t, 42, n)
+
+				var s string
+				require.NoError(t, conn.Get(ctx, "a", &s))
+				require.Equal(t, "42", s)
+			},
+		},
+		"SUCCESS expires": {
+			cmd: func(conn *Conn, clock *testClock) error {
+				if err := conn.Set(ctx, "a", "b", time.Minute); err != nil {
+					return err
+				}
+
+				clock.Add(time.Minute)
+				var s string
+				return conn.Get(ctx, "a", &s)
+			},
+			exp: func(conn *Conn, err error) {
+				require.ErrorIs(t, err, cache.ErrKeyDoesNotExist)
+
+				n, err := conn.Exists(ctx, "a")
+				require.NoError(t, err)
+				require.Zero(t, n)
+			},
+		},
+		"SUCCESS keeps the ttl": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				if err := conn.Set(ctx, "a", "b", time.Minute); err != nil {
+					return err
+				}
+
+				return conn.Set(ctx, "a", "c", redis.KeepTTL)
+			},
+			exp: func(conn *Conn, err error) {
+				require.NoError(t, err)
+
+				ttl, err := conn.TTL(ctx, "a")
+				require.NoError(t, err)
+				require.Equal(t, time.Minute, ttl)
+			},
+		},
+		"SUCCESS ttl and persist": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				return conn.Set(ctx, "a", "b", 0)
+			},
+			exp: func(conn *Conn, err error) {
+				require.NoError(t, err)
+
+				ttl, err := conn.TTL(ctx, "a")
+				require.NoError(t, err)
+				require.Equal(t, cache.NoExpiration, ttl)
+
+				ok, err := conn.Expire(ctx, "a", time.Hour)
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				ok, err = conn.Persist(ctx, "a")
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				_, err = conn.TTL(ctx, "b")
+				require.ErrorIs(t, err, cache.ErrKeyDoesNotExist)
+			},
+		},
+		"SUCCESS mset and mget": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				return conn.MSet(ctx, "a", 1, "b", true)
+			},
+			exp: func(conn *Conn, err error) {
+				require.NoError(t, err)
+
+				values, err := conn.MGet(ctx, "a", "b", "c")
+				require.NoError(t, err)
+				require.Equal(t, []any{"1", "1", nil}, values)
+			},
+		},
+		"SUCCESS incr": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				if err := conn.Set(ctx, "a", 1, time.Minute); err != nil {
+					return err
+				}
+
+				_, err := conn.Incr(ctx, "a")
+				return err
+			},
+			exp: func(conn *Conn, err error) {
+				require.NoError(t, err)
+
+				n, err := conn.IncrBy(ctx, "a", -5)
+				require.NoError(t, err)
+				require.Equal(t, int64(-3), n)
+
+				ttl, err := conn.TTL(ctx, "a")
+				require.NoError(t, err)
+				require.Equal(t, time.Minute, ttl)
+			},
+		},
+		"FAILED incr not an integer": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				if err := conn.Set(ctx, "a", "b", 0); err != nil {
+					return err
+				}
+
+				_, err := conn.Incr(ctx, "a")
+				return err
+			},
+			exp: func(_ *Conn, err error) {
+				require.ErrorIs(t, err, ErrNotInteger)
+			},
+		},
+		"FAILED wrong type": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				if _, err := conn.SAdd(ctx, "a", "b"); err != nil {
+					return err
+				}
+
+				var s string
+				return conn.Get(ctx, "a", &s)
+			},
+			exp: func(_ *Conn, err error) {
+				require.ErrorIs(t, err, ErrWrongType)
+			},
+		},
+		"FAILED mset odd arguments": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				return conn.MSet(ctx, "a", 1, "b")
+			},
+			exp: func(_ *Conn, err error) {
+				require.ErrorIs(t, err, ErrWrongArgCount)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runConnTestCase(t, &tc)
+		})
+	}
+}
+
+func TestConn_Collections(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]connTestCase{
+		"SUCCESS hash": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				_, err := conn.HSet(ctx, "h", "a", 1, "b", "c")
+				return err
+			},
+			exp: func(conn *Conn, err error) {
+				require.NoError(t, err)
+
+				var n int
+				require.NoError(t, conn.HGet(ctx, "h", "a", &n))
+				require.Equal(t, 1, n)
+				require.ErrorIs(t, conn.HGet(ctx, "h", "d", &n), cache.ErrKeyDoesNotExist)
+
+				deleted, err := conn.HDel(ctx, "h", "a", "d")
+				require.NoError(t, err)
+				require.Equal(t, int64(1), deleted)
+
+				fields, err := conn.HGetAll(ctx, "h")
+				require.NoError(t, err)
+				require.Equal(t, map[string]string{"b": "c"}, fields)
+			},
+		},
+		"SUCCESS set": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				_, err := conn.SAdd(ctx, "s", "a", "b", "a")
+				return err
+			},
+			exp: func(conn *Conn, err error) {
+				require.NoError(t, err)
+
+				members, err := conn.SMembers(ctx, "s")
+				require.NoError(t, err)
+				require.ElementsMatch(t, []string{"a", "b"}, members)
+
+				n, err := conn.SRem(ctx, "s", "a", "b")
+				require.NoError(t, err)
+				require.Equal(t, int64(2), n)
+
+				// The emptied set is deleted
+				exists, err := conn.Exists(ctx, "s")
+				require.NoError(t, err)
+				require.Zero(t, exists)
+			},
+		},
+		"SUCCESS sorted set": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				_, err := conn.ZAdd(ctx, "z", cache.Z{Score: 2, Member: "b"}, cache.Z{Score: 1, Member: "a"},
+					cache.Z{Score: 3, Member: "c"}, cache.Z{Score: 2, Member: "a2"})
+				return err
+			},
+			exp: func(conn *Conn, err error) {
+				require.NoError(t, err)
+
+				members, err := conn.ZRangeByScore(ctx, "z", &cache.ZRangeBy{Min: "-inf", Max: "+inf"})
+				require.NoError(t, err)
+				require.Equal(t, []string{"a", "a2", "b", "c"}, members)
+
+				members, err = conn.ZRangeByScore(ctx, "z", &cache.ZRangeBy{Min: "(1", Max: "3", Offset: 1, Count: 1})
+				require.NoError(t, err)
+				require.Equal(t, []string{"b"}, members)
+
+				n, err := conn.ZRem(ctx, "z", "a", "d")
+				require.NoError(t, err)
+				require.Equal(t, int64(1), n)
+
+				_, err = conn.ZRangeByScore(ctx, "z", &cache.ZRangeBy{Min: "a", Max: "+inf"})
+				require.ErrorIs(t, err, ErrNotFloat)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runConnTestCase(t, &tc)
+		})
+	}
+}
+
+func TestTx(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]connTestCase{
+		"SUCCESS exec": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				tx := conn.Begin(ctx)
+				if err := tx.Set(ctx, "a", "b", 0); err != nil {
+					return err
+				}
+				if _, err := tx.SAdd(ctx, "s", "a"); err != nil {
+					return err
+				}
+
+				// Nothing is visible until the commands are executed
+				n, err := conn.Exists(ctx, "a", "s")
+				require.NoError(t, err)
+				require.Zero(t, n)
+
+				return tx.Exec(ctx)
+			},
+			exp: func(conn *Conn, err error) {
+				require.NoError(t, err)
+
+				n, err := conn.Exists(ctx, "a", "s")
+				require.NoError(t, err)
+				require.Equal(t, int64(2), n)
+			},
+		},
+		"SUCCESS get is scanned on exec": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				if err := conn.Set(ctx, "a", "b", 0); err != nil {
+					return err
+				}
+
+				var s string
+				tx := conn.Begin(ctx)
+				if err := tx.Get(ctx, "a", &s); err != nil {
+					return err
+				}
+				require.Empty(t, s)
+
+				if err := tx.Exec(ctx); err != nil {
+					return err
+				}
+				require.Equal(t, "b", s)
+				return nil
+			},
+			exp: func(_ *Conn, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS discard": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				tx := conn.Begin(ctx)
+				if err := tx.Set(ctx, "a", "b", 0); err != nil {
+					return err
+				}
+				if err := tx.Discard(ctx); err != nil {
+					return err
+				}
+
+				return tx.Exec(ctx)
+			},
+			exp: func(conn *Conn, err error) {
+				require.NoError(t, err)
+
+				n, err := conn.Exists(ctx, "a")
+				require.NoError(t, err)
+				require.Zero(t, n)
+			},
+		},
+		"FAILED command does not stop the rest": {
+			cmd: func(conn *Conn, _ *testClock) error {
+				if err := conn.Set(ctx, "a", "b", 0); err != nil {
+					return err
+				}
+
+				tx := conn.Begin(ctx)
+				if _, err := tx.Incr(ctx, "a"); err != nil {
+					return err
+				}
+				if err := tx.Set(ctx, "c", "d", 0); err != nil {
+					return err
+				}
+
+				return tx.Exec(ctx)
+			},
+			exp: func(conn *Conn, err error) {
+				require.ErrorIs(t, err, ErrNotInteger)
+
+				n, err := conn.Exists(ctx, "c")
+				require.NoError(t, err)
+				require.Equal(t, int64(1), n)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runConnTestCase(t, &tc)
+		})
+	}
+}
+
+func TestClient_LockStore(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]clientTestCase{
+		"SUCCESS compare and set": {
+			cmd: func(client *Client, clock *testClock) error {
+				if _, err := client.SetNX(ctx, "a", "b", time.Minute); err != nil {
+					return err
+				}
+
+				clock.Add(time.Minute)
+				ok, err := client.SetNX(ctx, "a", "c", time.Minute)
+				require.True(t, ok)
+				return err
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				ok, err := client.SetNX(ctx, "a", "d", 0)
+				require.NoError(t, err)
+				require.False(t, ok)
+
+				ok, err = client.CompareAndExpire(ctx, "a", "b", time.Hour)
+				require.NoError(t, err)
+				require.False(t, ok)
+
+				ok, err = client.CompareAndExpire(ctx, "a", "c", time.Hour)
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				ttl, err := client.TTL(ctx, "a")
+				require.NoError(t, err)
+				require.Equal(t, time.Hour, ttl)
+
+				ok, err = client.CompareAndDelete(ctx, "a", "b")
+				require.NoError(t, err)
+				require.False(t, ok)
+
+				ok, err = client.CompareAndDelete(ctx, "a", "c")
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				n, err := client.Exists(ctx, "a")
+				require.NoError(t, err)
+				require.Zero(t, n)
+			},
+		},
+		"FAILED compare wrong type": {
+			cmd: func(client *Client, _ *testClock) error {
+				if _, err := client.SAdd(ctx, "a", "b"); err != nil {
+					return err
+				}
+
+				_, err := client.CompareAndDelete(ctx, "a", "b")
+				return err
+			},
+			exp: func(_ *Client, err error) {
+				require.ErrorIs(t, err, ErrWrongType)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runClientTestCase(t, &tc)
+		})
+	}
+}
+
+func TestClient_Watch(t *testing.T) {
+	ctx := context.Background()
+
+	// setInTx sets the key within a transaction begun on the watching connection
+	setInTx := func(conn cache.WatchConn, key, value string) error {
+		tx := conn.Begin(ctx)
+		if err := tx.Set(ctx, key, value, 0); err != nil {
+			return err
+		}
+
+		return tx.Exec(ctx)
+	}
+
+	tcs := map[string]struct {
+		cmd func(client *Client) error
+		exp func(client *Client, err error)
+	}{
+		"SUCCESS watched key is not modified": {
+			cmd: func(client *Client) error {
+				return client.Watch(ctx, func(conn cache.WatchConn) error {
+					var s string
+					if err := conn.Get(ctx, "a", &s); err != nil {
+						return err
+					}
+
+					return setInTx(conn, "a", s+"c")
+				}, "a")
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				var s string
+				require.NoError(t, client.Get(ctx, "a", &s))
+				require.Equal(t, "bc", s)
+			},
+		},
+		"SUCCESS another key is modified": {
+			cmd: func(client *Client) error {
+				return client.Watch(ctx, func(conn cache.WatchConn) error {
+					if err := client.Set(ctx, "other", "b", 0); err != nil {
+						return err
+					}
+
+					return setInTx(conn, "a", "c")
+				}, "a")
+			},
+			exp: func(_ *Client, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED watched key is modified": {
+			cmd: func(client *Client) error {
+				return client.Watch(ctx, func(conn cache.WatchConn) error {
+					if _, err := client.SAdd(ctx, "s", "a"); err != nil {
+						return err
+					}
+
+					return setInTx(conn, "a", "c")
+				}, "a", "s")
+			},
+			exp: func(client *Client, err error) {
+				require.ErrorIs(t, err, cache.ErrTxFailed)
+
+				var s string
+				require.NoError(t, client.Get(ctx, "a", &s))
+				require.Equal(t, "b", s)
+			},
+		},
+		"FAILED key watched later is modified": {
+			cmd: func(client *Client) error {
+				return client.Watch(ctx, func(conn cache.WatchConn) error {
+					if err := conn.Watch(ctx, "b"); err != nil {
+						return err
+					}
+					if err := client.Set(ctx, "b", "c", 0); err != nil {
+						return err
+					}
+
+					return setInTx(conn, "a", "c")
+				}, "a")
+			},
+			exp: func(_ *Client, err error) {
+				require.ErrorIs(t, err, cache.ErrTxFailed)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+			client := New(logger, nil)
+			require.NoError(t, client.Set(ctx, "a", "b", 0))
+
+			err := tc.cmd(client)
+			tc.exp(client, err)
+		})
+	}
+}
+
+func TestClient_RateLimits(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]clientTestCase{
+		"SUCCESS sliding window log": {
+			cmd: func(client *Client, clock *testClock) error {
+				rl, err := client.SlidingWindowLog(ctx, "a", 2, time.Minute)
+				require.NoError(t, err)
+				require.Equal(t, ratelimit.RateLimit{
+					Allowed:    true,
+					Limit:      2,
+					Remaining:  1,
+					ResetAfter: time.Minute,
+				}, rl)
+
+				clock.Add(10 * time.Second)
+				rl, err = client.SlidingWindowLog(ctx, "a", 2, time.Minute)
+				require.NoError(t, err)
+				require.True(t, rl.Allowed)
+				require.Zero(t, rl.Remaining)
+
+				clock.Add(10 * time.Second)
+				rl, err = client.SlidingWindowLog(ctx, "a", 2, time.Minute)
+				require.NoError(t, err)
+				require.Equal(t, ratelimit.RateLimit{
+					Limit:      2,
+					RetryAfter: 40 * time.Second,
+					ResetAfter: 50 * time.Second,
+				}, rl)
+
+				// The first request leaves the window
+				clock.Add(40 * time.Second)
+				rl, err = client.SlidingWindowLog(ctx, "a", 2, time.Minute)
+				require.True(t, rl.Allowed)
+				return err
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				ttl, err := client.TTL(ctx, "a")
+				require.NoError(t, err)
+				require.Equal(t, time.Minute, ttl)
+			},
+		},
+		"SUCCESS token bucket": {
+			cmd: func(client *Client, clock *testClock) error {
+				for i := range 2 {
+					rl, err := client.TokenBucket(ctx, "a", 2, 30*time.Second)
+					require.NoError(t, err)
+					require.Equal(t, ratelimit.RateLimit{
+						Allowed:    true,
+						Limit:      2,
+						Remaining:  int64(1 - i),
+						ResetAfter: time.Duration(i+1) * 30 * time.Second,
+					}, rl)
+				}
+
+				rl, err := client.TokenBucket(ctx, "a", 2, 30*time.Second)
+				require.NoError(t, err)
+				require.False(t, rl.Allowed)
+				require.Equal(t, 30*time.Second, rl.RetryAfter)
+
+				clock.Add(30 * time.Second)
+				rl, err = client.TokenBucket(ctx, "a", 2, 30*time.Second)
+				require.NoError(t, err)
+				require.True(t, rl.Allowed)
+				require.Zero(t, rl.Remaining)
+
+				clock.Add(15 * time.Second)
+				rl, err = client.TokenBucket(ctx, "a", 2, 30*time.Second)
+				require.NoError(t, err)
+				require.Equal(t, ratelimit.RateLimit{
+					Limit:      2,
+					RetryAfter: 15 * time.Second,
+					ResetAfter: 45 * time.Second,
+				}, rl)
+
+				// The bucket is full again, once the key expires
+				clock.Add(45 * time.Second)
+				rl, err = client.TokenBucket(ctx, "a", 2, 30*time.Second)
+				require.Equal(t, int64(1), rl.Remaining)
+				return err
+			},
+			exp: func(_ *Client, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED invalid limit": {
+			cmd: func(client *Client, _ *testClock) error {
+				_, err := client.TokenBucket(ctx, "a", 1, time.Microsecond)
+				return err
+			},
+			exp: func(_ *Client, err error) {
+				require.ErrorIs(t, err, ratelimit.ErrInvalidRateLimit)
+			},
+		},
+		"FAILED wrong type": {
+			cmd: func(client *Client, _ *testClock) error {
+				if err := client.Set(ctx, "a", "b", 0); err != nil {
+					return err
+				}
+
+				_, err := client.SlidingWindowLog(ctx, "a", 1, time.Minute)
+				return err
+			},
+			exp: func(_ *Client, err error) {
+				require.ErrorIs(t, err, ErrWrongType)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runClientTestCase(t, &tc)
+		})
+	}
+}
+
+func TestConn_Scan(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+
+	client := New(logger, nil)
+	require.NoError(t, client.MSet(ctx, "session:1", "a", "session:2", "b", "user:1", "c"))
+	require.NoError(t, client.Set(ctx, "session:3", "d", time.Nanosecond))
+	_, err := client.HSet(ctx, "h", "a", "1", "b", "2")
+	require.NoError(t, err)
+	_, err = client.ZAdd(ctx, "z", cache.Z{Score: 1.5, Member: "a"})
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+
+	keys := scanAll(t, client.ScanKeys(ctx, "session:*", 0))
+	require.Equal(t, []string{"session:1", "session:2"}, keys)
+
+	keys = scanAll(t, client.ScanKeys(ctx, "", 0))
+	require.Equal(t, []string{"h", "session:1", "session:2", "user:1", "z"}, keys)
+
+	vals := scanAll(t, client.HScan(ctx, "h", "b", 0))
+	require.Equal(t, []string{"b", "2"}, vals)
+
+	vals = scanAll(t, client.ZScan(ctx, "z", "", 0))
+	require.Equal(t, []string{"a", "1.5"}, vals)
+
+	it := client.SScan(ctx, "h", "", 0)
+	require.False(t, it.Next(ctx))
+	require.ErrorIs(t, it.Err(), ErrWrongType)
+}
+
+func TestMatch(t *testing.T) {
+	tcs := map[string]struct {
+		pattern string
+		s       string
+		exp     bool
+	}{
+		"empty pattern":       {pattern: "", s: "a", exp: true},
+		"literal":             {pattern: "abc", s: "abc", exp: true},
+		"literal mismatch":    {pattern: "abc", s: "abd", exp: false},
+		"star":                {pattern: "a*c", s: "abbbc", exp: true},
+		"star empty":          {pattern: "a*", s: "a", exp: true},
+		"star with slash":     {pattern: "session:*", s: "session:a/b", exp: true},
+		"question":            {pattern: "a?c", s: "abc", exp: true},
+		"question too short":  {pattern: "a?", s: "a", exp: false},
+		"class":               {pattern: "h[ae]llo", s: "hello", exp: true},
+		"class mismatch":      {pattern: "h[ae]llo", s: "hillo", exp: false},
+		"negated class":       {pattern: "h[^e]llo", s: "hallo", exp: true},
+		"negated mismatch":    {pattern: "h[^e]llo", s: "hello", exp: false},
+		"range":               {pattern: "[a-c]", s: "b", exp: true},
+		"reversed range":      {pattern: "[c-a]", s: "b", exp: true},
+		"escaped star":        {pattern: `a\*`, s: "a*", exp: true},
+		"escaped star strict": {pattern: `a\*`, s: "ab", exp: false},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.exp, match(tc.pattern, tc.s))
+		})
+	}
+}
+
+func TestClient_Run(t *testing.T) {
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	client := New(logger, &Config{CleanupPeriod: time.Millisecond})
+	require.NoError(t, client.Set(context.Background(), "a", "b", time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- client.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		client.db.mu.Lock()
+		defer client.db.mu.Unlock()
+
+		return len(client.db.entries) == 0
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
+func scanAll(t *testing.T, it cache.ScanIterator) []string {
+	var vals []string
+	for it.Next(context.Background()) {
+		vals = append(vals, it.Val())
+	}
+	require.NoError(t, it.Err())
+
+	return vals
+}
+
+// runConnTestCase should be called by [testing.T.Run]
+func runConnTestCase(t *testing.T, tc *connTestCase) {
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	client := New(logger, nil)
+
+	clock := &testClock{now: time.Now()}
+	client.db.now = clock.Now
+
+	var err error
+	if tc.cmd != nil {
+		err = tc.cmd(&client.Conn, clock)
+	}
+
+	if tc.exp != nil {
+		tc.exp(&client.Conn, err)
+	}
+}
+
+// runClientTestCase should be called by [testing.T.Run]
+func runClientTestCase(t *testing.T, tc *clientTestCase) {
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	client := New(logger, nil)
+
+	clock := &testClock{now: time.Now()}
+	client.db.now = clock.Now
+
+	var err error
+	if tc.cmd != nil {
+		err = tc.cmd(client, clock)
+	}
+
+	if tc.exp != nil {
+		tc.exp(client, err)
+	}
+}
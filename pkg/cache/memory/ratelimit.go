@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/ratelimit"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+)
+
+// rateLimitFn counts the request at the time in milliseconds
+type rateLimitFn func(d *db, key string, limit, period, now int64) (ratelimit.RateLimit, error)
+
+// SlidingWindowLog keeps the timestamps of the requests
+// in a sorted set, as the redis client does
+func (c *Client) SlidingWindowLog(_ context.Context, key string, limit int64, window time.Duration) (ratelimit.RateLimit, error) {
+	logger := c.logger.With(log.Fields{
+		"key":    key,
+		"limit":  limit,
+		"window": window,
+	})
+
+	rl, err := c.rateLimit(slidingWindowLog, key, limit, window)
+	if err != nil {
+		logger.WithError(err).Error("failed to log the request in the sliding window")
+		return ratelimit.RateLimit{}, err
+	}
+
+	logger.Debug(fmt.Sprintf("logged the request in the sliding window: %t", rl.Allowed))
+	return rl, nil
+}
+
+// TokenBucket keeps the number of tokens and the time
+// they were counted at in a hash, as the redis client does
+func (c *Client) TokenBucket(_ context.Context, key string, capacity int64, interval time.Duration) (ratelimit.RateLimit, error) {
+	logger := c.logger.With(log.Fields{
+		"key":      key,
+		"capacity": capacity,
+		"interval": interval,
+	})
+
+	rl, err := c.rateLimit(tokenBucket, key, capacity, interval)
+	if err != nil {
+		logger.WithError(err).Error("failed to take a token from the bucket")
+		return ratelimit.RateLimit{}, err
+	}
+
+	logger.Debug(fmt.Sprintf("took a token from the bucket: %t", rl.Allowed))
+	return rl, nil
+}
+
+func (c *Client) rateLimit(fn rateLimitFn, key string, limit int64, period time.Duration) (ratelimit.RateLimit, error) {
+	if limit <= 0 || period.Milliseconds() <= 0 {
+		return ratelimit.RateLimit{}, proxerr.New(ratelimit.ErrInvalidRateLimit,
+			fmt.Sprintf("invalid rate limit %d per %s", limit, period))
+	}
+
+	var rl ratelimit.RateLimit
+	err := c.do(func(d *db) error {
+		var err error
+		rl, err = fn(d, key, limit, period.Milliseconds(), d.now().UnixMilli())
+		return err
+	})
+
+	return rl, err
+}
+
+func slidingWindowLog(d *db, key string, limit, window, now int64) (ratelimit.RateLimit, error) {
+	zs, err := lookupOrCreate[sortedSet](d, key)
+	if err != nil {
+		return ratelimit.RateLimit{}, err
+	}
+
+	for member, score := range zs {
+		if score <= float64(now-window) {
+			delete(zs, member)
+		}
+	}
+
+	rl := ratelimit.RateLimit{Limit: limit}
+	if int64(len(zs)) < limit {
+		member := strconv.FormatInt(now, 10) + ":" + strconv.FormatUint(rand.Uint64(), 16)
+		zs[member] = float64(now)
+		d.expire(key, time.Duration(window)*time.Millisecond)
+		rl.Allowed = true
+	}
+
+	if len(zs) > 0 {
+		oldest, newest := math.Inf(1), math.Inf(-1)
+		for _, score := range zs {
+			oldest, newest = min(oldest, score), max(newest, score)
+		}
+
+		if !rl.Allowed {
+			rl.RetryAfter = time.Duration(int64(oldest)+window-now) * time.Millisecond
+		}
+		rl.ResetAfter = time.Duration(int64(newest)+window-now) * time.Millisecond
+	}
+
+	rl.Remaining = max(limit-int64(len(zs)), 0)
+	deleteIfEmpty(d, key, zs)
+	return rl, nil
+}
+
+func tokenBucket(d *db, key string, capacity, interval, now int64) (ratelimit.RateLimit, error) {
+	h, err := lookupOrCreate[hash](d, key)
+	if err != nil {
+		return ratelimit.RateLimit{}, err
+	}
+
+	tokens, tokensErr := strconv.ParseInt(h["tokens"], 10, 64)
+	ts, tsErr := strconv.ParseInt(h["ts"], 10, 64)
+	if tokensErr != nil || tsErr != nil {
+		tokens, ts = capacity, now
+	}
+
+	// Only whole tokens are refilled, and the time is moved forward by their intervals
+	refill := max(now-ts, 0) / interval
+	tokens += refill
+	ts += refill * interval
+	if tokens >= capacity {
+		tokens, ts = capacity, now
+	}
+
+	rl := ratelimit.RateLimit{Limit: capacity}
+	if tokens > 0 {
+		tokens--
+		rl.Allowed = true
+	}
+
+	elapsed := max(now-ts, 0)
+	if !rl.Allowed {
+		rl.RetryAfter = time.Duration(interval-elapsed) * time.Millisecond
+	}
+	reset := (capacity-tokens)*interval - elapsed
+	rl.ResetAfter = time.Duration(reset) * time.Millisecond
+	rl.Remaining = tokens
+
+	h["tokens"] = strconv.FormatInt(tokens, 10)
+	h["ts"] = strconv.FormatInt(ts, 10)
+	d.expire(key, time.Duration(max(reset, 1))*time.Millisecond)
+	return rl, nil
+}
@@ -0,0 +1,230 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/cache"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+)
+
+// keyScanFn returns the matching elements of the collection
+// stored by the key in the order they are iterated
+type keyScanFn func(d *db, key, pattern string) ([]string, error)
+
+// ScanKeys returns the keys in the lexicographical order. All the keys
+// are matched at once, so the count is ignored. Unlike the other
+// commands, the scans are not queued within a [Tx]
+func (c *Conn) ScanKeys(_ context.Context, match string, _ int64) cache.ScanIterator {
+	return newScanIterator(c.db.keys(match), nil)
+}
+
+// SScan returns the members in the lexicographical order
+func (c *Conn) SScan(_ context.Context, key, match string, _ int64) cache.ScanIterator {
+	return c.scan(scanSet, key, match)
+}
+
+// HScan returns the fields in the lexicographical order
+func (c *Conn) HScan(_ context.Context, key, match string, _ int64) cache.ScanIterator {
+	return c.scan(scanHash, key, match)
+}
+
+// ZScan returns the members in the lexicographical order
+func (c *Conn) ZScan(_ context.Context, key, match string, _ int64) cache.ScanIterator {
+	return c.scan(scanSortedSet, key, match)
+}
+
+func (c *Conn) scan(fn keyScanFn, key, match string) cache.ScanIterator {
+	c.db.mu.Lock()
+	vals, err := fn(c.db, key, match)
+	c.db.mu.Unlock()
+
+	if err != nil {
+		c.logger.With(log.Fields{"key": key}).WithError(err).Error("failed to scan the key")
+	}
+
+	return newScanIterator(vals, err)
+}
+
+func scanSet(d *db, key, pattern string) ([]string, error) {
+	s, err := lookup[set](d, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var vals []string
+	for _, member := range slices.Sorted(maps.Keys(s)) {
+		if match(pattern, member) {
+			vals = append(vals, member)
+		}
+	}
+
+	return vals, nil
+}
+
+func scanHash(d *db, key, pattern string) ([]string, error) {
+	h, err := lookup[hash](d, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var vals []string
+	for _, field := range slices.Sorted(maps.Keys(h)) {
+		if match(pattern, field) {
+			vals = append(vals, field, h[field])
+		}
+	}
+
+	return vals, nil
+}
+
+func scanSortedSet(d *db, key, pattern string) ([]string, error) {
+	zs, err := lookup[sortedSet](d, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var vals []string
+	for _, member := range slices.Sorted(maps.Keys(zs)) {
+		if match(pattern, member) {
+			vals = append(vals, member, formatFloat(zs[member]))
+		}
+	}
+
+	return vals, nil
+}
+
+type scanIterator struct {
+	vals []string
+	pos  int
+	err  error
+}
+
+func newScanIterator(vals []string, err error) *scanIterator {
+	return &scanIterator{vals: vals, pos: -1, err: err}
+}
+
+func (it *scanIterator) Err() error {
+	return it.err
+}
+
+func (it *scanIterator) Val() string {
+	if it.pos < 0 || it.pos >= len(it.vals) {
+		return ""
+	}
+
+	return it.vals[it.pos]
+}
+
+// Next stops when the context is done
+func (it *scanIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if it.pos+1 >= len(it.vals) {
+		return false
+	}
+
+	it.pos++
+	return true
+}
+
+// match reports whether the string matches the Redis glob-style pattern,
+// which supports "*", "?", "[abc]", "[^abc]", "[a-z]" and "\\" escaping.
+// The empty pattern matches any string, as the omitted MATCH option does
+func match(pattern, s string) bool {
+	return pattern == "" || globMatch(pattern, s)
+}
+
+func globMatch(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+
+			for i := 0; i <= len(s); i++ {
+				if globMatch(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			s = s[1:]
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+
+			var ok bool
+			if pattern, ok = matchClass(pattern[1:], s[0]); !ok {
+				return false
+			}
+			s = s[1:]
+			continue
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+			s = s[1:]
+		}
+
+		pattern = pattern[1:]
+	}
+
+	return len(s) == 0
+}
+
+// matchClass matches the character against the class, which follows "[",
+// and returns the rest of the pattern after the closing "]"
+func matchClass(pattern string, c byte) (string, bool) {
+	negate := len(pattern) > 0 && pattern[0] == '^'
+	if negate {
+		pattern = pattern[1:]
+	}
+
+	matched := false
+	for len(pattern) > 0 && pattern[0] != ']' {
+		switch {
+		case pattern[0] == '\\' && len(pattern) > 1:
+			matched = matched || pattern[1] == c
+			pattern = pattern[2:]
+		case len(pattern) > 2 && pattern[1] == '-' && pattern[2] != ']':
+			lo, hi := pattern[0], pattern[2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+
+			matched = matched || (c >= lo && c <= hi)
+			pattern = pattern[3:]
+		default:
+			matched = matched || pattern[0] == c
+			pattern = pattern[1:]
+		}
+	}
+
+	if len(pattern) > 0 {
+		pattern = pattern[1:]
+	}
+
+	return pattern, matched != negate
+}
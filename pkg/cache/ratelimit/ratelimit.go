@@ -0,0 +1,213 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+)
+
+const (
+	// AlgorithmSlidingWindowLog allows Limit requests within any Window. It is
+	// exact, but keeps a timestamp per request, so it suits the low limits
+	AlgorithmSlidingWindowLog = "sliding_window_log"
+
+	// AlgorithmTokenBucket allows the bursts of up to Limit requests,
+	// and then a request per Window/Limit. It keeps two numbers per key
+	AlgorithmTokenBucket = "token_bucket"
+)
+
+// keyFormat will be interpreted as "rate_limit:<name>:<key>"
+const keyFormat = "rate_limit:%s:%s"
+
+var (
+	ErrInvalidAlgorithm = errors.New("invalid rate limit algorithm")
+	ErrInvalidRateLimit = errors.New("the limit and the window must be positive")
+)
+
+// RateLimit is the state of the limit after the request was counted
+type RateLimit struct {
+	Allowed   bool
+	Limit     int64
+	Remaining int64
+
+	// RetryAfter is the time until the next request is
+	// allowed. It is zero, if the request is allowed
+	RetryAfter time.Duration
+
+	// ResetAfter is the time until the quota is fully restored
+	ResetAfter time.Duration
+}
+
+// Store is implemented by the clients of the cache backends. Its commands
+// are atomic on their own, so they are not a part of cache.Conn and
+// can't be queued within a cache.Tx
+type Store interface {
+	// SlidingWindowLog atomically logs the request by the key, if less
+	// than limit requests have been logged within the last window.
+	// The window is truncated to milliseconds
+	SlidingWindowLog(ctx context.Context, key string, limit int64, window time.Duration) (RateLimit, error)
+
+	// TokenBucket atomically takes a token from the bucket by the key. The bucket
+	// holds up to capacity tokens and is refilled by a token per interval.
+	// The interval is truncated to milliseconds
+	TokenBucket(ctx context.Context, key string, capacity int64, interval time.Duration) (RateLimit, error)
+}
+
+// Key identifies the subject of the limit
+type Key string
+
+// UserID keys the limit by the id of an authenticated user
+func UserID(id string) Key {
+	return Key("user:" + id)
+}
+
+// Email keys the limit by the email, which is normalized,
+// so that the case doesn't bypass the limit, and hashed
+func Email(email string) Key {
+	return Key("email:" + hash(strings.ToLower(strings.TrimSpace(email))))
+}
+
+// ClientIP keys the limit by the address of the client,
+// e.g. the ClientIP of the session fingerprint
+func ClientIP(ip string) Key {
+	return Key("ip:" + ip)
+}
+
+// APIKey keys the limit by the hash of the key,
+// so that the secret isn't stored in the cache
+func APIKey(key string) Key {
+	return Key("api_key:" + hash(key))
+}
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+type Config struct {
+	// Algorithm is one of the Algorithm constants.
+	// It is [AlgorithmSlidingWindowLog] by default
+	Algorithm string
+
+	// Limit is the number of requests allowed within the Window
+	Limit  int64
+	Window time.Duration
+}
+
+// Limiter counts the requests in the cache, so the limits
+// are shared by all the instances connected to it
+type Limiter struct {
+	conn   Store
+	name   string
+	config Config
+	logger log.Logger
+}
+
+// NewLimiter validates the config. The name separates the
+// limits of the different actions, e.g. "login" and "refresh"
+func NewLimiter(conn Store, logger log.Logger, name string, config *Config) (*Limiter, error) {
+	l := &Limiter{
+		conn:   conn,
+		name:   name,
+		logger: logger.With(log.Fields{"rate_limit": name}),
+	}
+	if config != nil {
+		l.config = *config
+	}
+	if l.config.Algorithm == "" {
+		l.config.Algorithm = AlgorithmSlidingWindowLog
+	}
+
+	if err := l.validate(); err != nil {
+		l.logger.WithError(err).Error("failed to validate the rate limit config")
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (l *Limiter) validate() error {
+	if l.config.Limit <= 0 || l.config.Window <= 0 {
+		return proxerr.New(ErrInvalidRateLimit,
+			fmt.Sprintf("invalid rate limit %d per %s", l.config.Limit, l.config.Window))
+	}
+
+	switch l.config.Algorithm {
+	case AlgorithmSlidingWindowLog:
+		if l.config.Window.Milliseconds() == 0 {
+			return proxerr.New(ErrInvalidRateLimit,
+				fmt.Sprintf("the window %s is shorter than a millisecond", l.config.Window))
+		}
+	case AlgorithmTokenBucket:
+		if l.interval().Milliseconds() == 0 {
+			return proxerr.New(ErrInvalidRateLimit,
+				fmt.Sprintf("the token interval %s is shorter than a millisecond", l.interval()))
+		}
+	default:
+		return proxerr.New(ErrInvalidAlgorithm, fmt.Sprintf("invalid rate limit algorithm %q", l.config.Algorithm))
+	}
+
+	return nil
+}
+
+// interval is the time to refill a token of the bucket
+func (l *Limiter) interval() time.Duration {
+	return l.config.Window / time.Duration(l.config.Limit)
+}
+
+// Allow counts the request by the key. The denied requests
+// are not counted, so they don't prolong the limit
+func (l *Limiter) Allow(ctx context.Context, key Key) (RateLimit, error) {
+	logger := l.logger.With(log.Fields{"key": key})
+	cacheKey := fmt.Sprintf(keyFormat, l.name, key)
+
+	var (
+		rl  RateLimit
+		err error
+	)
+	switch l.config.Algorithm {
+	case AlgorithmTokenBucket:
+		rl, err = l.conn.TokenBucket(ctx, cacheKey, l.config.Limit, l.interval())
+	default:
+		rl, err = l.conn.SlidingWindowLog(ctx, cacheKey, l.config.Limit, l.config.Window)
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to count the request")
+		return RateLimit{}, err
+	}
+
+	if rl.Allowed {
+		logger.Debug("counted the request")
+	} else {
+		logger.Debug("the rate limit is exceeded")
+	}
+
+	return rl, nil
+}
+
+// SetHeaders sets the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset
+// headers, and Retry-After if the request is denied. The times are rounded up
+// to seconds, so that the clients don't retry too early
+func SetHeaders(h http.Header, rl RateLimit) {
+	h.Set("RateLimit-Limit", strconv.FormatInt(rl.Limit, 10))
+	h.Set("RateLimit-Remaining", strconv.FormatInt(rl.Remaining, 10))
+	h.Set("RateLimit-Reset", formatSeconds(rl.ResetAfter))
+
+	if !rl.Allowed {
+		h.Set("Retry-After", formatSeconds(rl.RetryAfter))
+	}
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
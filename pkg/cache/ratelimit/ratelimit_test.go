@@ -0,0 +1,230 @@
+package ratelimit_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stdslog "log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/memory"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/ratelimit"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/redis"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// Both of the cache backends implement the store
+var (
+	_ ratelimit.Store = (*memory.Client)(nil)
+	_ ratelimit.Store = (*redis.Client)(nil)
+)
+
+type (
+	limiterTestCaseCommand func(store ratelimit.Store) (*ratelimit.Limiter, error)
+	limiterTestCaseExpect  func(limiter *ratelimit.Limiter, err error)
+
+	limiterTestCase struct {
+		cmd limiterTestCaseCommand
+		exp limiterTestCaseExpect
+	}
+)
+
+func TestNewLimiter(t *testing.T) {
+	tcs := map[string]limiterTestCase{
+		"SUCCESS default algorithm": {
+			cmd: func(store ratelimit.Store) (*ratelimit.Limiter, error) {
+				// The token interval would be too short for the token bucket
+				return newTestLimiter(store, "login", &ratelimit.Config{Limit: 2000, Window: time.Second})
+			},
+			exp: func(limiter *ratelimit.Limiter, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, limiter)
+			},
+		},
+		"FAILED invalid algorithm": {
+			cmd: func(store ratelimit.Store) (*ratelimit.Limiter, error) {
+				return newTestLimiter(store, "login", &ratelimit.Config{Algorithm: "fixed_window", Limit: 5, Window: time.Minute})
+			},
+			exp: func(_ *ratelimit.Limiter, err error) {
+				require.ErrorIs(t, err, ratelimit.ErrInvalidAlgorithm)
+			},
+		},
+		"FAILED no limit": {
+			cmd: func(store ratelimit.Store) (*ratelimit.Limiter, error) {
+				return newTestLimiter(store, "login", nil)
+			},
+			exp: func(_ *ratelimit.Limiter, err error) {
+				require.ErrorIs(t, err, ratelimit.ErrInvalidRateLimit)
+			},
+		},
+		"FAILED token interval is too short": {
+			cmd: func(store ratelimit.Store) (*ratelimit.Limiter, error) {
+				return newTestLimiter(store, "login", &ratelimit.Config{
+					Algorithm: ratelimit.AlgorithmTokenBucket,
+					Limit:     2000,
+					Window:    time.Second,
+				})
+			},
+			exp: func(_ *ratelimit.Limiter, err error) {
+				require.ErrorIs(t, err, ratelimit.ErrInvalidRateLimit)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runLimiterTestCase(t, &tc)
+		})
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]limiterTestCase{
+		"SUCCESS sliding window log": {
+			cmd: func(store ratelimit.Store) (*ratelimit.Limiter, error) {
+				return newTestLimiter(store, "login", &ratelimit.Config{Limit: 2, Window: time.Minute})
+			},
+			exp: func(limiter *ratelimit.Limiter, err error) {
+				require.NoError(t, err)
+
+				for range 2 {
+					rl, err := limiter.Allow(ctx, ratelimit.Email("user@example.com"))
+					require.NoError(t, err)
+					require.True(t, rl.Allowed)
+				}
+
+				rl, err := limiter.Allow(ctx, ratelimit.Email(" User@Example.com"))
+				require.NoError(t, err)
+				require.False(t, rl.Allowed)
+				require.Zero(t, rl.Remaining)
+				require.Positive(t, rl.RetryAfter)
+
+				// The other keys are limited separately
+				rl, err = limiter.Allow(ctx, ratelimit.ClientIP("127.0.0.1"))
+				require.NoError(t, err)
+				require.True(t, rl.Allowed)
+			},
+		},
+		"SUCCESS token bucket": {
+			cmd: func(store ratelimit.Store) (*ratelimit.Limiter, error) {
+				return newTestLimiter(store, "refresh", &ratelimit.Config{
+					Algorithm: ratelimit.AlgorithmTokenBucket,
+					Limit:     2,
+					Window:    time.Minute,
+				})
+			},
+			exp: func(limiter *ratelimit.Limiter, err error) {
+				require.NoError(t, err)
+
+				for range 2 {
+					rl, err := limiter.Allow(ctx, ratelimit.UserID("a"))
+					require.NoError(t, err)
+					require.True(t, rl.Allowed)
+				}
+
+				rl, err := limiter.Allow(ctx, ratelimit.UserID("a"))
+				require.NoError(t, err)
+				require.False(t, rl.Allowed)
+				require.Equal(t, int64(2), rl.Limit)
+				require.LessOrEqual(t, rl.RetryAfter, 30*time.Second)
+				require.LessOrEqual(t, rl.ResetAfter, time.Minute)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runLimiterTestCase(t, &tc)
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	type testCase struct {
+		key ratelimit.Key
+		exp ratelimit.Key
+	}
+
+	tcs := map[string]testCase{
+		"user id":            {key: ratelimit.UserID("a"), exp: "user:a"},
+		"client ip":          {key: ratelimit.ClientIP("127.0.0.1"), exp: "ip:127.0.0.1"},
+		"normalized email":   {key: ratelimit.Email(" User@Example.com "), exp: ratelimit.Email("user@example.com")},
+		"hashed api key":     {key: ratelimit.APIKey("secret"), exp: "api_key:" + ratelimit.Key(hash("secret"))},
+		"case-sensitive key": {key: ratelimit.APIKey("Secret"), exp: "api_key:" + ratelimit.Key(hash("Secret"))},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.exp, tc.key)
+			require.NotContains(t, string(tc.key), "secret")
+		})
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	type testCase struct {
+		rl  ratelimit.RateLimit
+		exp http.Header
+	}
+
+	tcs := map[string]testCase{
+		"allowed": {
+			rl: ratelimit.RateLimit{Allowed: true, Limit: 5, Remaining: 4, ResetAfter: time.Minute},
+			exp: http.Header{
+				"Ratelimit-Limit":     {"5"},
+				"Ratelimit-Remaining": {"4"},
+				"Ratelimit-Reset":     {"60"},
+			},
+		},
+		"denied": {
+			rl: ratelimit.RateLimit{Limit: 5, RetryAfter: 1500 * time.Millisecond, ResetAfter: time.Minute},
+			exp: http.Header{
+				"Ratelimit-Limit":     {"5"},
+				"Ratelimit-Remaining": {"0"},
+				"Ratelimit-Reset":     {"60"},
+				"Retry-After":         {"2"},
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			h := make(http.Header)
+			ratelimit.SetHeaders(h, tc.rl)
+			require.Equal(t, tc.exp, h)
+		})
+	}
+}
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func newTestLimiter(store ratelimit.Store, name string, config *ratelimit.Config) (*ratelimit.Limiter, error) {
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	return ratelimit.NewLimiter(store, logger, name, config)
+}
+
+// runLimiterTestCase should be called by [testing.T.Run]
+func runLimiterTestCase(t *testing.T, tc *limiterTestCase) {
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	store := memory.New(logger, nil)
+
+	var (
+		limiter *ratelimit.Limiter
+		err     error
+	)
+	if tc.cmd != nil {
+		limiter, err = tc.cmd(store)
+	}
+
+	if tc.exp != nil {
+		tc.exp(limiter, err)
+	}
+}
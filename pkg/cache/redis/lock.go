@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// compareAndDeleteScript returns 1 if the key was deleted
+	compareAndDeleteScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+	// compareAndExpireScript takes the expiration in milliseconds
+	// and returns 1 if it was set
+	compareAndExpireScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+)
+
+// SetNX and the compare commands implement lock.Store. They are only run on
+// the [Client], as a lock must not be acquired or released within a transaction
+func (c *Client) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	logger := c.logger.With(log.Fields{
+		"key":        key,
+		"expiration": expiration,
+	})
+
+	ok, err := c.conn.SetNX(ctx, key, value, expiration).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to set the key if it does not exist")
+		return false, err
+	}
+
+	logger.Debug(fmt.Sprintf("set the key if it does not exist: %t", ok))
+	return ok, nil
+}
+
+func (c *Client) CompareAndDelete(ctx context.Context, key string, value any) (bool, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	ok, err := c.runScript(ctx, compareAndDeleteScript, []string{key}, value)
+	if err != nil {
+		logger.WithError(err).Error("failed to compare and delete the key")
+		return false, err
+	}
+
+	logger.Debug(fmt.Sprintf("compared and deleted the key: %t", ok))
+	return ok, nil
+}
+
+func (c *Client) CompareAndExpire(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	logger := c.logger.With(log.Fields{
+		"key":        key,
+		"expiration": expiration,
+	})
+
+	ok, err := c.runScript(ctx, compareAndExpireScript, []string{key}, value, expiration.Milliseconds())
+	if err != nil {
+		logger.WithError(err).Error("failed to compare and expire the key")
+		return false, err
+	}
+
+	logger.Debug(fmt.Sprintf("compared and expired the key: %t", ok))
+	return ok, nil
+}
+
+// runScript runs the script, which returns 1 on success. The script
+// is sent by its hash, and entirely only if Redis doesn't have it cached
+func (c *Client) runScript(ctx context.Context, script *redis.Script, keys []string, args ...any) (bool, error) {
+	n, err := script.Run(ctx, c.conn, keys, args...).Int64()
+	return n == 1, err
+}
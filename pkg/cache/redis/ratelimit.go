@@ -0,0 +1,160 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/ratelimit"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"github.com/redis/go-redis/v9"
+)
+
+// The rate limit scripts take the time from the Redis server, so that the
+// instances of the application with skewed clocks share the same limits.
+// They return {allowed, remaining, retry after, reset after} in milliseconds
+var (
+	// slidingWindowLogScript keeps the timestamps of the requests in a sorted set.
+	// It takes the limit, the window and a random suffix of the member
+	slidingWindowLogScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+
+local allowed = 0
+local count = redis.call("ZCARD", KEYS[1])
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, now .. ":" .. ARGV[3])
+	redis.call("PEXPIRE", KEYS[1], window)
+	count = count + 1
+	allowed = 1
+end
+
+local retry = 0
+local reset = 0
+if count > 0 then
+	local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+	local newest = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+	if allowed == 0 then
+		retry = tonumber(oldest[2]) + window - now
+	end
+	reset = tonumber(newest[2]) + window - now
+end
+
+return {allowed, limit - count, retry, reset}
+`)
+
+	// tokenBucketScript keeps the number of tokens and the time they were
+	// counted at in a hash. Only whole tokens are refilled, and the time is
+	// moved forward by their intervals. It takes the capacity and the interval
+	tokenBucketScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+
+local refill = math.floor(math.max(now - ts, 0) / interval)
+tokens = tokens + refill
+ts = ts + refill * interval
+if tokens >= capacity then
+	tokens = capacity
+	ts = now
+end
+
+local allowed = 0
+if tokens > 0 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+local elapsed = math.max(now - ts, 0)
+local retry = 0
+if allowed == 0 then
+	retry = interval - elapsed
+end
+local reset = (capacity - tokens) * interval - elapsed
+
+redis.call("HSET", KEYS[1], "tokens", tokens, "ts", ts)
+redis.call("PEXPIRE", KEYS[1], math.max(reset, 1))
+
+return {allowed, tokens, retry, reset}
+`)
+)
+
+func (c *Client) SlidingWindowLog(ctx context.Context, key string, limit int64, window time.Duration) (ratelimit.RateLimit, error) {
+	logger := c.logger.With(log.Fields{
+		"key":    key,
+		"limit":  limit,
+		"window": window,
+	})
+
+	rl, err := c.runRateLimitScript(ctx, slidingWindowLogScript, key, limit, window,
+		strconv.FormatUint(rand.Uint64(), 16))
+	if err != nil {
+		logger.WithError(err).Error("failed to log the request in the sliding window")
+		return ratelimit.RateLimit{}, err
+	}
+
+	logger.Debug(fmt.Sprintf("logged the request in the sliding window: %t", rl.Allowed))
+	return rl, nil
+}
+
+func (c *Client) TokenBucket(ctx context.Context, key string, capacity int64, interval time.Duration) (ratelimit.RateLimit, error) {
+	logger := c.logger.With(log.Fields{
+		"key":      key,
+		"capacity": capacity,
+		"interval": interval,
+	})
+
+	rl, err := c.runRateLimitScript(ctx, tokenBucketScript, key, capacity, interval)
+	if err != nil {
+		logger.WithError(err).Error("failed to take a token from the bucket")
+		return ratelimit.RateLimit{}, err
+	}
+
+	logger.Debug(fmt.Sprintf("took a token from the bucket: %t", rl.Allowed))
+	return rl, nil
+}
+
+func (c *Client) runRateLimitScript(
+	ctx context.Context,
+	script *redis.Script,
+	key string,
+	limit int64,
+	period time.Duration,
+	args ...any,
+) (ratelimit.RateLimit, error) {
+	if limit <= 0 || period.Milliseconds() <= 0 {
+		return ratelimit.RateLimit{}, proxerr.New(ratelimit.ErrInvalidRateLimit,
+			fmt.Sprintf("invalid rate limit %d per %s", limit, period))
+	}
+
+	args = append([]any{limit, period.Milliseconds()}, args...)
+
+	vals, err := script.Run(ctx, c.conn, []string{key}, args...).Int64Slice()
+	if err != nil {
+		return ratelimit.RateLimit{}, err
+	}
+
+	return ratelimit.RateLimit{
+		Allowed:    vals[0] == 1,
+		Limit:      limit,
+		Remaining:  max(vals[1], 0),
+		RetryAfter: time.Duration(vals[2]) * time.Millisecond,
+		ResetAfter: time.Duration(vals[3]) * time.Millisecond,
+	}, nil
+}
@@ -15,14 +15,32 @@ import (
 
 type (
 	DriverConn interface {
+		redis.Scripter
 		Get(ctx context.Context, key string) *redis.StringCmd
 		Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd
+		SetNX(ctx context.Context, key string, value any, expiration time.Duration) *redis.BoolCmd
+		MGet(ctx context.Context, keys ...string) *redis.SliceCmd
+		MSet(ctx context.Context, values ...any) *redis.StatusCmd
 		Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd
 		SScan(ctx context.Context, key string, cursor uint64, match string, count int64) *redis.ScanCmd
 		HScan(ctx context.Context, key string, cursor uint64, match string, count int64) *redis.ScanCmd
 		ZScan(ctx context.Context, key string, cursor uint64, match string, count int64) *redis.ScanCmd
 		Del(ctx context.Context, keys ...string) *redis.IntCmd
 		Exists(ctx context.Context, keys ...string) *redis.IntCmd
+		IncrBy(ctx context.Context, key string, value int64) *redis.IntCmd
+		HSet(ctx context.Context, key string, values ...any) *redis.IntCmd
+		HGet(ctx context.Context, key, field string) *redis.StringCmd
+		HGetAll(ctx context.Context, key string) *redis.MapStringStringCmd
+		HDel(ctx context.Context, key string, fields ...string) *redis.IntCmd
+		SAdd(ctx context.Context, key string, members ...any) *redis.IntCmd
+		SRem(ctx context.Context, key string, members ...any) *redis.IntCmd
+		SMembers(ctx context.Context, key string) *redis.StringSliceCmd
+		ZAdd(ctx context.Context, key string, members ...redis.Z) *redis.IntCmd
+		ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.StringSliceCmd
+		ZRem(ctx context.Context, key string, members ...any) *redis.IntCmd
+		Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
+		TTL(ctx context.Context, key string) *redis.DurationCmd
+		Persist(ctx context.Context, key string) *redis.BoolCmd
 		TxPipeline() redis.Pipeliner
 	}
 
@@ -80,8 +98,29 @@ func (c *Conn) Set(ctx context.Context, key string, value any, expiration time.D
 	return nil
 }
 
-func (c *Conn) Scan(ctx context.Context, scanner cache.Scanner) cache.ScanIterator {
-	return scanner.Scan(ctx)
+// MGet returns nil values for the keys, which do not exist
+func (c *Conn) MGet(ctx context.Context, keys ...string) ([]any, error) {
+	logger := c.logger.With(log.Fields{"keys": keys})
+
+	values, err := c.conn.MGet(ctx, keys...).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to get the keys")
+		return nil, err
+	}
+
+	logger.Debug("got the keys")
+	return values, nil
+}
+
+// MSet takes the keys and the values in turns
+func (c *Conn) MSet(ctx context.Context, values ...any) error {
+	if err := c.conn.MSet(ctx, values...).Err(); err != nil {
+		c.logger.WithError(err).Error("failed to set the keys")
+		return err
+	}
+
+	c.logger.Debug(fmt.Sprintf("set %d keys", len(values)/2))
+	return nil
 }
 
 func (c *Conn) Delete(ctx context.Context, key string) (int64, error) {
@@ -110,6 +149,277 @@ func (c *Conn) Exists(ctx context.Context, keys ...string) (int64, error) {
 	return n, nil
 }
 
+// Incr considers the key, which does not exist, to be 0
+func (c *Conn) Incr(ctx context.Context, key string) (int64, error) {
+	return c.IncrBy(ctx, key, 1)
+}
+
+// IncrBy considers the key, which does not exist, to be 0
+func (c *Conn) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	logger := c.logger.With(log.Fields{
+		"key":   key,
+		"value": value,
+	})
+
+	n, err := c.conn.IncrBy(ctx, key, value).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to increment the key")
+		return 0, err
+	}
+
+	logger.Debug("incremented the key")
+	return n, nil
+}
+
+// HSet takes the fields and the values in turns. It returns
+// the number of the fields, which were added
+func (c *Conn) HSet(ctx context.Context, key string, values ...any) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	n, err := c.conn.HSet(ctx, key, values...).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to set fields of the hash")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("added %d out of %d fields to the hash", n, len(values)/2))
+	return n, nil
+}
+
+// HGet returns [cache.ErrKeyDoesNotExist] if either the hash
+// or the field does not exist
+func (c *Conn) HGet(ctx context.Context, key, field string, dest any) error {
+	logger := c.logger.With(log.Fields{
+		"key":   key,
+		"field": field,
+	})
+
+	if err := c.conn.HGet(ctx, key, field).Scan(dest); err != nil {
+		if errors.Is(err, redis.Nil) {
+			err = proxerr.New(cache.ErrKeyDoesNotExist, err.Error())
+		}
+
+		logger.WithError(err).Error("failed to get the field of the hash")
+		return err
+	}
+
+	logger.Debug("got the field of the hash")
+	return nil
+}
+
+// HGetAll returns an empty map if the hash does not exist
+func (c *Conn) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	fields, err := c.conn.HGetAll(ctx, key).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to get fields of the hash")
+		return nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("got %d fields of the hash", len(fields)))
+	return fields, nil
+}
+
+func (c *Conn) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	n, err := c.conn.HDel(ctx, key, fields...).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to delete fields of the hash")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("deleted %d out of %d fields of the hash", n, len(fields)))
+	return n, nil
+}
+
+func (c *Conn) SAdd(ctx context.Context, key string, members ...any) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	n, err := c.conn.SAdd(ctx, key, members...).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to add members to the set")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("added %d out of %d members to the set", n, len(members)))
+	return n, nil
+}
+
+func (c *Conn) SRem(ctx context.Context, key string, members ...any) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	n, err := c.conn.SRem(ctx, key, members...).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to remove members from the set")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("removed %d out of %d members from the set", n, len(members)))
+	return n, nil
+}
+
+// SMembers returns an empty slice if the set does not exist
+func (c *Conn) SMembers(ctx context.Context, key string) ([]string, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	members, err := c.conn.SMembers(ctx, key).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to get members of the set")
+		return nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("got %d members of the set", len(members)))
+	return members, nil
+}
+
+// ZAdd updates the scores of the existing members. It returns
+// the number of the members, which were added
+func (c *Conn) ZAdd(ctx context.Context, key string, members ...cache.Z) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	zs := make([]redis.Z, len(members))
+	for i, m := range members {
+		zs[i] = redis.Z{Score: m.Score, Member: m.Member}
+	}
+
+	n, err := c.conn.ZAdd(ctx, key, zs...).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to add members to the sorted set")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("added %d out of %d members to the sorted set", n, len(members)))
+	return n, nil
+}
+
+// ZRangeByScore returns the members ordered by their scores
+func (c *Conn) ZRangeByScore(ctx context.Context, key string, opt *cache.ZRangeBy) ([]string, error) {
+	logger := c.logger.With(log.Fields{
+		"key": key,
+		"min": opt.Min,
+		"max": opt.Max,
+	})
+
+	members, err := c.conn.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min:    opt.Min,
+		Max:    opt.Max,
+		Offset: opt.Offset,
+		Count:  opt.Count,
+	}).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to get members of the sorted set")
+		return nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("got %d members of the sorted set", len(members)))
+	return members, nil
+}
+
+func (c *Conn) ZRem(ctx context.Context, key string, members ...any) (int64, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	n, err := c.conn.ZRem(ctx, key, members...).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to remove members from the sorted set")
+		return 0, err
+	}
+
+	logger.Debug(fmt.Sprintf("removed %d out of %d members from the sorted set", n, len(members)))
+	return n, nil
+}
+
+// Expire returns false if the key does not exist
+func (c *Conn) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	logger := c.logger.With(log.Fields{
+		"key":        key,
+		"expiration": expiration,
+	})
+
+	ok, err := c.conn.Expire(ctx, key, expiration).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to set the key expiration")
+		return false, err
+	}
+
+	logger.Debug("set the key expiration")
+	return ok, nil
+}
+
+// TTL returns [cache.NoExpiration] if the key never expires
+// and [cache.ErrKeyDoesNotExist] if it does not exist
+func (c *Conn) TTL(ctx context.Context, key string) (time.Duration, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	ttl, err := c.conn.TTL(ctx, key).Result()
+	if err == nil && ttl == -2 {
+		err = proxerr.New(cache.ErrKeyDoesNotExist, "key does not exist")
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to get the key ttl")
+		return 0, err
+	}
+
+	logger.Debug("got the key ttl")
+	return ttl, nil
+}
+
+// Persist returns false if the key does not exist or never expires
+func (c *Conn) Persist(ctx context.Context, key string) (bool, error) {
+	logger := c.logger.With(log.Fields{"key": key})
+
+	ok, err := c.conn.Persist(ctx, key).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to remove the key expiration")
+		return false, err
+	}
+
+	logger.Debug("removed the key expiration")
+	return ok, nil
+}
+
+// ScanKeys iterates the keys with [redis.ScanIterator], which
+// runs SCAN until the cursor returned by Redis is 0
+func (c *Conn) ScanKeys(ctx context.Context, match string, count int64) cache.ScanIterator {
+	c.logger.With(log.Fields{
+		"match": match,
+		"count": count,
+	}).Debug("scanning the keys")
+
+	return c.conn.Scan(ctx, 0, match, count).Iterator()
+}
+
+func (c *Conn) SScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	c.logger.With(log.Fields{
+		"key":   key,
+		"match": match,
+		"count": count,
+	}).Debug("scanning the set")
+
+	return c.conn.SScan(ctx, key, 0, match, count).Iterator()
+}
+
+func (c *Conn) HScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	c.logger.With(log.Fields{
+		"key":   key,
+		"match": match,
+		"count": count,
+	}).Debug("scanning the hash")
+
+	return c.conn.HScan(ctx, key, 0, match, count).Iterator()
+}
+
+func (c *Conn) ZScan(ctx context.Context, key, match string, count int64) cache.ScanIterator {
+	c.logger.With(log.Fields{
+		"key":   key,
+		"match": match,
+		"count": count,
+	}).Debug("scanning the sorted set")
+
+	return c.conn.ZScan(ctx, key, 0, match, count).Iterator()
+}
+
 func (c *Conn) Begin(_ context.Context) cache.Tx {
 	return newTx(c.conn.TxPipeline(), c.logger)
 }
@@ -135,6 +445,11 @@ func (t *Tx) Exec(ctx context.Context) error {
 	}
 
 	if _, err := tx.Exec(ctx); err != nil {
+		if errors.Is(err, redis.TxFailedErr) {
+			t.logger.Debug("a watched key was modified, the commands were not executed")
+			return proxerr.New(cache.ErrTxFailed, err.Error())
+		}
+
 		t.logger.WithError(err).Error("failed to execute commands")
 		return err
 	}
@@ -171,6 +486,43 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// Watch returns the error of the function. The keys are unwatched,
+// when the function returns or the [cache.Tx] is executed
+func (c *Client) Watch(ctx context.Context, fn func(conn cache.WatchConn) error, keys ...string) error {
+	logger := c.logger.With(log.Fields{"keys": keys})
+
+	err := c.redisClient.Watch(ctx, func(tx *redis.Tx) error {
+		return fn(&watchConn{
+			Conn: newConn(tx, c.logger),
+			tx:   tx,
+		})
+	}, keys...)
+	if err != nil {
+		logger.WithError(err).Debug("failed to run a transaction watching the keys")
+		return err
+	}
+
+	logger.Debug("ran a transaction watching the keys")
+	return nil
+}
+
+type watchConn struct {
+	Conn
+	tx *redis.Tx
+}
+
+func (c *watchConn) Watch(ctx context.Context, keys ...string) error {
+	logger := c.logger.With(log.Fields{"keys": keys})
+
+	if err := c.tx.Watch(ctx, keys...).Err(); err != nil {
+		logger.WithError(err).Error("failed to watch the keys")
+		return err
+	}
+
+	logger.Debug("watched the keys")
+	return nil
+}
+
 type Config struct {
 	Host            string
 	Port            int
@@ -0,0 +1,477 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	stdslog "log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/adanyl0v/pocket-ideas/pkg/cache"
+	"github.com/adanyl0v/pocket-ideas/pkg/cache/ratelimit"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/alicebob/miniredis/v2"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	clientTestCaseCommand func(client *Client, server *miniredis.Miniredis) error
+	clientTestCaseExpect  func(client *Client, err error)
+
+	clientTestCase struct {
+		cmd clientTestCaseCommand
+		exp clientTestCaseExpect
+	}
+)
+
+func TestConn_ScanKeys(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]clientTestCase{
+		"SUCCESS iterates all the batches": {
+			cmd: func(client *Client, server *miniredis.Miniredis) error {
+				for i := range 25 {
+					require.NoError(t, server.Set(fmt.Sprintf("session:%02d", i), "a"))
+				}
+				require.NoError(t, server.Set("whitelist:a", "a"))
+				return nil
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				keys := scanAll(t, client.ScanKeys(ctx, "session:*", 10))
+				require.Len(t, keys, 25)
+				require.NotContains(t, keys, "whitelist:a")
+			},
+		},
+		"SUCCESS no keys": {
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+				require.Empty(t, scanAll(t, client.ScanKeys(ctx, "session:*", 10)))
+			},
+		},
+		"FAILED server is closed": {
+			cmd: func(_ *Client, server *miniredis.Miniredis) error {
+				server.Close()
+				return nil
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				it := client.ScanKeys(ctx, "", 0)
+				require.False(t, it.Next(ctx))
+				require.Error(t, it.Err())
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runClientTestCase(t, &tc)
+		})
+	}
+}
+
+func TestConn_KeyScans(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]clientTestCase{
+		"SUCCESS set": {
+			cmd: func(client *Client, _ *miniredis.Miniredis) error {
+				_, err := client.SAdd(ctx, "s", "a1", "a2", "b1")
+				return err
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+				require.ElementsMatch(t, []string{"a1", "a2"}, scanAll(t, client.SScan(ctx, "s", "a*", 1)))
+			},
+		},
+		"SUCCESS hash": {
+			cmd: func(client *Client, _ *miniredis.Miniredis) error {
+				_, err := client.HSet(ctx, "h", "a", 1, "b", 2)
+				return err
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"b", "2"}, scanAll(t, client.HScan(ctx, "h", "b", 0)))
+			},
+		},
+		"SUCCESS sorted set": {
+			cmd: func(client *Client, _ *miniredis.Miniredis) error {
+				_, err := client.ZAdd(ctx, "z", cache.Z{Score: 1.5, Member: "a"})
+				return err
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"a", "1.5"}, scanAll(t, client.ZScan(ctx, "z", "", 0)))
+			},
+		},
+		"FAILED wrong type": {
+			cmd: func(_ *Client, server *miniredis.Miniredis) error {
+				return server.Set("s", "a")
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				it := client.SScan(ctx, "s", "", 0)
+				require.False(t, it.Next(ctx))
+				require.Error(t, it.Err())
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runClientTestCase(t, &tc)
+		})
+	}
+}
+
+func TestConn_Commands(t *testing.T) {
+	ctx := context.Background()
+	tcs := map[string]clientTestCase{
+		"SUCCESS ttl": {
+			cmd: func(client *Client, _ *miniredis.Miniredis) error {
+				return client.Set(ctx, "a", "b", time.Minute)
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				ttl, err := client.TTL(ctx, "a")
+				require.NoError(t, err)
+				require.Equal(t, time.Minute, ttl)
+
+				ok, err := client.Persist(ctx, "a")
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				ttl, err = client.TTL(ctx, "a")
+				require.NoError(t, err)
+				require.Equal(t, cache.NoExpiration, ttl)
+
+				_, err = client.TTL(ctx, "b")
+				require.ErrorIs(t, err, cache.ErrKeyDoesNotExist)
+			},
+		},
+		"SUCCESS counters and multiple keys": {
+			cmd: func(client *Client, _ *miniredis.Miniredis) error {
+				return client.MSet(ctx, "a", 1, "b", "c")
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				n, err := client.IncrBy(ctx, "a", 2)
+				require.NoError(t, err)
+				require.Equal(t, int64(3), n)
+
+				values, err := client.MGet(ctx, "a", "b", "d")
+				require.NoError(t, err)
+				require.Equal(t, []any{"3", "c", nil}, values)
+			},
+		},
+		"SUCCESS sorted set range": {
+			cmd: func(client *Client, _ *miniredis.Miniredis) error {
+				_, err := client.ZAdd(ctx, "z", cache.Z{Score: 1, Member: "a"}, cache.Z{Score: 2, Member: "b"})
+				return err
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				members, err := client.ZRangeByScore(ctx, "z", &cache.ZRangeBy{Min: "(1", Max: "+inf"})
+				require.NoError(t, err)
+				require.Equal(t, []string{"b"}, members)
+			},
+		},
+		"SUCCESS tx": {
+			cmd: func(client *Client, _ *miniredis.Miniredis) error {
+				tx := client.Begin(ctx)
+				if _, err := tx.HSet(ctx, "h", "a", 1); err != nil {
+					return err
+				}
+				if _, err := tx.Incr(ctx, "n"); err != nil {
+					return err
+				}
+
+				return tx.Exec(ctx)
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				var a int
+				require.NoError(t, client.HGet(ctx, "h", "a", &a))
+				require.Equal(t, 1, a)
+
+				var n string
+				require.NoError(t, client.Get(ctx, "n", &n))
+				require.Equal(t, "1", n)
+			},
+		},
+		"SUCCESS compare and set": {
+			cmd: func(client *Client, _ *miniredis.Miniredis) error {
+				ok, err := client.SetNX(ctx, "a", "b", time.Minute)
+				require.True(t, ok)
+				return err
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				ok, err := client.SetNX(ctx, "a", "c", time.Minute)
+				require.NoError(t, err)
+				require.False(t, ok)
+
+				ok, err = client.CompareAndExpire(ctx, "a", "c", time.Hour)
+				require.NoError(t, err)
+				require.False(t, ok)
+
+				ok, err = client.CompareAndExpire(ctx, "a", "b", time.Hour)
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				ttl, err := client.TTL(ctx, "a")
+				require.NoError(t, err)
+				require.Equal(t, time.Hour, ttl)
+
+				ok, err = client.CompareAndDelete(ctx, "a", "c")
+				require.NoError(t, err)
+				require.False(t, ok)
+
+				ok, err = client.CompareAndDelete(ctx, "a", "b")
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				n, err := client.Exists(ctx, "a")
+				require.NoError(t, err)
+				require.Zero(t, n)
+			},
+		},
+		"FAILED field does not exist": {
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				var s string
+				require.ErrorIs(t, client.HGet(ctx, "h", "a", &s), cache.ErrKeyDoesNotExist)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runClientTestCase(t, &tc)
+		})
+	}
+}
+
+func TestClient_Watch(t *testing.T) {
+	ctx := context.Background()
+
+	// setInTx sets the key within a transaction begun on the watching connection
+	setInTx := func(conn cache.WatchConn, key, value string) error {
+		tx := conn.Begin(ctx)
+		if err := tx.Set(ctx, key, value, 0); err != nil {
+			return err
+		}
+
+		return tx.Exec(ctx)
+	}
+
+	tcs := map[string]clientTestCase{
+		"SUCCESS watched key is not modified": {
+			cmd: func(client *Client, server *miniredis.Miniredis) error {
+				require.NoError(t, server.Set("a", "b"))
+				return client.Watch(ctx, func(conn cache.WatchConn) error {
+					var s string
+					if err := conn.Get(ctx, "a", &s); err != nil {
+						return err
+					}
+
+					return setInTx(conn, "a", s+"c")
+				}, "a")
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				var s string
+				require.NoError(t, client.Get(ctx, "a", &s))
+				require.Equal(t, "bc", s)
+			},
+		},
+		"FAILED watched key is modified": {
+			cmd: func(client *Client, server *miniredis.Miniredis) error {
+				return client.Watch(ctx, func(conn cache.WatchConn) error {
+					require.NoError(t, server.Set("a", "b"))
+					return setInTx(conn, "a", "c")
+				}, "a")
+			},
+			exp: func(client *Client, err error) {
+				require.ErrorIs(t, err, cache.ErrTxFailed)
+
+				var s string
+				require.NoError(t, client.Get(ctx, "a", &s))
+				require.Equal(t, "b", s)
+			},
+		},
+		"FAILED key watched later is modified": {
+			cmd: func(client *Client, server *miniredis.Miniredis) error {
+				return client.Watch(ctx, func(conn cache.WatchConn) error {
+					if err := conn.Watch(ctx, "b"); err != nil {
+						return err
+					}
+
+					require.NoError(t, server.Set("b", "c"))
+					return setInTx(conn, "a", "c")
+				}, "a")
+			},
+			exp: func(_ *Client, err error) {
+				require.ErrorIs(t, err, cache.ErrTxFailed)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runClientTestCase(t, &tc)
+		})
+	}
+}
+
+func TestClient_RateLimits(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tcs := map[string]clientTestCase{
+		"SUCCESS sliding window log": {
+			cmd: func(client *Client, server *miniredis.Miniredis) error {
+				server.SetTime(now)
+				for range 2 {
+					rl, err := client.SlidingWindowLog(ctx, "a", 2, time.Minute)
+					require.NoError(t, err)
+					require.True(t, rl.Allowed)
+				}
+
+				server.SetTime(now.Add(20 * time.Second))
+				rl, err := client.SlidingWindowLog(ctx, "a", 2, time.Minute)
+				require.NoError(t, err)
+				require.Equal(t, ratelimit.RateLimit{
+					Limit:      2,
+					RetryAfter: 40 * time.Second,
+					ResetAfter: 40 * time.Second,
+				}, rl)
+
+				// Both requests leave the window
+				server.SetTime(now.Add(time.Minute))
+				rl, err = client.SlidingWindowLog(ctx, "a", 2, time.Minute)
+				require.Equal(t, ratelimit.RateLimit{
+					Allowed:    true,
+					Limit:      2,
+					Remaining:  1,
+					ResetAfter: time.Minute,
+				}, rl)
+				return err
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				ttl, err := client.TTL(ctx, "a")
+				require.NoError(t, err)
+				require.Equal(t, time.Minute, ttl)
+			},
+		},
+		"SUCCESS token bucket": {
+			cmd: func(client *Client, server *miniredis.Miniredis) error {
+				server.SetTime(now)
+				for i := range 2 {
+					rl, err := client.TokenBucket(ctx, "a", 2, 30*time.Second)
+					require.NoError(t, err)
+					require.Equal(t, ratelimit.RateLimit{
+						Allowed:    true,
+						Limit:      2,
+						Remaining:  int64(1 - i),
+						ResetAfter: time.Duration(i+1) * 30 * time.Second,
+					}, rl)
+				}
+
+				server.SetTime(now.Add(15 * time.Second))
+				rl, err := client.TokenBucket(ctx, "a", 2, 30*time.Second)
+				require.NoError(t, err)
+				require.Equal(t, ratelimit.RateLimit{
+					Limit:      2,
+					RetryAfter: 15 * time.Second,
+					ResetAfter: 45 * time.Second,
+				}, rl)
+
+				server.SetTime(now.Add(30 * time.Second))
+				rl, err = client.TokenBucket(ctx, "a", 2, 30*time.Second)
+				require.True(t, rl.Allowed)
+				require.Zero(t, rl.Remaining)
+				return err
+			},
+			exp: func(client *Client, err error) {
+				require.NoError(t, err)
+
+				ttl, err := client.TTL(ctx, "a")
+				require.NoError(t, err)
+				require.Equal(t, time.Minute, ttl)
+			},
+		},
+		"FAILED invalid limit": {
+			cmd: func(client *Client, _ *miniredis.Miniredis) error {
+				_, err := client.SlidingWindowLog(ctx, "a", 0, time.Minute)
+				return err
+			},
+			exp: func(_ *Client, err error) {
+				require.ErrorIs(t, err, ratelimit.ErrInvalidRateLimit)
+			},
+		},
+		"FAILED wrong type": {
+			cmd: func(client *Client, server *miniredis.Miniredis) error {
+				if err := server.Set("a", "b"); err != nil {
+					return err
+				}
+
+				_, err := client.TokenBucket(ctx, "a", 1, time.Minute)
+				return err
+			},
+			exp: func(_ *Client, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runClientTestCase(t, &tc)
+		})
+	}
+}
+
+func scanAll(t *testing.T, it cache.ScanIterator) []string {
+	var vals []string
+	for it.Next(context.Background()) {
+		vals = append(vals, it.Val())
+	}
+	require.NoError(t, it.Err())
+
+	return vals
+}
+
+// runClientTestCase should be called by [testing.T.Run]
+func runClientTestCase(t *testing.T, tc *clientTestCase) {
+	server := miniredis.RunT(t)
+	port, err := strconv.Atoi(server.Port())
+	require.NoError(t, err)
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	client, err := Connect(context.Background(), logger, &Config{
+		Host: server.Host(),
+		Port: port,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	if tc.cmd != nil {
+		err = tc.cmd(client, server)
+	}
+
+	if tc.exp != nil {
+		tc.exp(client, err)
+	}
+}
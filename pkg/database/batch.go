@@ -0,0 +1,29 @@
+package database
+
+// BatchQuery is a statement queued in the [Batch]
+type BatchQuery struct {
+	Query string
+	Args  []any
+}
+
+// Batch queues the statements, which are sent to the database in a single
+// network round trip by [Conn.SendBatch]
+type Batch struct {
+	queries []BatchQuery
+}
+
+func (b *Batch) Queue(query string, args ...any) {
+	b.queries = append(b.queries, BatchQuery{
+		Query: query,
+		Args:  args,
+	})
+}
+
+func (b *Batch) Len() int {
+	return len(b.queries)
+}
+
+// Queries returns the queued statements in the order they are sent
+func (b *Batch) Queries() []BatchQuery {
+	return b.queries
+}
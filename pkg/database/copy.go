@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// CopySource yields the rows for [Conn.CopyFrom]. Next returns false when
+// there are no more rows or an error occurred, which is returned by Err
+type CopySource interface {
+	Next() bool
+	Values() ([]any, error)
+	Err() error
+}
+
+type sliceCopySource struct {
+	rows [][]any
+	i    int
+}
+
+// CopyFromSlice returns a source, which yields the rows in order
+func CopyFromSlice(rows [][]any) CopySource {
+	return &sliceCopySource{rows: rows, i: -1}
+}
+
+func (s *sliceCopySource) Next() bool {
+	s.i++
+	return s.i < len(s.rows)
+}
+
+func (s *sliceCopySource) Values() ([]any, error) {
+	return s.rows[s.i], nil
+}
+
+func (s *sliceCopySource) Err() error {
+	return nil
+}
+
+type channelCopySource struct {
+	ctx  context.Context
+	rows <-chan []any
+	row  []any
+	err  error
+}
+
+// CopyFromChannel returns a source, which yields the rows until the channel
+// is closed. The copy is aborted with the context error, if the context
+// is done before that
+func CopyFromChannel(ctx context.Context, rows <-chan []any) CopySource {
+	return &channelCopySource{
+		ctx:  ctx,
+		rows: rows,
+	}
+}
+
+func (s *channelCopySource) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	select {
+	case <-s.ctx.Done():
+		s.err = s.ctx.Err()
+		return false
+	case row, ok := <-s.rows:
+		s.row = row
+		return ok
+	}
+}
+
+func (s *channelCopySource) Values() ([]any, error) {
+	return s.row, nil
+}
+
+func (s *channelCopySource) Err() error {
+	return s.err
+}
+
+// CSVOptions configure the source returned by [CopyFromCSV]
+type CSVOptions struct {
+	// Comma is the field delimiter. It is ',' by default
+	Comma rune
+
+	// Header skips the first record
+	Header bool
+
+	// Null is the field, which is copied as NULL. The zero value copies
+	// the empty fields as NULL, as PostgreSQL does with the unquoted ones
+	Null string
+
+	// Parse converts the record to the row values. By default the fields
+	// are copied as strings, so the columns must accept the text values
+	Parse func(record []string) ([]any, error)
+}
+
+type csvCopySource struct {
+	reader *csv.Reader
+	opts   CSVOptions
+	header bool
+	row    []any
+	err    error
+}
+
+// CopyFromCSV returns a source, which yields a row for every CSV record
+func CopyFromCSV(r io.Reader, opts *CSVOptions) CopySource {
+	s := &csvCopySource{reader: csv.NewReader(r)}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.Comma != 0 {
+		s.reader.Comma = s.opts.Comma
+	}
+	s.reader.ReuseRecord = true
+	s.header = s.opts.Header
+
+	return s
+}
+
+func (s *csvCopySource) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	record, err := s.reader.Read()
+	if err == nil && s.header {
+		s.header = false
+		record, err = s.reader.Read()
+	}
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			s.err = err
+		}
+		return false
+	}
+
+	if s.opts.Parse != nil {
+		s.row, s.err = s.opts.Parse(record)
+		return s.err == nil
+	}
+
+	s.row = make([]any, len(record))
+	for i, field := range record {
+		if field != s.opts.Null {
+			s.row[i] = field
+		}
+	}
+	return true
+}
+
+func (s *csvCopySource) Values() ([]any, error) {
+	return s.row, nil
+}
+
+func (s *csvCopySource) Err() error {
+	return s.err
+}
@@ -0,0 +1,142 @@
+package database_test
+
+import (
+	"context"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCopyFromSlice(t *testing.T) {
+	rows, err := readCopySource(database.CopyFromSlice([][]any{{1, "a"}, {2, "b"}}))
+	require.NoError(t, err)
+	require.Equal(t, [][]any{{1, "a"}, {2, "b"}}, rows)
+}
+
+func TestCopyFromChannel(t *testing.T) {
+	tcs := map[string]struct {
+		cmd func() ([][]any, error)
+		exp func(rows [][]any, err error)
+	}{
+		"SUCCESS": {
+			cmd: func() ([][]any, error) {
+				ch := make(chan []any, 2)
+				ch <- []any{1, "a"}
+				ch <- []any{2, "b"}
+				close(ch)
+
+				return readCopySource(database.CopyFromChannel(context.Background(), ch))
+			},
+			exp: func(rows [][]any, err error) {
+				require.NoError(t, err)
+				require.Equal(t, [][]any{{1, "a"}, {2, "b"}}, rows)
+			},
+		},
+		"FAILED context canceled": {
+			cmd: func() ([][]any, error) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return readCopySource(database.CopyFromChannel(ctx, make(chan []any)))
+			},
+			exp: func(rows [][]any, err error) {
+				require.ErrorIs(t, err, context.Canceled)
+				require.Empty(t, rows)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			tc.exp(tc.cmd())
+		})
+	}
+}
+
+func TestCopyFromCSV(t *testing.T) {
+	tcs := map[string]struct {
+		input string
+		opts  *database.CSVOptions
+		exp   func(rows [][]any, err error)
+	}{
+		"SUCCESS": {
+			input: "1,a\n2,\n",
+			exp: func(rows [][]any, err error) {
+				require.NoError(t, err)
+				require.Equal(t, [][]any{{"1", "a"}, {"2", nil}}, rows)
+			},
+		},
+		"SUCCESS with options": {
+			input: "id;name\n1;\\N\n2;\n",
+			opts: &database.CSVOptions{
+				Comma:  ';',
+				Header: true,
+				Null:   `\N`,
+			},
+			exp: func(rows [][]any, err error) {
+				require.NoError(t, err)
+				require.Equal(t, [][]any{{"1", nil}, {"2", ""}}, rows)
+			},
+		},
+		"SUCCESS with parse": {
+			input: "1,a\n",
+			opts: &database.CSVOptions{
+				Parse: func(record []string) ([]any, error) {
+					id, err := strconv.Atoi(record[0])
+					return []any{id, record[1]}, err
+				},
+			},
+			exp: func(rows [][]any, err error) {
+				require.NoError(t, err)
+				require.Equal(t, [][]any{{1, "a"}}, rows)
+			},
+		},
+		"FAILED parse": {
+			input: "1,a\nb,c\n",
+			opts: &database.CSVOptions{
+				Parse: func(record []string) ([]any, error) {
+					id, err := strconv.Atoi(record[0])
+					return []any{id, record[1]}, err
+				},
+			},
+			exp: func(rows [][]any, err error) {
+				require.ErrorIs(t, err, strconv.ErrSyntax)
+				require.Equal(t, [][]any{{1, "a"}}, rows)
+			},
+		},
+		"FAILED invalid csv": {
+			input: "1,a\n2,b,c\n",
+			exp: func(rows [][]any, err error) {
+				require.Error(t, err)
+				require.Len(t, rows, 1)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			tc.exp(readCopySource(database.CopyFromCSV(strings.NewReader(tc.input), tc.opts)))
+		})
+	}
+}
+
+// readCopySource reads the source the same way the drivers do
+func readCopySource(src database.CopySource) ([][]any, error) {
+	var rows [][]any
+	for src.Next() {
+		values, err := src.Values()
+		if err != nil {
+			return rows, err
+		}
+
+		rows = append(rows, append([]any(nil), values...))
+	}
+
+	if err := src.Err(); err != nil {
+		return rows, err
+	}
+
+	return rows, nil
+}
@@ -12,6 +12,11 @@ var (
 	ErrUniqueViolation     = errors.New("unique violation")
 	ErrNotNullViolation    = errors.New("not null violation")
 	ErrForeignKeyViolation = errors.New("foreign key violation")
+
+	// ErrSerializationFailure and ErrDeadlockDetected are returned when
+	// the transaction conflicts with a concurrent one. See [IsRetryable]
+	ErrSerializationFailure = errors.New("serialization failure")
+	ErrDeadlockDetected     = errors.New("deadlock detected")
 )
 
 type Result interface {
@@ -35,15 +40,47 @@ type Rows interface {
 	Close()
 }
 
+// BatchResults must be read in the order the statements were queued, calling
+// the method that matches each statement once. Every statement has its own
+// result and error. Close must always be called, and it returns the first
+// error of the statements that were not read
+type BatchResults interface {
+	Execute() (Result, error)
+	Query() (Rows, error)
+	QueryRow() Row
+	Close() error
+}
+
 type Conn interface {
 	Execute(ctx context.Context, query string, args ...any) (Result, error)
 	Query(ctx context.Context, query string, args ...any) (Rows, error)
 	QueryRow(ctx context.Context, query string, args ...any) Row
+	SendBatch(ctx context.Context, batch *Batch) BatchResults
+
+	// CopyFrom bulk loads the rows into the columns of the table and returns
+	// the number of the copied rows. The table is either its name or
+	// the schema and the name, e.g. {"public", "ideas"}, which are quoted
+	CopyFrom(ctx context.Context, table []string, columns []string, src CopySource) (int64, error)
 	Begin(ctx context.Context) (Tx, error)
 }
 
+// Tx supports nested transactions by the means of savepoints. A savepoint
+// may be rolled back to without aborting the whole transaction, which is
+// useful for the partial rollbacks within a larger unit of work
 type Tx interface {
 	Conn
 	Commit(ctx context.Context) error
 	Rollback(ctx context.Context) error
+
+	// Savepoint creates a savepoint. If the name is already used,
+	// the new savepoint hides the old one until it is released
+	Savepoint(ctx context.Context, name string) error
+
+	// RollbackTo discards the changes made after the savepoint.
+	// The savepoint remains, so it may be rolled back to again
+	RollbackTo(ctx context.Context, name string) error
+
+	// Release destroys the savepoint and the ones created after it,
+	// keeping their changes
+	Release(ctx context.Context, name string) error
 }
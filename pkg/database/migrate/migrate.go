@@ -0,0 +1,388 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"io/fs"
+	"strings"
+)
+
+var (
+	ErrDirty            = errors.New("the database is dirty")
+	ErrVersionNotFound  = errors.New("migration version not found")
+	ErrInvalidMigration = errors.New("invalid migration")
+	ErrSchemaBehind     = errors.New("the database schema is behind")
+)
+
+const (
+	// DefaultTable is compatible with the [migrate CLI], so the databases
+	// that were migrated with it can be migrated by the [Migrator] further
+	//
+	// [migrate CLI]: https://github.com/golang-migrate/migrate
+	DefaultTable = "schema_migrations"
+
+	// DefaultLockID is the key of the advisory lock, which prevents
+	// concurrent migrations by several application instances
+	DefaultLockID int64 = 0x706f636b6574 // "pocket"
+)
+
+type Config struct {
+	Table  string
+	LockID int64
+}
+
+// MigrationStatus tells whether the migration is applied
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+// Status describes the database schema. The version is 0,
+// if no migrations were applied
+type Status struct {
+	Version    uint64
+	Dirty      bool
+	Migrations []MigrationStatus
+}
+
+// Migrator applies the migrations one by one. Every migration is applied in
+// its own transaction, which holds a transaction-level advisory lock, so
+// a failed migration is rolled back and doesn't leave the database dirty
+type Migrator struct {
+	conn       database.Conn
+	logger     log.Logger
+	migrations []Migration
+	config     Config
+}
+
+// New loads the migrations with [Load]. The zero fields of the config
+// are replaced with [DefaultTable] and [DefaultLockID]
+func New(conn database.Conn, fsys fs.FS, logger log.Logger, config *Config) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		logger.WithError(err).Error("failed to load migrations")
+		return nil, err
+	}
+
+	m := &Migrator{
+		conn:       conn,
+		logger:     logger,
+		migrations: migrations,
+	}
+	if config != nil {
+		m.config = *config
+	}
+	if m.config.Table == "" {
+		m.config.Table = DefaultTable
+	}
+	if m.config.LockID == 0 {
+		m.config.LockID = DefaultLockID
+	}
+
+	logger.With(log.Fields{
+		"migrations": len(migrations),
+		"latest":     m.Latest(),
+	}).Debug("loaded migrations")
+	return m, nil
+}
+
+// Migrations returns the loaded migrations sorted by version
+func (m *Migrator) Migrations() []Migration {
+	return append([]Migration(nil), m.migrations...)
+}
+
+// Latest returns the version of the latest migration or 0 if there are none
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies all the pending migrations. It doesn't roll back
+// the database, if its version is ahead of the latest migration
+func (m *Migrator) Up(ctx context.Context) error {
+	latest := m.Latest()
+	return m.run(ctx, func(current uint64) uint64 {
+		return max(current, latest)
+	})
+}
+
+// Down rolls back the latest applied migration
+func (m *Migrator) Down(ctx context.Context) error {
+	_, err := m.step(ctx, func(current uint64) (uint64, error) {
+		if current == 0 {
+			return 0, nil
+		}
+
+		i := m.index(current)
+		if i < 0 {
+			return 0, proxerr.New(ErrVersionNotFound, fmt.Sprintf("applied version %d is unknown", current))
+		}
+		if i == 0 {
+			return 0, nil
+		}
+
+		return m.migrations[i-1].Version, nil
+	})
+	return err
+}
+
+// Goto migrates up or down to the version. The version 0
+// rolls back all the migrations
+func (m *Migrator) Goto(ctx context.Context, version uint64) error {
+	if version != 0 && m.index(version) < 0 {
+		return proxerr.New(ErrVersionNotFound, fmt.Sprintf("version %d is unknown", version))
+	}
+
+	return m.run(ctx, func(uint64) uint64 { return version })
+}
+
+// Status returns the version of the database and the applied migrations
+func (m *Migrator) Status(ctx context.Context) (Status, error) {
+	if err := m.createTable(ctx, m.conn); err != nil {
+		return Status{}, err
+	}
+
+	version, dirty, err := m.version(ctx, m.conn)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{
+		Version:    version,
+		Dirty:      dirty,
+		Migrations: make([]MigrationStatus, len(m.migrations)),
+	}
+	for i, mig := range m.migrations {
+		status.Migrations[i] = MigrationStatus{
+			Migration: mig,
+			Applied:   mig.Version <= version,
+		}
+	}
+
+	return status, nil
+}
+
+// Check returns [ErrDirty], if the database is dirty, or [ErrSchemaBehind],
+// if it lacks some of the loaded migrations. The database may be ahead
+// of the latest migration, e.g. after the application was rolled back
+func (m *Migrator) Check(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if status.Dirty {
+		return proxerr.New(ErrDirty, fmt.Sprintf("version %d is dirty, fix and force it manually", status.Version))
+	}
+
+	if latest := m.Latest(); status.Version < latest {
+		return proxerr.New(ErrSchemaBehind, fmt.Sprintf("version %d is behind the latest migration %d", status.Version, latest))
+	}
+
+	return nil
+}
+
+// run makes steps towards the target version until it is reached
+func (m *Migrator) run(ctx context.Context, target func(current uint64) uint64) error {
+	for {
+		done, err := m.step(ctx, func(current uint64) (uint64, error) {
+			return target(current), nil
+		})
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// step applies or rolls back a single migration towards the target version.
+// It returns true, if the database is already at the target version
+func (m *Migrator) step(ctx context.Context, target func(current uint64) (uint64, error)) (done bool, err error) {
+	// The migrations and the wait for the lock may take
+	// much longer than the usual queries
+	ctx = database.WithQueryTimeout(ctx, 0)
+
+	tx, err := m.conn.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			if txErr := tx.Rollback(ctx); txErr != nil {
+				err = errors.Join(err, txErr)
+			}
+		}
+	}()
+
+	if _, err = tx.Execute(ctx, "SET LOCAL statement_timeout = 0"); err != nil {
+		m.logger.WithError(err).Error("failed to disable the statement timeout")
+		return false, err
+	}
+
+	if _, err = tx.Execute(ctx, "SELECT pg_advisory_xact_lock($1)", m.config.LockID); err != nil {
+		m.logger.WithError(err).Error("failed to acquire the migrations lock")
+		return false, err
+	}
+
+	if err = m.createTable(ctx, tx); err != nil {
+		return false, err
+	}
+
+	current, dirty, err := m.version(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+	if dirty {
+		m.logger.With(log.Fields{"version": current}).Error("the database is dirty")
+		return false, proxerr.New(ErrDirty, fmt.Sprintf("version %d is dirty, fix and force it manually", current))
+	}
+
+	to, err := target(current)
+	if err != nil {
+		return false, err
+	}
+	if to == current {
+		if err = tx.Commit(ctx); err != nil {
+			return false, err
+		}
+
+		m.logger.With(log.Fields{"version": current}).Debug("the database is up to date")
+		return true, nil
+	}
+
+	mig, query, next, err := m.next(current, to)
+	if err != nil {
+		return false, err
+	}
+
+	logger := m.logger.With(log.Fields{
+		"version": mig.Version,
+		"name":    mig.Name,
+	})
+
+	if _, err = tx.Execute(ctx, query); err != nil {
+		if to > current {
+			logger.WithError(err).Error("failed to apply a migration")
+		} else {
+			logger.WithError(err).Error("failed to roll back a migration")
+		}
+		return false, err
+	}
+
+	if err = m.setVersion(ctx, tx, next); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	if to > current {
+		logger.Info("applied a migration")
+	} else {
+		logger.Info("rolled back a migration")
+	}
+	return false, nil
+}
+
+// next returns the migration to apply in order to move from the current
+// version towards the target one, its query and the resulting version
+func (m *Migrator) next(current, target uint64) (Migration, string, uint64, error) {
+	if target > current {
+		for _, mig := range m.migrations {
+			if mig.Version > current {
+				return mig, mig.Up, mig.Version, nil
+			}
+		}
+
+		return Migration{}, "", 0, proxerr.New(ErrVersionNotFound, fmt.Sprintf("no migrations after version %d", current))
+	}
+
+	i := m.index(current)
+	if i < 0 {
+		return Migration{}, "", 0, proxerr.New(ErrVersionNotFound, fmt.Sprintf("applied version %d is unknown", current))
+	}
+
+	var prev uint64
+	if i > 0 {
+		prev = m.migrations[i-1].Version
+	}
+
+	return m.migrations[i], m.migrations[i].Down, prev, nil
+}
+
+func (m *Migrator) index(version uint64) int {
+	for i, mig := range m.migrations {
+		if mig.Version == version {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (m *Migrator) createTable(ctx context.Context, conn database.Conn) error {
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)",
+		quoteIdentifier(m.config.Table))
+	if _, err := conn.Execute(ctx, query); err != nil {
+		m.logger.WithError(err).Error("failed to create the migrations table")
+		return err
+	}
+
+	return nil
+}
+
+// version returns 0 if no migrations were applied
+func (m *Migrator) version(ctx context.Context, conn database.Conn) (uint64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+
+	query := fmt.Sprintf("SELECT version, dirty FROM %s LIMIT 1", quoteIdentifier(m.config.Table))
+	if err := conn.QueryRow(ctx, query).Scan(&version, &dirty); err != nil {
+		if errors.Is(err, database.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		m.logger.WithError(err).Error("failed to get the migrations version")
+		return 0, false, err
+	}
+
+	// The migrate CLI sets the version to -1 when everything is rolled back
+	if version < 0 {
+		return 0, dirty, nil
+	}
+
+	return uint64(version), dirty, nil
+}
+
+func (m *Migrator) setVersion(ctx context.Context, conn database.Conn, version uint64) error {
+	table := quoteIdentifier(m.config.Table)
+	if _, err := conn.Execute(ctx, fmt.Sprintf("DELETE FROM %s", table)); err != nil {
+		m.logger.WithError(err).Error("failed to reset the migrations version")
+		return err
+	}
+
+	if version == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (version, dirty) VALUES ($1, FALSE)", table)
+	if _, err := conn.Execute(ctx, query, int64(version)); err != nil {
+		m.logger.WithError(err).Error("failed to set the migrations version")
+		return err
+	}
+
+	return nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,468 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	_dbMock "github.com/adanyl0v/pocket-ideas/mocks/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"github.com/golang/mock/gomock"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	"testing"
+	"testing/fstest"
+)
+
+var testMigrations = fstest.MapFS{
+	"000001_init_users.up.sql":   {Data: []byte("CREATE TABLE users ();")},
+	"000001_init_users.down.sql": {Data: []byte("DROP TABLE users;")},
+	"000002_init_ideas.up.sql":   {Data: []byte("CREATE TABLE ideas ();")},
+	"000002_init_ideas.down.sql": {Data: []byte("DROP TABLE ideas;")},
+	"README.md":                  {Data: []byte("ignored")},
+}
+
+func TestLoad(t *testing.T) {
+	tcs := map[string]struct {
+		fsys fstest.MapFS
+		exp  func(migrations []Migration, err error)
+	}{
+		"SUCCESS": {
+			fsys: testMigrations,
+			exp: func(migrations []Migration, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []Migration{
+					{Version: 1, Name: "init_users", Up: "CREATE TABLE users ();", Down: "DROP TABLE users;"},
+					{Version: 2, Name: "init_ideas", Up: "CREATE TABLE ideas ();", Down: "DROP TABLE ideas;"},
+				}, migrations)
+			},
+		},
+		"FAILED invalid file name": {
+			fsys: fstest.MapFS{"init_users.up.sql": {Data: []byte("SELECT 1;")}},
+			exp: func(_ []Migration, err error) {
+				require.ErrorIs(t, err, ErrInvalidMigration)
+			},
+		},
+		"FAILED zero version": {
+			fsys: fstest.MapFS{
+				"000000_init.up.sql":   {Data: []byte("SELECT 1;")},
+				"000000_init.down.sql": {Data: []byte("SELECT 1;")},
+			},
+			exp: func(_ []Migration, err error) {
+				require.ErrorIs(t, err, ErrInvalidMigration)
+			},
+		},
+		"FAILED missing down file": {
+			fsys: fstest.MapFS{"000001_init_users.up.sql": {Data: []byte("SELECT 1;")}},
+			exp: func(_ []Migration, err error) {
+				require.ErrorIs(t, err, ErrInvalidMigration)
+			},
+		},
+		"FAILED empty down file": {
+			fsys: fstest.MapFS{
+				"000001_init_users.up.sql":   {Data: []byte("SELECT 1;")},
+				"000001_init_users.down.sql": {Data: []byte{}},
+			},
+			exp: func(_ []Migration, err error) {
+				require.ErrorIs(t, err, ErrInvalidMigration)
+			},
+		},
+		"FAILED duplicate version": {
+			fsys: fstest.MapFS{
+				"000001_init_users.up.sql": {Data: []byte("SELECT 1;")},
+				"000001_init_ideas.up.sql": {Data: []byte("SELECT 1;")},
+			},
+			exp: func(_ []Migration, err error) {
+				require.ErrorIs(t, err, ErrInvalidMigration)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			migrations, err := Load(tc.fsys)
+			tc.exp(migrations, err)
+		})
+	}
+}
+
+type migratorTestCase struct {
+	reg func(ctrl *gomock.Controller, conn *_dbMock.MockConn)
+	cmd func(m *Migrator) error
+	exp func(err error)
+}
+
+func TestMigrator_Up(t *testing.T) {
+	tcs := map[string]migratorTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				gomock.InOrder(
+					expectStep(ctrl, conn, 0, false, "CREATE TABLE users ();", 1),
+					expectStep(ctrl, conn, 1, false, "CREATE TABLE ideas ();", 2),
+					expectUpToDate(ctrl, conn, 2),
+				)
+			},
+			cmd: func(m *Migrator) error {
+				return m.Up(context.Background())
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS the database is ahead": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				expectUpToDate(ctrl, conn, 3)
+			},
+			cmd: func(m *Migrator) error {
+				return m.Up(context.Background())
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED dirty database": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := expectLockedVersion(ctrl, conn, 1, true)
+				tx.EXPECT().Rollback(gomock.Any()).Return(nil)
+			},
+			cmd: func(m *Migrator) error {
+				return m.Up(context.Background())
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrDirty)
+			},
+		},
+		"FAILED to apply a migration": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := expectLockedVersion(ctrl, conn, 0, false)
+				tx.EXPECT().Execute(gomock.Any(), "CREATE TABLE users ();").Return(nil, errors.New(""))
+				tx.EXPECT().Rollback(gomock.Any()).Return(nil)
+			},
+			cmd: func(m *Migrator) error {
+				return m.Up(context.Background())
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"FAILED to begin a transaction": {
+			reg: func(_ *gomock.Controller, conn *_dbMock.MockConn) {
+				conn.EXPECT().Begin(gomock.Any()).Return(nil, errors.New(""))
+			},
+			cmd: func(m *Migrator) error {
+				return m.Up(context.Background())
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runMigratorTestCase(t, &tc)
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	tcs := map[string]migratorTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				expectStep(ctrl, conn, 2, false, "DROP TABLE ideas;", 1)
+			},
+			cmd: func(m *Migrator) error {
+				return m.Down(context.Background())
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS rolls back the first migration": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				expectStep(ctrl, conn, 1, false, "DROP TABLE users;", 0)
+			},
+			cmd: func(m *Migrator) error {
+				return m.Down(context.Background())
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS nothing to roll back": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				expectUpToDate(ctrl, conn, 0)
+			},
+			cmd: func(m *Migrator) error {
+				return m.Down(context.Background())
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED unknown applied version": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := expectLockedVersion(ctrl, conn, 3, false)
+				tx.EXPECT().Rollback(gomock.Any()).Return(nil)
+			},
+			cmd: func(m *Migrator) error {
+				return m.Down(context.Background())
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrVersionNotFound)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runMigratorTestCase(t, &tc)
+		})
+	}
+}
+
+func TestMigrator_Goto(t *testing.T) {
+	tcs := map[string]migratorTestCase{
+		"SUCCESS down": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				gomock.InOrder(
+					expectStep(ctrl, conn, 2, false, "DROP TABLE ideas;", 1),
+					expectStep(ctrl, conn, 1, false, "DROP TABLE users;", 0),
+					expectUpToDate(ctrl, conn, 0),
+				)
+			},
+			cmd: func(m *Migrator) error {
+				return m.Goto(context.Background(), 0)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS up": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				gomock.InOrder(
+					expectStep(ctrl, conn, 0, false, "CREATE TABLE users ();", 1),
+					expectUpToDate(ctrl, conn, 1),
+				)
+			},
+			cmd: func(m *Migrator) error {
+				return m.Goto(context.Background(), 1)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED unknown version": {
+			cmd: func(m *Migrator) error {
+				return m.Goto(context.Background(), 3)
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrVersionNotFound)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runMigratorTestCase(t, &tc)
+		})
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	tcs := map[string]migratorTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				conn.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil, nil)
+				conn.EXPECT().QueryRow(gomock.Any(), gomock.Any()).Return(versionRow(ctrl, 1, false))
+			},
+			cmd: func(m *Migrator) error {
+				status, err := m.Status(context.Background())
+				require.Equal(t, uint64(1), status.Version)
+				require.False(t, status.Dirty)
+				require.Len(t, status.Migrations, 2)
+				require.True(t, status.Migrations[0].Applied)
+				require.False(t, status.Migrations[1].Applied)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS no migrations applied": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				row := _dbMock.NewMockRow(ctrl)
+				row.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(proxerr.New(database.ErrNoRows, ""))
+
+				conn.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil, nil)
+				conn.EXPECT().QueryRow(gomock.Any(), gomock.Any()).Return(row)
+			},
+			cmd: func(m *Migrator) error {
+				status, err := m.Status(context.Background())
+				require.Equal(t, uint64(0), status.Version)
+				require.False(t, status.Migrations[0].Applied)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED": {
+			reg: func(_ *gomock.Controller, conn *_dbMock.MockConn) {
+				conn.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil, errors.New(""))
+			},
+			cmd: func(m *Migrator) error {
+				_, err := m.Status(context.Background())
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runMigratorTestCase(t, &tc)
+		})
+	}
+}
+
+func TestMigrator_Check(t *testing.T) {
+	tcs := map[string]migratorTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				conn.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil, nil)
+				conn.EXPECT().QueryRow(gomock.Any(), gomock.Any()).Return(versionRow(ctrl, 2, false))
+			},
+			cmd: func(m *Migrator) error {
+				return m.Check(context.Background())
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS the database is ahead": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				conn.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil, nil)
+				conn.EXPECT().QueryRow(gomock.Any(), gomock.Any()).Return(versionRow(ctrl, 3, false))
+			},
+			cmd: func(m *Migrator) error {
+				return m.Check(context.Background())
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED the database is behind": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				conn.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil, nil)
+				conn.EXPECT().QueryRow(gomock.Any(), gomock.Any()).Return(versionRow(ctrl, 1, false))
+			},
+			cmd: func(m *Migrator) error {
+				return m.Check(context.Background())
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrSchemaBehind)
+			},
+		},
+		"FAILED dirty database": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				conn.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil, nil)
+				conn.EXPECT().QueryRow(gomock.Any(), gomock.Any()).Return(versionRow(ctrl, 2, true))
+			},
+			cmd: func(m *Migrator) error {
+				return m.Check(context.Background())
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrDirty)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runMigratorTestCase(t, &tc)
+		})
+	}
+}
+
+// expectLockedVersion expects a transaction to be begun and locked,
+// and the version to be read within it
+func expectLockedVersion(ctrl *gomock.Controller, conn *_dbMock.MockConn, version int64, dirty bool) *_dbMock.MockTx {
+	tx := _dbMock.NewMockTx(ctrl)
+	conn.EXPECT().Begin(gomock.Any()).Return(tx, nil)
+
+	gomock.InOrder(
+		tx.EXPECT().Execute(gomock.Any(), "SET LOCAL statement_timeout = 0").Return(nil, nil),
+		tx.EXPECT().Execute(gomock.Any(), "SELECT pg_advisory_xact_lock($1)", DefaultLockID).Return(nil, nil),
+		tx.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil, nil),
+		tx.EXPECT().QueryRow(gomock.Any(), gomock.Any()).Return(versionRow(ctrl, version, dirty)),
+	)
+	return tx
+}
+
+// expectStep expects the query to be executed and the version to be changed
+func expectStep(
+	ctrl *gomock.Controller,
+	conn *_dbMock.MockConn,
+	from int64,
+	dirty bool,
+	query string,
+	to int64,
+) *gomock.Call {
+	tx := expectLockedVersion(ctrl, conn, from, dirty)
+
+	calls := []*gomock.Call{
+		tx.EXPECT().Execute(gomock.Any(), query).Return(nil, nil),
+		tx.EXPECT().Execute(gomock.Any(), `DELETE FROM "schema_migrations"`).Return(nil, nil),
+	}
+	if to > 0 {
+		calls = append(calls, tx.EXPECT().Execute(gomock.Any(),
+			`INSERT INTO "schema_migrations" (version, dirty) VALUES ($1, FALSE)`, to).Return(nil, nil))
+	}
+
+	commit := tx.EXPECT().Commit(gomock.Any()).Return(nil)
+	gomock.InOrder(append(calls, commit)...)
+	return commit
+}
+
+// expectUpToDate expects the transaction to be committed without changes
+func expectUpToDate(ctrl *gomock.Controller, conn *_dbMock.MockConn, version int64) *gomock.Call {
+	tx := expectLockedVersion(ctrl, conn, version, false)
+	return tx.EXPECT().Commit(gomock.Any()).Return(nil)
+}
+
+func versionRow(ctrl *gomock.Controller, version int64, dirty bool) *_dbMock.MockRow {
+	row := _dbMock.NewMockRow(ctrl)
+	row.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*dest[0].(*int64) = version
+		*dest[1].(*bool) = dirty
+		return nil
+	})
+	return row
+}
+
+// runMigratorTestCase should be called by [testing.T.Run]
+func runMigratorTestCase(t *testing.T, tc *migratorTestCase) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn := _dbMock.NewMockConn(ctrl)
+	if tc.reg != nil {
+		tc.reg(ctrl, conn)
+	}
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	m, err := New(conn, testMigrations, logger, nil)
+	require.NoError(t, err)
+
+	if tc.cmd != nil {
+		err = tc.cmd(m)
+	}
+
+	if tc.exp != nil {
+		tc.exp(err)
+	}
+}
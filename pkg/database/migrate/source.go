@@ -0,0 +1,87 @@
+package migrate
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// fileNameRegexp matches the file names, such as "000001_init_users.up.sql",
+// which are compatible with the [migrate CLI]
+//
+// [migrate CLI]: https://github.com/golang-migrate/migrate
+var fileNameRegexp = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load reads the migrations from the root of the file system. Every
+// migration must have both up and down files. Files that don't end
+// with ".sql" are ignored. The migrations are sorted by version
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+
+		matches := fileNameRegexp.FindStringSubmatch(e.Name())
+		if matches == nil {
+			return nil, proxerr.New(ErrInvalidMigration, fmt.Sprintf("invalid file name %q", e.Name()))
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, proxerr.New(ErrInvalidMigration, fmt.Sprintf("invalid version in %q", e.Name()))
+		}
+
+		b, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		} else if m.Name != matches[2] {
+			return nil, proxerr.New(ErrInvalidMigration,
+				fmt.Sprintf("version %d is used by both %q and %q", version, m.Name, matches[2]))
+		}
+
+		if matches[3] == "up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, proxerr.New(ErrInvalidMigration,
+				fmt.Sprintf("migration %d_%s must have non-empty up and down files", m.Version, m.Name))
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrations, nil
+}
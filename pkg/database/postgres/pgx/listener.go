@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/internal/backoff"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+const (
+	DefaultListenerBufferSize = 64
+	DefaultListenerMinBackoff = 100 * time.Millisecond
+	DefaultListenerMaxBackoff = 30 * time.Second
+
+	listenerCloseTimeout = 5 * time.Second
+)
+
+var ErrNoListenChannels = errors.New("no channels to listen to")
+
+// DriverListenConn is a connection, which is taken out of the pool,
+// so that no one else receives its notifications
+type DriverListenConn interface {
+	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+	WaitForNotification(context.Context) (*pgconn.Notification, error)
+	Close(context.Context) error
+}
+
+type Notification struct {
+	Channel string
+	Payload string
+
+	// PID is the process ID of the server session, which sent the notification
+	PID uint32
+}
+
+type ListenerConfig struct {
+	Channels   []string
+	BufferSize int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Listener subscribes to the channels with LISTEN on a dedicated connection.
+// If the connection is lost, the listener reconnects with a backoff and
+// subscribes again. The notifications sent while it was reconnecting
+// are lost, so they must only be used as hints, e.g. for the cache
+// invalidation
+type Listener struct {
+	acquire       func(ctx context.Context) (DriverListenConn, error)
+	notifications chan Notification
+	config        ListenerConfig
+	logger        log.Logger
+}
+
+// NewListener returns a listener, which acquires its connections from
+// the client pool. The zero fields of the config are replaced with
+// the defaults
+func NewListener(client *Client, logger log.Logger, config *ListenerConfig) (*Listener, error) {
+	return newListener(func(ctx context.Context) (DriverListenConn, error) {
+		conn, err := client.Pool().Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return conn.Hijack(), nil
+	}, logger, config)
+}
+
+func newListener(
+	acquire func(ctx context.Context) (DriverListenConn, error),
+	logger log.Logger,
+	config *ListenerConfig,
+) (*Listener, error) {
+	if config == nil || len(config.Channels) == 0 {
+		return nil, ErrNoListenChannels
+	}
+
+	l := &Listener{
+		acquire: acquire,
+		config:  *config,
+		logger:  logger.With(log.Fields{"channels": config.Channels}),
+	}
+	if l.config.BufferSize <= 0 {
+		l.config.BufferSize = DefaultListenerBufferSize
+	}
+	if l.config.MinBackoff <= 0 {
+		l.config.MinBackoff = DefaultListenerMinBackoff
+	}
+	if l.config.MaxBackoff <= 0 {
+		l.config.MaxBackoff = DefaultListenerMaxBackoff
+	}
+
+	l.notifications = make(chan Notification, l.config.BufferSize)
+	return l, nil
+}
+
+// Notifications is closed when [Listener.Run] returns
+func (l *Listener) Notifications() <-chan Notification {
+	return l.notifications
+}
+
+// Run blocks until the context is done. It must be called once
+func (l *Listener) Run(ctx context.Context) error {
+	defer close(l.notifications)
+
+	attempt := 0
+	for {
+		err := l.listen(ctx, func() { attempt = 0 })
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		wait := backoff.Jittered(attempt, l.config.MinBackoff, l.config.MaxBackoff)
+		attempt++
+
+		l.logger.With(log.Fields{
+			"attempt": attempt,
+			"backoff": wait,
+		}).WithError(err).Warn("lost the listener connection, reconnecting")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// listen calls subscribed once the connection listens to all the channels
+// and delivers the notifications until an error occurs
+func (l *Listener) listen(ctx context.Context, subscribed func()) error {
+	conn, err := l.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), listenerCloseTimeout)
+		defer cancel()
+
+		if err := conn.Close(closeCtx); err != nil {
+			l.logger.WithError(err).Warn("failed to close the listener connection")
+		}
+	}()
+
+	for _, channel := range l.config.Channels {
+		if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return err
+		}
+	}
+
+	subscribed()
+	l.logger.Debug("listening to the channels")
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case l.notifications <- Notification{Channel: n.Channel, Payload: n.Payload, PID: n.PID}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Publish sends the notification with pg_notify. Within a transaction,
+// the notification is delivered only when the transaction is committed
+func Publish(ctx context.Context, conn database.Conn, channel, payload string) error {
+	_, err := conn.Execute(ctx, "SELECT pg_notify($1, $2)", channel, payload)
+	return err
+}
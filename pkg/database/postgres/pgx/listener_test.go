@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	_dbMock "github.com/adanyl0v/pocket-ideas/mocks/pkg/database"
+	_pgMock "github.com/adanyl0v/pocket-ideas/mocks/pkg/database/postgres/pgx"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	"testing"
+	"time"
+)
+
+var testListenerConfig = ListenerConfig{
+	Channels:   []string{"users", "ideas"},
+	MinBackoff: time.Millisecond,
+	MaxBackoff: time.Millisecond,
+}
+
+type (
+	listenerTestCaseRegister func(ctrl *gomock.Controller) []DriverListenConn
+	listenerTestCaseExpect   func(notifications []Notification, err error)
+
+	listenerTestCase struct {
+		reg listenerTestCaseRegister
+		exp listenerTestCaseExpect
+
+		// wait is the number of notifications to receive before canceling
+		wait int
+	}
+)
+
+func TestListener_Run(t *testing.T) {
+	tcs := map[string]listenerTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller) []DriverListenConn {
+				conn := _pgMock.NewMockDriverListenConn(ctrl)
+				gomock.InOrder(
+					conn.EXPECT().Exec(gomock.Any(), `LISTEN "users"`).Return(pgconn.CommandTag{}, nil),
+					conn.EXPECT().Exec(gomock.Any(), `LISTEN "ideas"`).Return(pgconn.CommandTag{}, nil),
+					conn.EXPECT().WaitForNotification(gomock.Any()).
+						Return(&pgconn.Notification{PID: 1, Channel: "users", Payload: "a"}, nil),
+					conn.EXPECT().WaitForNotification(gomock.Any()).
+						Return(&pgconn.Notification{PID: 2, Channel: "ideas", Payload: "b"}, nil),
+					conn.EXPECT().WaitForNotification(gomock.Any()).DoAndReturn(waitForCancel),
+					conn.EXPECT().Close(gomock.Any()).Return(nil),
+				)
+				return []DriverListenConn{conn}
+			},
+			wait: 2,
+			exp: func(notifications []Notification, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []Notification{
+					{Channel: "users", Payload: "a", PID: 1},
+					{Channel: "ideas", Payload: "b", PID: 2},
+				}, notifications)
+			},
+		},
+		"SUCCESS reconnects": {
+			reg: func(ctrl *gomock.Controller) []DriverListenConn {
+				lost := _pgMock.NewMockDriverListenConn(ctrl)
+				gomock.InOrder(
+					lost.EXPECT().Exec(gomock.Any(), gomock.Any()).Times(2).Return(pgconn.CommandTag{}, nil),
+					lost.EXPECT().WaitForNotification(gomock.Any()).
+						Return(&pgconn.Notification{Channel: "users", Payload: "a"}, nil),
+					lost.EXPECT().WaitForNotification(gomock.Any()).Return(nil, errors.New("connection reset")),
+					lost.EXPECT().Close(gomock.Any()).Return(errors.New("")),
+				)
+
+				conn := _pgMock.NewMockDriverListenConn(ctrl)
+				gomock.InOrder(
+					conn.EXPECT().Exec(gomock.Any(), gomock.Any()).Times(2).Return(pgconn.CommandTag{}, nil),
+					conn.EXPECT().WaitForNotification(gomock.Any()).
+						Return(&pgconn.Notification{Channel: "users", Payload: "b"}, nil),
+					conn.EXPECT().WaitForNotification(gomock.Any()).DoAndReturn(waitForCancel),
+					conn.EXPECT().Close(gomock.Any()).Return(nil),
+				)
+
+				// The nil connection makes the acquire fail
+				return []DriverListenConn{lost, nil, conn}
+			},
+			wait: 2,
+			exp: func(notifications []Notification, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []Notification{
+					{Channel: "users", Payload: "a"},
+					{Channel: "users", Payload: "b"},
+				}, notifications)
+			},
+		},
+		"SUCCESS resubscribes after a failed listen": {
+			reg: func(ctrl *gomock.Controller) []DriverListenConn {
+				failed := _pgMock.NewMockDriverListenConn(ctrl)
+				gomock.InOrder(
+					failed.EXPECT().Exec(gomock.Any(), `LISTEN "users"`).Return(pgconn.CommandTag{}, errors.New("")),
+					failed.EXPECT().Close(gomock.Any()).Return(nil),
+				)
+
+				conn := _pgMock.NewMockDriverListenConn(ctrl)
+				gomock.InOrder(
+					conn.EXPECT().Exec(gomock.Any(), gomock.Any()).Times(2).Return(pgconn.CommandTag{}, nil),
+					conn.EXPECT().WaitForNotification(gomock.Any()).
+						Return(&pgconn.Notification{Channel: "ideas", Payload: "a"}, nil),
+					conn.EXPECT().WaitForNotification(gomock.Any()).DoAndReturn(waitForCancel),
+					conn.EXPECT().Close(gomock.Any()).Return(nil),
+				)
+				return []DriverListenConn{failed, conn}
+			},
+			wait: 1,
+			exp: func(notifications []Notification, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []Notification{{Channel: "ideas", Payload: "a"}}, notifications)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runListenerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestNewListener(t *testing.T) {
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+
+	_, err := newListener(nil, logger, &ListenerConfig{})
+	require.ErrorIs(t, err, ErrNoListenChannels)
+
+	_, err = newListener(nil, logger, nil)
+	require.ErrorIs(t, err, ErrNoListenChannels)
+
+	l, err := newListener(nil, logger, &ListenerConfig{Channels: []string{"users"}})
+	require.NoError(t, err)
+	require.Equal(t, DefaultListenerBufferSize, cap(l.notifications))
+	require.Equal(t, DefaultListenerMinBackoff, l.config.MinBackoff)
+	require.Equal(t, DefaultListenerMaxBackoff, l.config.MaxBackoff)
+}
+
+func TestPublish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn := _dbMock.NewMockConn(ctrl)
+	conn.EXPECT().Execute(gomock.Any(), "SELECT pg_notify($1, $2)", "users", "id").Return(nil, nil)
+
+	require.NoError(t, Publish(context.Background(), conn, "users", "id"))
+}
+
+func waitForCancel(ctx context.Context) (*pgconn.Notification, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// runListenerTestCase should be called by [testing.T.Run]
+func runListenerTestCase(t *testing.T, tc *listenerTestCase) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conns := tc.reg(ctrl)
+	acquire := func(context.Context) (DriverListenConn, error) {
+		require.NotEmpty(t, conns)
+
+		conn := conns[0]
+		conns = conns[1:]
+		if conn == nil {
+			return nil, errors.New("")
+		}
+
+		return conn, nil
+	}
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	l, err := newListener(acquire, logger, &testListenerConfig)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- l.Run(ctx) }()
+
+	var notifications []Notification
+	for range tc.wait {
+		notifications = append(notifications, <-l.Notifications())
+	}
+	cancel()
+
+	err = <-errCh
+	_, ok := <-l.Notifications()
+	require.False(t, ok)
+
+	tc.exp(notifications, err)
+}
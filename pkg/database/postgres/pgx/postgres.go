@@ -12,12 +12,28 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	pgxuuid "github.com/vgarvardt/pgx-google-uuid/v5"
+	"strconv"
 	"time"
 )
 
 const DriverName = "pgx"
 
-var ErrNotTransaction = errors.New("the connection is not a transaction")
+var (
+	ErrNotTransaction            = errors.New("the connection is not a transaction")
+	ErrInvalidQueryExecMode      = errors.New("invalid query exec mode")
+	ErrIncompatibleQueryExecMode = errors.New("the query exec mode is incompatible with pgbouncer transaction pooling")
+	ErrIncompatibleTimeouts      = errors.New("the server timeouts are incompatible with pgbouncer transaction pooling")
+	ErrInvalidTableName          = errors.New("the table name must be either a table or a schema and a table")
+)
+
+// The query exec modes correspond to the [pgx.QueryExecMode] values
+const (
+	QueryExecModeCacheStatement = "cache_statement"
+	QueryExecModeCacheDescribe  = "cache_describe"
+	QueryExecModeDescribeExec   = "describe_exec"
+	QueryExecModeExec           = "exec"
+	QueryExecModeSimpleProtocol = "simple_protocol"
+)
 
 type DriverRow interface {
 	pgx.Row
@@ -27,10 +43,16 @@ type DriverRows interface {
 	pgx.Rows
 }
 
+type DriverBatchResults interface {
+	pgx.BatchResults
+}
+
 type DriverConn interface {
 	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
 	Query(context.Context, string, ...any) (pgx.Rows, error)
 	QueryRow(context.Context, string, ...any) pgx.Row
+	SendBatch(context.Context, *pgx.Batch) pgx.BatchResults
+	CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error)
 	Begin(context.Context) (pgx.Tx, error)
 }
 
@@ -40,32 +62,70 @@ type DriverTx interface {
 	Rollback(context.Context) error
 }
 
+// convertPgError maps the SQLSTATE codes to the database errors,
+// so that the callers don't depend on the driver
+func convertPgError(pgErr *pgconn.PgError) error {
+	switch pgErr.Code {
+	case pgerrcode.CheckViolation:
+		return proxerr.New(database.ErrCheckViolation, pgErr.Error())
+	case pgerrcode.UniqueViolation:
+		return proxerr.New(database.ErrUniqueViolation, pgErr.Error())
+	case pgerrcode.NotNullViolation:
+		return proxerr.New(database.ErrNotNullViolation, pgErr.Error())
+	case pgerrcode.ForeignKeyViolation:
+		return proxerr.New(database.ErrForeignKeyViolation, pgErr.Error())
+	case pgerrcode.SerializationFailure:
+		return proxerr.New(database.ErrSerializationFailure, pgErr.Error())
+	case pgerrcode.DeadlockDetected:
+		return proxerr.New(database.ErrDeadlockDetected, pgErr.Error())
+	default:
+		return pgErr
+	}
+}
+
+// convertError is the same as [convertPgError], but accepts any error
+func convertError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return convertPgError(pgErr)
+	}
+
+	return err
+}
+
+// Row and Rows cancel the query timeout, once they are read
 type Row struct {
-	row DriverRow
+	row    DriverRow
+	cancel context.CancelFunc
 }
 
-func newRow(row DriverRow) *Row {
-	return &Row{row: row}
+func newRow(row DriverRow, cancel context.CancelFunc) *Row {
+	return &Row{row: row, cancel: cancel}
 }
 
 func (r *Row) Scan(dest ...any) error {
+	if r.cancel != nil {
+		defer r.cancel()
+	}
+
 	if err := r.row.Scan(dest...); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return proxerr.New(database.ErrNoRows, err.Error())
 		}
 
-		return err
+		return convertError(err)
 	}
 
 	return nil
 }
 
 type Rows struct {
-	rows DriverRows
+	rows   DriverRows
+	cancel context.CancelFunc
 }
 
-func newRows(rows DriverRows) *Rows {
-	return &Rows{rows: rows}
+func newRows(rows DriverRows, cancel context.CancelFunc) *Rows {
+	return &Rows{rows: rows, cancel: cancel}
 }
 
 func (r *Rows) Scan(dest ...any) error {
@@ -74,18 +134,27 @@ func (r *Rows) Scan(dest ...any) error {
 			return proxerr.New(database.ErrNoRows, err.Error())
 		}
 
-		return err
+		return convertError(err)
 	}
 
 	return nil
 }
 
 func (r *Rows) Err() error {
-	return r.rows.Err()
+	if err := r.rows.Err(); err != nil {
+		return convertError(err)
+	}
+
+	return nil
 }
 
 func (r *Rows) Next() bool {
-	return r.rows.Next()
+	if r.rows.Next() {
+		return true
+	}
+
+	r.release()
+	return false
 }
 
 func (r *Rows) Values() ([]any, error) {
@@ -94,23 +163,141 @@ func (r *Rows) Values() ([]any, error) {
 
 func (r *Rows) Close() {
 	r.rows.Close()
+	r.release()
+}
+
+func (r *Rows) release() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
+type BatchResults struct {
+	results DriverBatchResults
+	queries []database.BatchQuery
+	next    int
+	cancel  context.CancelFunc
+	logger  log.Logger
+}
+
+func newBatchResults(
+	results DriverBatchResults,
+	queries []database.BatchQuery,
+	cancel context.CancelFunc,
+	logger log.Logger,
+) *BatchResults {
+	return &BatchResults{
+		results: results,
+		queries: queries,
+		cancel:  cancel,
+		logger:  logger,
+	}
+}
+
+func (r *BatchResults) Execute() (database.Result, error) {
+	logger := r.nextLogger()
+
+	tag, err := r.results.Exec()
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			err = convertPgError(pgErr)
+			logger = logger.With(log.Fields{"driverError": *pgErr})
+		}
+
+		logger.WithError(err).Error("failed sql batch query execution")
+		return nil, err
+	}
+
+	logger.Debug("executed sql batch query")
+	return tag, nil
+}
+
+func (r *BatchResults) Query() (database.Rows, error) {
+	logger := r.nextLogger()
+
+	rows, err := r.results.Query()
+	if err != nil {
+		err = convertError(err)
+		logger.WithError(err).Error("failed sql batch query execution")
+		return nil, err
+	}
+
+	logger.Debug("executed sql batch query")
+	return newRows(rows, nil), nil
+}
+
+func (r *BatchResults) QueryRow() database.Row {
+	logger := r.nextLogger()
+
+	row := r.results.QueryRow()
+
+	logger.Debug("executed sql batch query")
+	return newRow(row, nil)
+}
+
+// Close cancels the query timeout, so the rows returned
+// by the batch must be read before
+func (r *BatchResults) Close() error {
+	if r.cancel != nil {
+		defer r.cancel()
+	}
+
+	if err := r.results.Close(); err != nil {
+		err = convertError(err)
+		r.logger.WithError(err).Error("failed to close the sql batch")
+		return err
+	}
+
+	return nil
+}
+
+// nextLogger returns a logger with the query, which result is read next
+func (r *BatchResults) nextLogger() log.Logger {
+	fields := log.Fields{"batch_index": r.next}
+	if r.next < len(r.queries) {
+		fields["query"] = r.queries[r.next].Query
+	}
+
+	r.next++
+	return r.logger.With(fields)
 }
 
 type Conn struct {
-	conn   DriverConn
-	logger log.Logger
+	conn         DriverConn
+	queryTimeout time.Duration
+	logger       log.Logger
 }
 
-func newConn(conn DriverConn, logger log.Logger) Conn {
+func newConn(conn DriverConn, queryTimeout time.Duration, logger log.Logger) Conn {
 	return Conn{
-		conn:   conn,
-		logger: logger,
+		conn:         conn,
+		queryTimeout: queryTimeout,
+		logger:       logger,
 	}
 }
 
+// withQueryTimeout applies the timeout from the context or the default
+// one. The returned function must be called once the results are read
+func (c *Conn) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.queryTimeout
+	if t, ok := database.QueryTimeoutFromContext(ctx); ok {
+		timeout = t
+	}
+
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
 func (c *Conn) Execute(ctx context.Context, query string, args ...any) (database.Result, error) {
 	logger := c.logger.With(log.Fields{"query": query})
 
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+
 	now := time.Now()
 	tag, err := c.conn.Exec(ctx, query, args...)
 	t := time.Since(now)
@@ -118,17 +305,7 @@ func (c *Conn) Execute(ctx context.Context, query string, args ...any) (database
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
-			switch pgErr.Code {
-			case pgerrcode.CheckViolation:
-				err = proxerr.New(database.ErrCheckViolation, pgErr.Error())
-			case pgerrcode.UniqueViolation:
-				err = proxerr.New(database.ErrUniqueViolation, pgErr.Error())
-			case pgerrcode.NotNullViolation:
-				err = proxerr.New(database.ErrNotNullViolation, pgErr.Error())
-			case pgerrcode.ForeignKeyViolation:
-				err = proxerr.New(database.ErrForeignKeyViolation, pgErr.Error())
-			}
-
+			err = convertPgError(pgErr)
 			logger = logger.With(log.Fields{"driverError": *pgErr})
 		}
 
@@ -143,11 +320,15 @@ func (c *Conn) Execute(ctx context.Context, query string, args ...any) (database
 func (c *Conn) Query(ctx context.Context, query string, args ...any) (database.Rows, error) {
 	logger := c.logger.With(log.Fields{"query": query})
 
+	ctx, cancel := c.withQueryTimeout(ctx)
+
 	now := time.Now()
 	rows, err := c.conn.Query(ctx, query, args...)
 	t := time.Since(now)
 
 	if err != nil {
+		cancel()
+
 		if errors.Is(err, pgx.ErrNoRows) {
 			err = proxerr.New(database.ErrNoRows, err.Error())
 			logger = logger.With(log.Fields{"driverError": err})
@@ -158,18 +339,158 @@ func (c *Conn) Query(ctx context.Context, query string, args ...any) (database.R
 	}
 
 	logger.With(log.Fields{"duration": t}).Debug("executed sql")
-	return newRows(rows), err
+	return newRows(rows, cancel), err
 }
 
 func (c *Conn) QueryRow(ctx context.Context, query string, args ...any) database.Row {
 	logger := c.logger.With(log.Fields{"query": query})
 
+	ctx, cancel := c.withQueryTimeout(ctx)
+
 	now := time.Now()
 	row := c.conn.QueryRow(ctx, query, args...)
 	t := time.Since(now)
 
 	logger.With(log.Fields{"duration": t}).Debug("executed sql")
-	return newRow(row)
+	return newRow(row, cancel)
+}
+
+// SendBatch sends all the queued statements in a single network round trip.
+// The errors of the statements are reported by the results
+func (c *Conn) SendBatch(ctx context.Context, batch *database.Batch) database.BatchResults {
+	b := &pgx.Batch{}
+	for _, q := range batch.Queries() {
+		b.Queue(q.Query, q.Args...)
+	}
+
+	ctx, cancel := c.withQueryTimeout(ctx)
+
+	now := time.Now()
+	results := c.conn.SendBatch(ctx, b)
+	t := time.Since(now)
+
+	c.logger.With(log.Fields{
+		"queries":  b.Len(),
+		"duration": t,
+	}).Debug("sent an sql batch")
+	return newBatchResults(results, batch.Queries(), cancel, c.logger)
+}
+
+// CopyFrom uses the COPY protocol, which is much faster than inserts
+// when loading lots of rows. Neither the query timeout nor the statement
+// timeout of the server apply to it, because the duration depends on
+// the number of the rows. The latter is disabled with SET LOCAL, so the copy
+// runs in its own transaction or, within a transaction, in a savepoint,
+// after which the previous timeout is restored
+func (c *Conn) CopyFrom(ctx context.Context, table []string, columns []string, src database.CopySource) (int64, error) {
+	logger := c.logger.With(log.Fields{
+		"table":   table,
+		"columns": columns,
+	})
+
+	if len(table) == 0 || len(table) > 2 {
+		err := proxerr.New(ErrInvalidTableName, fmt.Sprintf("%d parts", len(table)))
+		logger.WithError(err).Error("failed sql copy")
+		return 0, err
+	}
+
+	now := time.Now()
+	n, err := c.copyFrom(ctx, table, columns, src)
+	t := time.Since(now)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			err = convertPgError(pgErr)
+			logger = logger.With(log.Fields{"driverError": *pgErr})
+		}
+
+		logger.WithError(err).Error("failed sql copy")
+		return n, err
+	}
+
+	logger.With(log.Fields{
+		"rows":     n,
+		"duration": t,
+	}).Debug("copied sql rows")
+	return n, nil
+}
+
+func (c *Conn) copyFrom(ctx context.Context, table []string, columns []string, src database.CopySource) (int64, error) {
+	if tx, ok := c.conn.(DriverTx); ok {
+		return copyFromInSavepoint(ctx, tx, table, columns, src)
+	}
+
+	tx, err := c.conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := copyFromWithoutTimeout(ctx, tx, table, columns, src)
+	if err != nil {
+		if txErr := tx.Rollback(ctx); txErr != nil {
+			err = errors.Join(err, txErr)
+		}
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// copyFromInSavepoint doesn't leak the disabled statement timeout to the rest
+// of the transaction. Rolling back to the savepoint undoes SET LOCAL, but
+// releasing it doesn't, so the previous timeout is set again before that
+func copyFromInSavepoint(
+	ctx context.Context,
+	tx DriverTx,
+	table []string,
+	columns []string,
+	src database.CopySource,
+) (int64, error) {
+	var timeout string
+	if err := tx.QueryRow(ctx, "SHOW statement_timeout").Scan(&timeout); err != nil {
+		return 0, err
+	}
+
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := copyFromWithoutTimeout(ctx, sp, table, columns, src)
+	if err == nil {
+		_, err = sp.Exec(ctx, "SELECT set_config('statement_timeout', $1, true)", timeout)
+	}
+	if err != nil {
+		if spErr := sp.Rollback(ctx); spErr != nil {
+			err = errors.Join(err, spErr)
+		}
+		return 0, err
+	}
+
+	if err = sp.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func copyFromWithoutTimeout(
+	ctx context.Context,
+	tx DriverConn,
+	table []string,
+	columns []string,
+	src database.CopySource,
+) (int64, error) {
+	if _, err := tx.Exec(ctx, "SET LOCAL statement_timeout = 0"); err != nil {
+		return 0, err
+	}
+
+	return tx.CopyFrom(ctx, table, columns, src)
 }
 
 func (c *Conn) Begin(ctx context.Context) (database.Tx, error) {
@@ -180,7 +501,7 @@ func (c *Conn) Begin(ctx context.Context) (database.Tx, error) {
 	}
 
 	c.logger.Debug("begun an sql transaction")
-	return newTx(newConn(tx, c.logger)), nil
+	return newTx(newConn(tx, c.queryTimeout, c.logger)), nil
 }
 
 type Config struct {
@@ -195,6 +516,76 @@ type Config struct {
 	MaxConnIdleTime   time.Duration
 	MaxConnLifetime   time.Duration
 	HealthCheckPeriod time.Duration
+
+	// QueryExecMode is one of the QueryExecMode constants. It is
+	// [QueryExecModeSimpleProtocol] by default
+	QueryExecMode string
+
+	// PgBouncer tells that the connections go through PgBouncer in the
+	// transaction pooling mode, which only supports the modes that neither
+	// prepare named statements nor span several round trips
+	PgBouncer bool
+
+	// ConnTimeout limits establishing a single connection
+	ConnTimeout time.Duration
+
+	// StatementTimeout and IdleInTxSessionTimeout are the session defaults
+	// enforced by the server, except for [Conn.CopyFrom], which disables
+	// the former. QueryTimeout cancels the calls on the client side and may
+	// be overridden by [database.WithQueryTimeout]. The zero values disable
+	// the timeouts.
+	//
+	// The server timeouts are sent as the startup parameters, which PgBouncer
+	// doesn't pass to the server connections shared by the clients. They must
+	// be zero with PgBouncer and set on the database role instead
+	StatementTimeout       time.Duration
+	IdleInTxSessionTimeout time.Duration
+	QueryTimeout           time.Duration
+}
+
+// parseQueryExecMode validates the mode against the PgBouncer usage
+func parseQueryExecMode(mode string, pgBouncer bool) (pgx.QueryExecMode, error) {
+	var (
+		execMode   pgx.QueryExecMode
+		compatible bool
+	)
+	switch mode {
+	case QueryExecModeCacheStatement:
+		execMode = pgx.QueryExecModeCacheStatement
+	case QueryExecModeCacheDescribe:
+		// The description is fetched on a separate round trip,
+		// when the statement is not cached yet
+		execMode = pgx.QueryExecModeCacheDescribe
+	case QueryExecModeDescribeExec:
+		execMode = pgx.QueryExecModeDescribeExec
+	case QueryExecModeExec:
+		execMode, compatible = pgx.QueryExecModeExec, true
+	case QueryExecModeSimpleProtocol, "":
+		execMode, compatible = pgx.QueryExecModeSimpleProtocol, true
+	default:
+		return 0, proxerr.New(ErrInvalidQueryExecMode, fmt.Sprintf("invalid query exec mode %q", mode))
+	}
+
+	if pgBouncer && !compatible {
+		return 0, proxerr.New(ErrIncompatibleQueryExecMode,
+			fmt.Sprintf("query exec mode %q is incompatible with pgbouncer transaction pooling", mode))
+	}
+
+	return execMode, nil
+}
+
+// validateTimeouts rejects the server timeouts, which
+// would be silently lost with PgBouncer
+func validateTimeouts(config *Config) error {
+	if !config.PgBouncer {
+		return nil
+	}
+
+	if config.StatementTimeout > 0 || config.IdleInTxSessionTimeout > 0 {
+		return ErrIncompatibleTimeouts
+	}
+
+	return nil
 }
 
 func (c *Config) URL() string {
@@ -215,30 +606,83 @@ func (c *Client) Pool() *pgxpool.Pool {
 	return c.pool
 }
 
+func (c *Client) Ping(ctx context.Context) error {
+	return c.pool.Ping(ctx)
+}
+
 func (c *Client) Close() {
 	c.pool.Close()
 	c.logger.Info("closed the postgres connection")
 }
 
+// Connect creates the client and pings the database, so it fails
+// fast, if the database is unreachable
 func Connect(ctx context.Context, logger log.Logger, config *Config) (*Client, error) {
+	client, err := New(ctx, logger, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = client.pool.Ping(ctx); err != nil {
+		logger.WithError(err).Error("failed to ping the postgres database")
+		client.pool.Close()
+		return nil, err
+	}
+
+	stat := client.pool.Stat()
+	logger.With(log.Fields{
+		"acquired_total": stat.AcquireCount(),
+		"acquired_now":   stat.AcquiredConns(),
+		"acquired_empty": stat.EmptyAcquireCount(),
+	}).Debug("pinged the postgres connection")
+
+	return client, nil
+}
+
+// New creates the client without connecting to the database, as the pool
+// connects lazily. It suits the replicas, which may be down at the start
+// and are picked up by the health checks of the [Router] later
+func New(ctx context.Context, logger log.Logger, config *Config) (*Client, error) {
+	execMode, err := parseQueryExecMode(config.QueryExecMode, config.PgBouncer)
+	if err != nil {
+		logger.WithError(err).Error("failed to parse the query exec mode")
+		return nil, err
+	}
+
+	if err = validateTimeouts(config); err != nil {
+		logger.WithError(err).Error("failed to validate the server timeouts")
+		return nil, err
+	}
+
 	pc, err := pgxpool.ParseConfig(config.URL())
 	if err != nil {
 		logger.WithError(err).Error("failed to parse the postgres connection config")
 		return nil, err
 	}
 	logger.With(log.Fields{
-		"driver":   DriverName,
-		"host":     config.Host,
-		"port":     config.Port,
-		"user":     config.User,
-		"database": config.Database,
-		"sslmode":  config.SSLMode,
+		"driver":    DriverName,
+		"host":      config.Host,
+		"port":      config.Port,
+		"user":      config.User,
+		"database":  config.Database,
+		"sslmode":   config.SSLMode,
+		"exec_mode": execMode,
+		"pgbouncer": config.PgBouncer,
 	}).Debug("parsed the postgres connection config")
 
-	// Ensure that there is no preparation and the entire request goes through in
-	// a single network call [https://habr.com/ru/companies/avito/articles/461935]
+	// The simple protocol ensures that there is no preparation and the entire request goes
+	// through in a single network call [https://habr.com/ru/companies/avito/articles/461935]
 	pc.ConnConfig.RuntimeParams = map[string]string{"standard_conforming_strings": "on"}
-	pc.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	pc.ConnConfig.DefaultQueryExecMode = execMode
+
+	pc.ConnConfig.ConnectTimeout = config.ConnTimeout
+	if config.StatementTimeout > 0 {
+		pc.ConnConfig.RuntimeParams["statement_timeout"] = formatMilliseconds(config.StatementTimeout)
+	}
+	if config.IdleInTxSessionTimeout > 0 {
+		pc.ConnConfig.RuntimeParams["idle_in_transaction_session_timeout"] =
+			formatMilliseconds(config.IdleInTxSessionTimeout)
+	}
 
 	pc.MaxConns = int32(config.MaxConns)
 	pc.MinConns = int32(config.MinConns)
@@ -263,26 +707,23 @@ func Connect(ctx context.Context, logger log.Logger, config *Config) (*Client, e
 		"max_conn_lifetime":   pc.MaxConnLifetime,
 		"max_conn_idle_time":  pc.MaxConnIdleTime,
 		"health_check_period": pc.HealthCheckPeriod,
+		"conn_timeout":        config.ConnTimeout,
+		"statement_timeout":   config.StatementTimeout,
+		"query_timeout":       config.QueryTimeout,
 	}).Debug("created a new postgres connection pool")
 
-	if err = pool.Ping(ctx); err != nil {
-		logger.WithError(err).Error("failed to ping the postgres database")
-		return nil, err
-	}
-
-	stat := pool.Stat()
-	logger.With(log.Fields{
-		"acquired_total": stat.AcquireCount(),
-		"acquired_now":   stat.AcquiredConns(),
-		"acquired_empty": stat.EmptyAcquireCount(),
-	}).Debug("pinged the postgres connection")
-
 	return &Client{
-		Conn: newConn(pool, logger),
+		Conn: newConn(pool, config.QueryTimeout, logger),
 		pool: pool,
 	}, nil
 }
 
+// formatMilliseconds formats the duration as a value of the server
+// parameters, which are measured in milliseconds
+func formatMilliseconds(d time.Duration) string {
+	return strconv.FormatInt(d.Milliseconds(), 10)
+}
+
 type Tx struct {
 	Conn
 }
@@ -294,6 +735,7 @@ func (t *Tx) Commit(ctx context.Context) error {
 	}
 
 	if err := tx.Commit(ctx); err != nil {
+		err = convertError(err)
 		t.logger.WithError(err).Error("failed to commit an sql transaction")
 		return err
 	}
@@ -317,6 +759,38 @@ func (t *Tx) Rollback(ctx context.Context) error {
 	return nil
 }
 
+func (t *Tx) Savepoint(ctx context.Context, name string) error {
+	return t.execSavepoint(ctx, "SAVEPOINT ", name, "created a savepoint", "failed to create a savepoint")
+}
+
+func (t *Tx) RollbackTo(ctx context.Context, name string) error {
+	return t.execSavepoint(ctx, "ROLLBACK TO SAVEPOINT ", name,
+		"rolled back to the savepoint", "failed to rollback to a savepoint")
+}
+
+func (t *Tx) Release(ctx context.Context, name string) error {
+	return t.execSavepoint(ctx, "RELEASE SAVEPOINT ", name, "released the savepoint", "failed to release a savepoint")
+}
+
+// execSavepoint sanitizes the savepoint name, because it is
+// an identifier and can't be passed as a query argument
+func (t *Tx) execSavepoint(ctx context.Context, command, name, okMsg, errMsg string) error {
+	tx, ok := t.conn.(DriverTx)
+	if !ok {
+		return ErrNotTransaction
+	}
+
+	logger := t.logger.With(log.Fields{"savepoint": name})
+	if _, err := tx.Exec(ctx, command+pgx.Identifier{name}.Sanitize()); err != nil {
+		err = convertError(err)
+		logger.WithError(err).Error(errMsg)
+		return err
+	}
+
+	logger.Debug(okMsg)
+	return nil
+}
+
 func newTx(conn Conn) *Tx {
 	return &Tx{
 		Conn: conn,
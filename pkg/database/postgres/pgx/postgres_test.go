@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	stdslog "log/slog"
 	"testing"
+	"time"
 )
 
 func TestRow_Scan(t *testing.T) {
@@ -239,6 +240,32 @@ func TestConn_Execute(t *testing.T) {
 				require.Equal(t, database.ErrForeignKeyViolation, err)
 			},
 		},
+		"FAILED serialization failure": {
+			reg: func(_ *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				conn.EXPECT().Exec(gomock.Any(), "").
+					Return(pgconn.CommandTag{}, &pgconn.PgError{Code: pgerrcode.SerializationFailure})
+			},
+			cmd: func(conn *Conn) error {
+				_, err := conn.Execute(context.Background(), "")
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, database.ErrSerializationFailure, err)
+			},
+		},
+		"FAILED deadlock detected": {
+			reg: func(_ *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				conn.EXPECT().Exec(gomock.Any(), "").
+					Return(pgconn.CommandTag{}, &pgconn.PgError{Code: pgerrcode.DeadlockDetected})
+			},
+			cmd: func(conn *Conn) error {
+				_, err := conn.Execute(context.Background(), "")
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, database.ErrDeadlockDetected, err)
+			},
+		},
 		"FAILED": {
 			reg: func(_ *gomock.Controller, conn *_pgMock.MockDriverConn) {
 				conn.EXPECT().Exec(gomock.Any(), "").
@@ -366,6 +393,359 @@ func TestConn_Begin(t *testing.T) {
 	}
 }
 
+func TestConn_SendBatch(t *testing.T) {
+	tcs := map[string]connTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				results := _pgMock.NewMockDriverBatchResults(ctrl)
+				conn.EXPECT().SendBatch(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, b *pgx.Batch) pgx.BatchResults {
+						require.Equal(t, 3, b.Len())
+						require.Equal(t, "INSERT", b.QueuedQueries[0].SQL)
+						require.Equal(t, []any{1}, b.QueuedQueries[0].Arguments)
+						return results
+					})
+
+				gomock.InOrder(
+					results.EXPECT().Exec().Return(pgconn.NewCommandTag("INSERT 0 1"), nil),
+					results.EXPECT().Exec().Return(pgconn.CommandTag{},
+						&pgconn.PgError{Code: pgerrcode.UniqueViolation}),
+					results.EXPECT().Query().Return(nil, nil),
+					results.EXPECT().Close().Return(nil),
+				)
+			},
+			cmd: func(conn *Conn) error {
+				batch := &database.Batch{}
+				batch.Queue("INSERT", 1)
+				batch.Queue("INSERT", 2)
+				batch.Queue("SELECT")
+
+				results := conn.SendBatch(context.Background(), batch)
+
+				res, err := results.Execute()
+				require.NoError(t, err)
+				require.Equal(t, int64(1), res.RowsAffected())
+
+				_, err = results.Execute()
+				require.ErrorIs(t, err, database.ErrUniqueViolation)
+
+				_, err = results.Query()
+				require.NoError(t, err)
+
+				return results.Close()
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED query": {
+			reg: func(ctrl *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				results := _pgMock.NewMockDriverBatchResults(ctrl)
+				conn.EXPECT().SendBatch(gomock.Any(), gomock.Any()).Return(results)
+
+				gomock.InOrder(
+					results.EXPECT().Query().Return(nil, &pgconn.PgError{Code: pgerrcode.SerializationFailure}),
+					results.EXPECT().Close().Return(nil),
+				)
+			},
+			cmd: func(conn *Conn) error {
+				batch := &database.Batch{}
+				batch.Queue("SELECT")
+
+				results := conn.SendBatch(context.Background(), batch)
+				defer func() { _ = results.Close() }()
+
+				_, err := results.Query()
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, database.ErrSerializationFailure, err)
+			},
+		},
+		"FAILED close": {
+			reg: func(ctrl *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				results := _pgMock.NewMockDriverBatchResults(ctrl)
+				conn.EXPECT().SendBatch(gomock.Any(), gomock.Any()).Return(results)
+				results.EXPECT().Close().Return(&pgconn.PgError{Code: pgerrcode.ForeignKeyViolation})
+			},
+			cmd: func(conn *Conn) error {
+				batch := &database.Batch{}
+				batch.Queue("INSERT")
+
+				return conn.SendBatch(context.Background(), batch).Close()
+			},
+			exp: func(err error) {
+				require.Equal(t, database.ErrForeignKeyViolation, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runConnTestCase(t, &tc)
+		})
+	}
+}
+
+func TestConn_CopyFrom(t *testing.T) {
+	var inTx *_pgMock.MockDriverTx
+
+	tcs := map[string]connTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				tx := _pgMock.NewMockDriverTx(ctrl)
+				gomock.InOrder(
+					conn.EXPECT().Begin(gomock.Any()).Return(testDriverTx{tx}, nil),
+					tx.EXPECT().Exec(gomock.Any(), "SET LOCAL statement_timeout = 0").Return(pgconn.CommandTag{}, nil),
+					tx.EXPECT().CopyFrom(gomock.Any(), pgx.Identifier{"public", "ideas"}, []string{"id", "title"}, gomock.Any()).
+						Return(int64(2), nil),
+					tx.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(conn *Conn) error {
+				src := database.CopyFromSlice([][]any{{1, "a"}, {2, "b"}})
+				n, err := conn.CopyFrom(context.Background(), []string{"public", "ideas"}, []string{"id", "title"}, src)
+				require.Equal(t, int64(2), n)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS within a transaction": {
+			reg: func(ctrl *gomock.Controller, _ *_pgMock.MockDriverConn) {
+				inTx = _pgMock.NewMockDriverTx(ctrl)
+				sp := _pgMock.NewMockDriverTx(ctrl)
+				row := _pgMock.NewMockDriverRow(ctrl)
+				gomock.InOrder(
+					inTx.EXPECT().QueryRow(gomock.Any(), "SHOW statement_timeout").Return(row),
+					row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...any) error {
+						*dest[0].(*string) = "5s"
+						return nil
+					}),
+					inTx.EXPECT().Begin(gomock.Any()).Return(testDriverTx{sp}, nil),
+					sp.EXPECT().Exec(gomock.Any(), "SET LOCAL statement_timeout = 0").Return(pgconn.CommandTag{}, nil),
+					sp.EXPECT().CopyFrom(gomock.Any(), pgx.Identifier{"ideas"}, []string{"id"}, gomock.Any()).
+						Return(int64(1), nil),
+					sp.EXPECT().Exec(gomock.Any(), "SELECT set_config('statement_timeout', $1, true)", "5s").
+						Return(pgconn.CommandTag{}, nil),
+					sp.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(conn *Conn) error {
+				// Only the savepoint is released, the transaction remains
+				tx := &Conn{conn: inTx, logger: conn.logger}
+				_, err := tx.CopyFrom(context.Background(), []string{"ideas"}, []string{"id"}, database.CopyFromSlice([][]any{{1}}))
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED within a transaction": {
+			reg: func(ctrl *gomock.Controller, _ *_pgMock.MockDriverConn) {
+				inTx = _pgMock.NewMockDriverTx(ctrl)
+				sp := _pgMock.NewMockDriverTx(ctrl)
+				row := _pgMock.NewMockDriverRow(ctrl)
+				gomock.InOrder(
+					inTx.EXPECT().QueryRow(gomock.Any(), "SHOW statement_timeout").Return(row),
+					row.EXPECT().Scan(gomock.Any()).Return(nil),
+					inTx.EXPECT().Begin(gomock.Any()).Return(testDriverTx{sp}, nil),
+					sp.EXPECT().Exec(gomock.Any(), "SET LOCAL statement_timeout = 0").Return(pgconn.CommandTag{}, nil),
+					sp.EXPECT().CopyFrom(gomock.Any(), pgx.Identifier{"ideas"}, []string{"id"}, gomock.Any()).
+						Return(int64(0), &pgconn.PgError{Code: pgerrcode.UniqueViolation}),
+					// Rolling back to the savepoint restores the timeout
+					sp.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(conn *Conn) error {
+				tx := &Conn{conn: inTx, logger: conn.logger}
+				_, err := tx.CopyFrom(context.Background(), []string{"ideas"}, []string{"id"}, database.CopyFromSlice([][]any{{1}}))
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, database.ErrUniqueViolation, err)
+			},
+		},
+		"FAILED invalid table name": {
+			reg: func(_ *gomock.Controller, _ *_pgMock.MockDriverConn) {},
+			cmd: func(conn *Conn) error {
+				_, err := conn.CopyFrom(context.Background(), []string{"a", "b", "c"}, []string{"id"}, database.CopyFromSlice(nil))
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrInvalidTableName)
+			},
+		},
+		"FAILED unique violation": {
+			reg: func(ctrl *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				tx := _pgMock.NewMockDriverTx(ctrl)
+				gomock.InOrder(
+					conn.EXPECT().Begin(gomock.Any()).Return(testDriverTx{tx}, nil),
+					tx.EXPECT().Exec(gomock.Any(), "SET LOCAL statement_timeout = 0").Return(pgconn.CommandTag{}, nil),
+					tx.EXPECT().CopyFrom(gomock.Any(), pgx.Identifier{"ideas"}, gomock.Any(), gomock.Any()).
+						Return(int64(0), &pgconn.PgError{Code: pgerrcode.UniqueViolation}),
+					tx.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(conn *Conn) error {
+				_, err := conn.CopyFrom(context.Background(), []string{"ideas"}, []string{"id"}, database.CopyFromSlice(nil))
+				return err
+			},
+			exp: func(err error) {
+				require.Equal(t, database.ErrUniqueViolation, err)
+			},
+		},
+		"FAILED to disable the statement timeout": {
+			reg: func(ctrl *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				tx := _pgMock.NewMockDriverTx(ctrl)
+				gomock.InOrder(
+					conn.EXPECT().Begin(gomock.Any()).Return(testDriverTx{tx}, nil),
+					tx.EXPECT().Exec(gomock.Any(), "SET LOCAL statement_timeout = 0").
+						Return(pgconn.CommandTag{}, errors.New("")),
+					tx.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(conn *Conn) error {
+				_, err := conn.CopyFrom(context.Background(), []string{"ideas"}, []string{"id"}, database.CopyFromSlice(nil))
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"FAILED to begin a transaction": {
+			reg: func(_ *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				conn.EXPECT().Begin(gomock.Any()).Return(nil, errors.New(""))
+			},
+			cmd: func(conn *Conn) error {
+				_, err := conn.CopyFrom(context.Background(), []string{"ideas"}, []string{"id"}, database.CopyFromSlice(nil))
+				return err
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runConnTestCase(t, &tc)
+		})
+	}
+}
+
+// testDriverTx completes [_pgMock.MockDriverTx] to a [pgx.Tx],
+// which is returned by [DriverConn.Begin]
+type testDriverTx struct {
+	*_pgMock.MockDriverTx
+}
+
+func (testDriverTx) LargeObjects() pgx.LargeObjects {
+	return pgx.LargeObjects{}
+}
+
+func (testDriverTx) Prepare(context.Context, string, string) (*pgconn.StatementDescription, error) {
+	return nil, nil
+}
+
+func (testDriverTx) Conn() *pgx.Conn {
+	return nil
+}
+
+func TestConn_QueryTimeout(t *testing.T) {
+	var queryCtx context.Context
+
+	tcs := map[string]connTestCase{
+		"SUCCESS default timeout": {
+			reg: func(_ *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				conn.EXPECT().Exec(gomock.Any(), "").DoAndReturn(
+					func(ctx context.Context, _ string, _ ...any) (pgconn.CommandTag, error) {
+						deadline, ok := ctx.Deadline()
+						require.True(t, ok)
+						require.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+						return pgconn.CommandTag{}, nil
+					})
+			},
+			cmd: func(conn *Conn) error {
+				conn.queryTimeout = time.Minute
+				_, err := conn.Execute(context.Background(), "")
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS disabled timeout": {
+			reg: func(_ *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				conn.EXPECT().Exec(gomock.Any(), "").DoAndReturn(
+					func(ctx context.Context, _ string, _ ...any) (pgconn.CommandTag, error) {
+						_, ok := ctx.Deadline()
+						require.False(t, ok)
+						return pgconn.CommandTag{}, nil
+					})
+			},
+			cmd: func(conn *Conn) error {
+				conn.queryTimeout = time.Minute
+				_, err := conn.Execute(database.WithQueryTimeout(context.Background(), 0), "")
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS rows cancel the timeout on close": {
+			reg: func(ctrl *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				rows := _pgMock.NewMockDriverRows(ctrl)
+				rows.EXPECT().Close()
+
+				conn.EXPECT().Query(gomock.Any(), "").DoAndReturn(
+					func(ctx context.Context, _ string, _ ...any) (pgx.Rows, error) {
+						queryCtx = ctx
+						return rows, nil
+					})
+			},
+			cmd: func(conn *Conn) error {
+				conn.queryTimeout = time.Minute
+				rows, err := conn.Query(context.Background(), "")
+				if err != nil {
+					return err
+				}
+
+				require.NoError(t, queryCtx.Err())
+				rows.Close()
+				return queryCtx.Err()
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, context.Canceled)
+			},
+		},
+		"FAILED deadline exceeded": {
+			reg: func(_ *gomock.Controller, conn *_pgMock.MockDriverConn) {
+				conn.EXPECT().Exec(gomock.Any(), "").DoAndReturn(
+					func(ctx context.Context, _ string, _ ...any) (pgconn.CommandTag, error) {
+						<-ctx.Done()
+						return pgconn.CommandTag{}, ctx.Err()
+					})
+			},
+			cmd: func(conn *Conn) error {
+				conn.queryTimeout = time.Hour
+				_, err := conn.Execute(database.WithQueryTimeout(context.Background(), time.Millisecond), "")
+				return err
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runConnTestCase(t, &tc)
+		})
+	}
+}
+
 func runConnTestCase(t *testing.T, tc *connTestCase) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -429,6 +809,17 @@ func TestTx_Commit(t *testing.T) {
 				require.Equal(t, ErrNotTransaction, err)
 			},
 		},
+		"FAILED serialization failure": {
+			reg: func(_ *gomock.Controller, tx *_pgMock.MockDriverTx) {
+				tx.EXPECT().Commit(gomock.Any()).Return(&pgconn.PgError{Code: pgerrcode.SerializationFailure})
+			},
+			cmd: func(conn *Tx) error {
+				return conn.Commit(context.Background())
+			},
+			exp: func(err error) {
+				require.Equal(t, database.ErrSerializationFailure, err)
+			},
+		},
 		"FAILED": {
 			reg: func(_ *gomock.Controller, tx *_pgMock.MockDriverTx) {
 				tx.EXPECT().Commit(gomock.Any()).Return(errors.New(""))
@@ -491,6 +882,169 @@ func TestTx_Rollback(t *testing.T) {
 	}
 }
 
+func TestTx_Savepoint(t *testing.T) {
+	tcs := map[string]txTestCase{
+		"SUCCESS partial rollback": {
+			reg: func(_ *gomock.Controller, tx *_pgMock.MockDriverTx) {
+				gomock.InOrder(
+					tx.EXPECT().Exec(gomock.Any(), "INSERT users").Return(pgconn.CommandTag{}, nil),
+					tx.EXPECT().Exec(gomock.Any(), `SAVEPOINT "ideas"`).Return(pgconn.CommandTag{}, nil),
+					tx.EXPECT().Exec(gomock.Any(), "INSERT ideas").
+						Return(pgconn.CommandTag{}, &pgconn.PgError{Code: pgerrcode.UniqueViolation}),
+					tx.EXPECT().Exec(gomock.Any(), `ROLLBACK TO SAVEPOINT "ideas"`).Return(pgconn.CommandTag{}, nil),
+					tx.EXPECT().Exec(gomock.Any(), `RELEASE SAVEPOINT "ideas"`).Return(pgconn.CommandTag{}, nil),
+					tx.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(conn *Tx) error {
+				ctx := context.Background()
+				if _, err := conn.Execute(ctx, "INSERT users"); err != nil {
+					return err
+				}
+
+				if err := conn.Savepoint(ctx, "ideas"); err != nil {
+					return err
+				}
+
+				_, err := conn.Execute(ctx, "INSERT ideas")
+				require.ErrorIs(t, err, database.ErrUniqueViolation)
+
+				if err = conn.RollbackTo(ctx, "ideas"); err != nil {
+					return err
+				}
+				if err = conn.Release(ctx, "ideas"); err != nil {
+					return err
+				}
+
+				return conn.Commit(ctx)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS sanitizes the name": {
+			reg: func(_ *gomock.Controller, tx *_pgMock.MockDriverTx) {
+				tx.EXPECT().Exec(gomock.Any(), `SAVEPOINT "a""; DROP TABLE users; --"`).Return(pgconn.CommandTag{}, nil)
+			},
+			cmd: func(conn *Tx) error {
+				return conn.Savepoint(context.Background(), `a"; DROP TABLE users; --`)
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED not a transaction": {
+			cmd: func(conn *Tx) error {
+				conn.conn = nil
+				return conn.Savepoint(context.Background(), "ideas")
+			},
+			exp: func(err error) {
+				require.Equal(t, ErrNotTransaction, err)
+			},
+		},
+		"FAILED rollback to an unknown savepoint": {
+			reg: func(_ *gomock.Controller, tx *_pgMock.MockDriverTx) {
+				tx.EXPECT().Exec(gomock.Any(), `ROLLBACK TO SAVEPOINT "ideas"`).
+					Return(pgconn.CommandTag{}, &pgconn.PgError{Code: pgerrcode.InvalidSavepointSpecification})
+			},
+			cmd: func(conn *Tx) error {
+				return conn.RollbackTo(context.Background(), "ideas")
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"FAILED release": {
+			reg: func(_ *gomock.Controller, tx *_pgMock.MockDriverTx) {
+				tx.EXPECT().Exec(gomock.Any(), `RELEASE SAVEPOINT "ideas"`).Return(pgconn.CommandTag{}, errors.New(""))
+			},
+			cmd: func(conn *Tx) error {
+				return conn.Release(context.Background(), "ideas")
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runTxTestCase(t, &tc)
+		})
+	}
+}
+
+func TestParseQueryExecMode(t *testing.T) {
+	tcs := map[string]struct {
+		mode      string
+		pgBouncer bool
+		exp       func(mode pgx.QueryExecMode, err error)
+	}{
+		"SUCCESS default": {
+			exp: func(mode pgx.QueryExecMode, err error) {
+				require.NoError(t, err)
+				require.Equal(t, pgx.QueryExecModeSimpleProtocol, mode)
+			},
+		},
+		"SUCCESS cache statement": {
+			mode: QueryExecModeCacheStatement,
+			exp: func(mode pgx.QueryExecMode, err error) {
+				require.NoError(t, err)
+				require.Equal(t, pgx.QueryExecModeCacheStatement, mode)
+			},
+		},
+		"SUCCESS exec with pgbouncer": {
+			mode:      QueryExecModeExec,
+			pgBouncer: true,
+			exp: func(mode pgx.QueryExecMode, err error) {
+				require.NoError(t, err)
+				require.Equal(t, pgx.QueryExecModeExec, mode)
+			},
+		},
+		"SUCCESS simple protocol with pgbouncer": {
+			mode:      QueryExecModeSimpleProtocol,
+			pgBouncer: true,
+			exp: func(mode pgx.QueryExecMode, err error) {
+				require.NoError(t, err)
+				require.Equal(t, pgx.QueryExecModeSimpleProtocol, mode)
+			},
+		},
+		"FAILED invalid mode": {
+			mode: "prepare",
+			exp: func(_ pgx.QueryExecMode, err error) {
+				require.ErrorIs(t, err, ErrInvalidQueryExecMode)
+			},
+		},
+		"FAILED cache statement with pgbouncer": {
+			mode:      QueryExecModeCacheStatement,
+			pgBouncer: true,
+			exp: func(_ pgx.QueryExecMode, err error) {
+				require.ErrorIs(t, err, ErrIncompatibleQueryExecMode)
+			},
+		},
+		"FAILED cache describe with pgbouncer": {
+			mode:      QueryExecModeCacheDescribe,
+			pgBouncer: true,
+			exp: func(_ pgx.QueryExecMode, err error) {
+				require.ErrorIs(t, err, ErrIncompatibleQueryExecMode)
+			},
+		},
+		"FAILED describe exec with pgbouncer": {
+			mode:      QueryExecModeDescribeExec,
+			pgBouncer: true,
+			exp: func(_ pgx.QueryExecMode, err error) {
+				require.ErrorIs(t, err, ErrIncompatibleQueryExecMode)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			tc.exp(parseQueryExecMode(tc.mode, tc.pgBouncer))
+		})
+	}
+}
+
 func runTxTestCase(t *testing.T, tc *txTestCase) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -521,3 +1075,41 @@ func runTxTestCase(t *testing.T, tc *txTestCase) {
 		tc.exp(err)
 	}
 }
+
+func TestValidateTimeouts(t *testing.T) {
+	tcs := map[string]struct {
+		config Config
+		exp    func(err error)
+	}{
+		"SUCCESS timeouts without pgbouncer": {
+			config: Config{StatementTimeout: time.Second, IdleInTxSessionTimeout: time.Minute},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS no timeouts with pgbouncer": {
+			config: Config{PgBouncer: true, QueryTimeout: time.Second},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED statement timeout with pgbouncer": {
+			config: Config{PgBouncer: true, StatementTimeout: time.Second},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrIncompatibleTimeouts)
+			},
+		},
+		"FAILED idle in transaction timeout with pgbouncer": {
+			config: Config{PgBouncer: true, IdleInTxSessionTimeout: time.Minute},
+			exp: func(err error) {
+				require.ErrorIs(t, err, ErrIncompatibleTimeouts)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			tc.exp(validateTimeouts(&tc.config))
+		})
+	}
+}
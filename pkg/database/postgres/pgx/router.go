@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultRouterHealthCheckPeriod = 10 * time.Second
+
+// RouterConn is a connection pool, which the [Router] routes the queries to
+type RouterConn interface {
+	database.Conn
+	Ping(ctx context.Context) error
+	Close()
+}
+
+type primaryContextKey struct{}
+
+// WithPrimary makes the [Router] send the reads to the primary, e.g.
+// when the caller must read its own writes, which may have not reached
+// the replicas yet
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryContextKey{}).(bool)
+	return primary
+}
+
+type RouterConfig struct {
+	HealthCheckPeriod time.Duration
+}
+
+type routerReplica struct {
+	conn    RouterConn
+	healthy atomic.Bool
+}
+
+// Router sends Query and QueryRow to the healthy replicas in round robin,
+// and everything else, including the transactions, to the primary. If all
+// the replicas are unhealthy, the reads go to the primary as well
+type Router struct {
+	primary  RouterConn
+	replicas []*routerReplica
+	next     atomic.Uint64
+	config   RouterConfig
+	logger   log.Logger
+}
+
+// NewRouter doesn't block on the replicas, which are assumed healthy
+// until [Router.Run] checks them. A replica, which is down at the start,
+// may thus fail a few reads before the first health check
+func NewRouter(primary RouterConn, replicas []RouterConn, logger log.Logger, config *RouterConfig) *Router {
+	r := &Router{
+		primary:  primary,
+		replicas: make([]*routerReplica, len(replicas)),
+		logger:   logger,
+	}
+	if config != nil {
+		r.config = *config
+	}
+	if r.config.HealthCheckPeriod <= 0 {
+		r.config.HealthCheckPeriod = DefaultRouterHealthCheckPeriod
+	}
+
+	for i, conn := range replicas {
+		r.replicas[i] = &routerReplica{conn: conn}
+		r.replicas[i].healthy.Store(true)
+	}
+
+	return r
+}
+
+// Primary returns the connection, which must be used by the callers
+// that write, such as migrations
+func (r *Router) Primary() RouterConn {
+	return r.primary
+}
+
+func (r *Router) Execute(ctx context.Context, query string, args ...any) (database.Result, error) {
+	return r.primary.Execute(ctx, query, args...)
+}
+
+func (r *Router) Query(ctx context.Context, query string, args ...any) (database.Rows, error) {
+	return r.read(ctx).Query(ctx, query, args...)
+}
+
+func (r *Router) QueryRow(ctx context.Context, query string, args ...any) database.Row {
+	return r.read(ctx).QueryRow(ctx, query, args...)
+}
+
+func (r *Router) SendBatch(ctx context.Context, batch *database.Batch) database.BatchResults {
+	return r.primary.SendBatch(ctx, batch)
+}
+
+func (r *Router) CopyFrom(ctx context.Context, table []string, columns []string, src database.CopySource) (int64, error) {
+	return r.primary.CopyFrom(ctx, table, columns, src)
+}
+
+func (r *Router) Begin(ctx context.Context) (database.Tx, error) {
+	return r.primary.Begin(ctx)
+}
+
+// Run checks the health of the replicas right away and then periodically.
+// It blocks until the context is done
+func (r *Router) Run(ctx context.Context) error {
+	if len(r.replicas) == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	r.checkHealth(ctx)
+	ticker := time.NewTicker(r.config.HealthCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.checkHealth(ctx)
+		}
+	}
+}
+
+// Close closes the replicas and the primary
+func (r *Router) Close() {
+	for _, replica := range r.replicas {
+		replica.conn.Close()
+	}
+
+	r.primary.Close()
+}
+
+// checkHealth pings the replicas concurrently, so that a replica, which
+// doesn't respond, delays the check by a single timeout at most
+func (r *Router) checkHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i, replica := range r.replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.checkReplicaHealth(ctx, i, replica)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (r *Router) checkReplicaHealth(ctx context.Context, i int, replica *routerReplica) {
+	pingCtx, cancel := context.WithTimeout(ctx, r.config.HealthCheckPeriod)
+	err := replica.conn.Ping(pingCtx)
+	cancel()
+
+	healthy := err == nil
+	if replica.healthy.Swap(healthy) == healthy {
+		return
+	}
+
+	logger := r.logger.With(log.Fields{"replica": i})
+	if healthy {
+		logger.Info("the postgres replica is healthy again")
+	} else {
+		logger.WithError(err).Warn("the postgres replica is unhealthy")
+	}
+}
+
+// read returns the next healthy replica or the primary
+func (r *Router) read(ctx context.Context) database.Conn {
+	if len(r.replicas) == 0 || usePrimary(ctx) {
+		return r.primary
+	}
+
+	start := r.next.Add(1) - 1
+	for i := range uint64(len(r.replicas)) {
+		replica := r.replicas[(start+i)%uint64(len(r.replicas))]
+		if replica.healthy.Load() {
+			return replica.conn
+		}
+	}
+
+	return r.primary
+}
@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	_pgMock "github.com/adanyl0v/pocket-ideas/mocks/pkg/database/postgres/pgx"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/golang/mock/gomock"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	"testing"
+)
+
+type (
+	routerTestCaseRegister func(primary *_pgMock.MockRouterConn, replicas []*_pgMock.MockRouterConn)
+	routerTestCaseCommand  func(router *Router) error
+	routerTestCaseExpect   func(err error)
+
+	routerTestCase struct {
+		reg routerTestCaseRegister
+		cmd routerTestCaseCommand
+		exp routerTestCaseExpect
+	}
+)
+
+func TestRouter_Query(t *testing.T) {
+	tcs := map[string]routerTestCase{
+		"SUCCESS round robin": {
+			reg: func(primary *_pgMock.MockRouterConn, replicas []*_pgMock.MockRouterConn) {
+				gomock.InOrder(
+					replicas[0].EXPECT().Query(gomock.Any(), "SELECT").Return(nil, nil),
+					replicas[1].EXPECT().QueryRow(gomock.Any(), "SELECT").Return(nil),
+					replicas[0].EXPECT().Query(gomock.Any(), "SELECT").Return(nil, nil),
+				)
+			},
+			cmd: func(router *Router) error {
+				ctx := context.Background()
+				if _, err := router.Query(ctx, "SELECT"); err != nil {
+					return err
+				}
+
+				router.QueryRow(ctx, "SELECT")
+				_, err := router.Query(ctx, "SELECT")
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS forced primary": {
+			reg: func(primary *_pgMock.MockRouterConn, _ []*_pgMock.MockRouterConn) {
+				primary.EXPECT().Query(gomock.Any(), "SELECT").Return(nil, nil)
+				primary.EXPECT().QueryRow(gomock.Any(), "SELECT").Return(nil)
+			},
+			cmd: func(router *Router) error {
+				ctx := WithPrimary(context.Background())
+				router.QueryRow(ctx, "SELECT")
+
+				_, err := router.Query(ctx, "SELECT")
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS skips unhealthy replicas": {
+			reg: func(primary *_pgMock.MockRouterConn, replicas []*_pgMock.MockRouterConn) {
+				replicas[0].EXPECT().Ping(gomock.Any()).Return(errors.New(""))
+				replicas[1].EXPECT().Ping(gomock.Any()).Return(nil)
+				replicas[1].EXPECT().Query(gomock.Any(), "SELECT").Times(2).Return(nil, nil)
+			},
+			cmd: func(router *Router) error {
+				router.checkHealth(context.Background())
+
+				for range 2 {
+					if _, err := router.Query(context.Background(), "SELECT"); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS all replicas are unhealthy": {
+			reg: func(primary *_pgMock.MockRouterConn, replicas []*_pgMock.MockRouterConn) {
+				gomock.InOrder(
+					replicas[0].EXPECT().Ping(gomock.Any()).Return(errors.New("")),
+					replicas[0].EXPECT().Ping(gomock.Any()).Return(nil),
+				)
+				replicas[1].EXPECT().Ping(gomock.Any()).Times(2).Return(errors.New(""))
+
+				gomock.InOrder(
+					primary.EXPECT().Query(gomock.Any(), "SELECT").Return(nil, nil),
+					replicas[0].EXPECT().Query(gomock.Any(), "SELECT").Return(nil, nil),
+				)
+			},
+			cmd: func(router *Router) error {
+				router.checkHealth(context.Background())
+				if _, err := router.Query(context.Background(), "SELECT"); err != nil {
+					return err
+				}
+
+				// The first replica recovers
+				router.checkHealth(context.Background())
+				_, err := router.Query(context.Background(), "SELECT")
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runRouterTestCase(t, &tc)
+		})
+	}
+}
+
+func TestRouter_Primary(t *testing.T) {
+	tcs := map[string]routerTestCase{
+		"SUCCESS writes go to the primary": {
+			reg: func(primary *_pgMock.MockRouterConn, _ []*_pgMock.MockRouterConn) {
+				primary.EXPECT().Execute(gomock.Any(), "UPDATE").Return(nil, nil)
+				primary.EXPECT().Begin(gomock.Any()).Return(nil, nil)
+				primary.EXPECT().SendBatch(gomock.Any(), gomock.Any()).Return(nil)
+				primary.EXPECT().CopyFrom(gomock.Any(), []string{"ideas"}, nil, nil).Return(int64(0), nil)
+			},
+			cmd: func(router *Router) error {
+				ctx := context.Background()
+				if _, err := router.Execute(ctx, "UPDATE"); err != nil {
+					return err
+				}
+				if _, err := router.Begin(ctx); err != nil {
+					return err
+				}
+
+				router.SendBatch(ctx, &database.Batch{})
+				_, err := router.CopyFrom(ctx, []string{"ideas"}, nil, nil)
+				return err
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS close": {
+			reg: func(primary *_pgMock.MockRouterConn, replicas []*_pgMock.MockRouterConn) {
+				primary.EXPECT().Close()
+				for _, replica := range replicas {
+					replica.EXPECT().Close()
+				}
+			},
+			cmd: func(router *Router) error {
+				router.Close()
+				return nil
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runRouterTestCase(t, &tc)
+		})
+	}
+}
+
+func TestNewRouter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The replica isn't pinged, so it gets the reads until it's checked
+	primary := _pgMock.NewMockRouterConn(ctrl)
+	replica := _pgMock.NewMockRouterConn(ctrl)
+	replica.EXPECT().Query(gomock.Any(), "SELECT").Times(1).Return(nil, nil)
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	router := NewRouter(primary, []RouterConn{replica}, logger, nil)
+
+	_, err := router.Query(context.Background(), "SELECT")
+	require.NoError(t, err)
+}
+
+func TestRouter_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	primary := _pgMock.NewMockRouterConn(ctrl)
+	down := _pgMock.NewMockRouterConn(ctrl)
+	gomock.InOrder(
+		down.EXPECT().Ping(gomock.Any()).Times(1).DoAndReturn(func(context.Context) error {
+			cancel()
+			return errors.New("")
+		}),
+		primary.EXPECT().Query(gomock.Any(), "SELECT").Times(1).Return(nil, nil),
+	)
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	router := NewRouter(primary, []RouterConn{down}, logger, nil)
+
+	// The replicas are checked right away, before the first tick
+	require.NoError(t, router.Run(ctx))
+
+	_, err := router.Query(context.Background(), "SELECT")
+	require.NoError(t, err)
+}
+
+// runRouterTestCase should be called by [testing.T.Run]
+func runRouterTestCase(t *testing.T, tc *routerTestCase) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	primary := _pgMock.NewMockRouterConn(ctrl)
+	replicas := []*_pgMock.MockRouterConn{_pgMock.NewMockRouterConn(ctrl), _pgMock.NewMockRouterConn(ctrl)}
+	if tc.reg != nil {
+		tc.reg(primary, replicas)
+	}
+
+	conns := make([]RouterConn, len(replicas))
+	for i, replica := range replicas {
+		conns[i] = replica
+	}
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	router := NewRouter(primary, conns, logger, nil)
+
+	var err error
+	if tc.cmd != nil {
+		err = tc.cmd(router)
+	}
+
+	if tc.exp != nil {
+		tc.exp(err)
+	}
+}
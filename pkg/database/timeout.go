@@ -0,0 +1,20 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+type queryTimeoutContextKey struct{}
+
+// WithQueryTimeout overrides the default query timeout of the connection
+// for the calls made with the context. The zero timeout disables it
+func WithQueryTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutContextKey{}, timeout)
+}
+
+// QueryTimeoutFromContext returns the timeout set by [WithQueryTimeout]
+func QueryTimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(queryTimeoutContextKey{}).(time.Duration)
+	return timeout, ok
+}
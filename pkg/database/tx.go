@@ -0,0 +1,224 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/pkg/internal/backoff"
+	"github.com/adanyl0v/pocket-ideas/pkg/log"
+	"strings"
+	"time"
+)
+
+type IsoLevel string
+
+const (
+	IsoLevelDefault        IsoLevel = ""
+	IsoLevelReadCommitted  IsoLevel = "READ COMMITTED"
+	IsoLevelRepeatableRead IsoLevel = "REPEATABLE READ"
+	IsoLevelSerializable   IsoLevel = "SERIALIZABLE"
+)
+
+const (
+	DefaultTxMaxRetries = 3
+	DefaultTxMinBackoff = 10 * time.Millisecond
+	DefaultTxMaxBackoff = time.Second
+)
+
+// TxOptions configure the transactions run by [TxRunner.RunInTx].
+// The zero values of the retry fields are replaced with the defaults,
+// so a negative MaxRetries is required to disable the retries
+type TxOptions struct {
+	IsoLevel   IsoLevel
+	ReadOnly   bool
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+type txContextKey struct{}
+
+// ContextWithTx returns a copy of the context, which carries the transaction
+func ContextWithTx(ctx context.Context, tx Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext returns the transaction set by [ContextWithTx]
+func TxFromContext(ctx context.Context) (Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(Tx)
+	return tx, ok && tx != nil
+}
+
+// ConnFromContext returns the transaction from the context or
+// the connection itself, if there is none
+func ConnFromContext(ctx context.Context, conn Conn) Conn {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+
+	return conn
+}
+
+// IsRetryable reports whether the transaction failed because of a conflict
+// with a concurrent one and may succeed, if it is run once again
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrSerializationFailure) || errors.Is(err, ErrDeadlockDetected)
+}
+
+// TxRunner removes the commit and rollback boilerplate. The functions it runs
+// receive a context with the transaction, so any repository that takes
+// its connection with [ConnFromContext] joins the transaction
+type TxRunner struct {
+	conn   Conn
+	logger log.Logger
+}
+
+func NewTxRunner(conn Conn, logger log.Logger) *TxRunner {
+	return &TxRunner{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+// nestedTxSavepoint is the same for all the nesting levels, because
+// a savepoint hides the older one with the same name until it is released.
+// That's why the savepoint is released even after it is rolled back to,
+// otherwise the outer level would roll back to the inner savepoint
+const nestedTxSavepoint = "nested_tx"
+
+// RunInTx commits the transaction, if the function returns nil, and rolls it
+// back otherwise. The whole transaction is retried with a backoff, if it
+// fails with [ErrSerializationFailure] or [ErrDeadlockDetected].
+//
+// If the context already carries a transaction, the function runs within
+// a savepoint of it, so an error only rolls back the changes made by the
+// function. The options are ignored then, and the retries are left
+// to the outermost call
+func (r *TxRunner) RunInTx(ctx context.Context, opts *TxOptions, fn func(ctx context.Context) error) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return runInSavepoint(ctx, tx, fn)
+	}
+
+	var o TxOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = DefaultTxMaxRetries
+	}
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = DefaultTxMinBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultTxMaxBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := r.runOnce(ctx, &o, fn)
+		if err == nil || !IsRetryable(err) || attempt >= o.MaxRetries {
+			return err
+		}
+
+		wait := backoff.Jittered(attempt, o.MinBackoff, o.MaxBackoff)
+		r.logger.With(log.Fields{
+			"attempt": attempt + 1,
+			"backoff": wait,
+		}).WithError(err).Warn("retrying an sql transaction")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func (r *TxRunner) runOnce(ctx context.Context, opts *TxOptions, fn func(ctx context.Context) error) (err error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	// A failed commit has already ended the transaction,
+	// so there is nothing to roll back
+	committing := false
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+
+		if err != nil && !committing {
+			if txErr := tx.Rollback(ctx); txErr != nil {
+				err = errors.Join(err, txErr)
+			}
+		}
+	}()
+
+	if query := setTransactionQuery(opts); query != "" {
+		if _, err = tx.Execute(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	if err = fn(ContextWithTx(ctx, tx)); err != nil {
+		return err
+	}
+
+	committing = true
+	return tx.Commit(ctx)
+}
+
+func runInSavepoint(ctx context.Context, tx Tx, fn func(ctx context.Context) error) (err error) {
+	if err = tx.Savepoint(ctx, nestedTxSavepoint); err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = rollbackToSavepoint(ctx, tx)
+			panic(p)
+		}
+
+		if err != nil {
+			if txErr := rollbackToSavepoint(ctx, tx); txErr != nil {
+				err = errors.Join(err, txErr)
+			}
+		}
+	}()
+
+	if err = fn(ctx); err != nil {
+		return err
+	}
+
+	return tx.Release(ctx, nestedTxSavepoint)
+}
+
+// rollbackToSavepoint undoes the changes made since the savepoint
+// and then releases it, so the outer one is visible again
+func rollbackToSavepoint(ctx context.Context, tx Tx) error {
+	if err := tx.RollbackTo(ctx, nestedTxSavepoint); err != nil {
+		return err
+	}
+
+	return tx.Release(ctx, nestedTxSavepoint)
+}
+
+// setTransactionQuery must be executed before any other query
+// of the transaction. It returns "" for the default options
+func setTransactionQuery(opts *TxOptions) string {
+	var modes []string
+	if opts.IsoLevel != IsoLevelDefault {
+		modes = append(modes, fmt.Sprintf("ISOLATION LEVEL %s", opts.IsoLevel))
+	}
+	if opts.ReadOnly {
+		modes = append(modes, "READ ONLY")
+	}
+
+	if len(modes) == 0 {
+		return ""
+	}
+
+	return "SET TRANSACTION " + strings.Join(modes, ", ")
+}
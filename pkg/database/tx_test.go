@@ -0,0 +1,383 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	_dbMock "github.com/adanyl0v/pocket-ideas/mocks/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/database"
+	"github.com/adanyl0v/pocket-ideas/pkg/log/slog"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	"github.com/golang/mock/gomock"
+	slogzap "github.com/samber/slog-zap/v2"
+	"github.com/stretchr/testify/require"
+	stdslog "log/slog"
+	"testing"
+	"time"
+)
+
+type (
+	txRunnerTestCaseRegister func(ctrl *gomock.Controller, conn *_dbMock.MockConn)
+	txRunnerTestCaseCommand  func(runner *database.TxRunner) error
+	txRunnerTestCaseExpect   func(err error)
+
+	txRunnerTestCase struct {
+		reg txRunnerTestCaseRegister
+		cmd txRunnerTestCaseCommand
+		exp txRunnerTestCaseExpect
+	}
+)
+
+var testTxOptions = &database.TxOptions{
+	MinBackoff: time.Millisecond,
+	MaxBackoff: time.Millisecond,
+}
+
+func TestTxRunner_RunInTx(t *testing.T) {
+	tcs := map[string]txRunnerTestCase{
+		"SUCCESS": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := _dbMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx, nil)
+				gomock.InOrder(
+					tx.EXPECT().Execute(gomock.Any(), "UPDATE").Return(nil, nil),
+					tx.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), nil, execInTx("UPDATE"))
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS with options": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := _dbMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx, nil)
+				gomock.InOrder(
+					tx.EXPECT().Execute(gomock.Any(), "SET TRANSACTION ISOLATION LEVEL SERIALIZABLE, READ ONLY").
+						Return(nil, nil),
+					tx.EXPECT().Execute(gomock.Any(), "SELECT").Return(nil, nil),
+					tx.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), &database.TxOptions{
+					IsoLevel: database.IsoLevelSerializable,
+					ReadOnly: true,
+				}, execInTx("SELECT"))
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS nested": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := _dbMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx, nil)
+				gomock.InOrder(
+					tx.EXPECT().Execute(gomock.Any(), "INSERT users").Return(nil, nil),
+					tx.EXPECT().Savepoint(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Execute(gomock.Any(), "INSERT ideas").Return(nil, nil),
+					tx.EXPECT().Release(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), nil, func(ctx context.Context) error {
+					if err := execInTx("INSERT users")(ctx); err != nil {
+						return err
+					}
+
+					return runner.RunInTx(ctx, nil, execInTx("INSERT ideas"))
+				})
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS nested partial rollback": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := _dbMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx, nil)
+				gomock.InOrder(
+					tx.EXPECT().Execute(gomock.Any(), "INSERT users").Return(nil, nil),
+					tx.EXPECT().Savepoint(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Execute(gomock.Any(), "INSERT ideas").
+						Return(nil, proxerr.New(database.ErrUniqueViolation, "")),
+					tx.EXPECT().RollbackTo(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Release(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Execute(gomock.Any(), "UPDATE users").Return(nil, nil),
+					tx.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), nil, func(ctx context.Context) error {
+					if err := execInTx("INSERT users")(ctx); err != nil {
+						return err
+					}
+
+					// The failed insert is rolled back, but the outer
+					// transaction goes on and is committed
+					err := runner.RunInTx(ctx, nil, execInTx("INSERT ideas"))
+					require.ErrorIs(t, err, database.ErrUniqueViolation)
+
+					return execInTx("UPDATE users")(ctx)
+				})
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS doubly nested partial rollback": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := _dbMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx, nil)
+				gomock.InOrder(
+					tx.EXPECT().Savepoint(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Execute(gomock.Any(), "INSERT ideas").Return(nil, nil),
+					tx.EXPECT().Savepoint(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Execute(gomock.Any(), "INSERT tags").
+						Return(nil, proxerr.New(database.ErrUniqueViolation, "")),
+					tx.EXPECT().RollbackTo(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Release(gomock.Any(), "nested_tx").Return(nil),
+					// The inner savepoint is released, so the middle
+					// level rolls back to its own one
+					tx.EXPECT().RollbackTo(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Release(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), nil, func(ctx context.Context) error {
+					err := runner.RunInTx(ctx, nil, func(ctx context.Context) error {
+						if err := execInTx("INSERT ideas")(ctx); err != nil {
+							return err
+						}
+
+						return runner.RunInTx(ctx, nil, execInTx("INSERT tags"))
+					})
+					require.ErrorIs(t, err, database.ErrUniqueViolation)
+
+					return nil
+				})
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED nested": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := _dbMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx, nil)
+				gomock.InOrder(
+					tx.EXPECT().Savepoint(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Execute(gomock.Any(), "INSERT ideas").
+						Return(nil, proxerr.New(database.ErrUniqueViolation, "")),
+					tx.EXPECT().RollbackTo(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Release(gomock.Any(), "nested_tx").Return(nil),
+					tx.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), nil, func(ctx context.Context) error {
+					return runner.RunInTx(ctx, nil, execInTx("INSERT ideas"))
+				})
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, database.ErrUniqueViolation)
+			},
+		},
+		"FAILED to create a savepoint": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := _dbMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx, nil)
+				gomock.InOrder(
+					tx.EXPECT().Savepoint(gomock.Any(), "nested_tx").Return(errors.New("")),
+					tx.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), nil, func(ctx context.Context) error {
+					return runner.RunInTx(ctx, nil, execInTx("INSERT ideas"))
+				})
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"SUCCESS retries a serialization failure": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				failed := _dbMock.NewMockTx(ctrl)
+				failed.EXPECT().Execute(gomock.Any(), "UPDATE").
+					Return(nil, proxerr.New(database.ErrSerializationFailure, ""))
+				failed.EXPECT().Rollback(gomock.Any()).Return(nil)
+
+				tx := _dbMock.NewMockTx(ctrl)
+				tx.EXPECT().Execute(gomock.Any(), "UPDATE").Return(nil, nil)
+				tx.EXPECT().Commit(gomock.Any()).Return(nil)
+
+				gomock.InOrder(
+					conn.EXPECT().Begin(gomock.Any()).Return(failed, nil),
+					conn.EXPECT().Begin(gomock.Any()).Return(tx, nil),
+				)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), testTxOptions, execInTx("UPDATE"))
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"SUCCESS retries a deadlock on commit": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				failed := _dbMock.NewMockTx(ctrl)
+				failed.EXPECT().Execute(gomock.Any(), "UPDATE").Return(nil, nil)
+				failed.EXPECT().Commit(gomock.Any()).Return(proxerr.New(database.ErrDeadlockDetected, ""))
+
+				tx := _dbMock.NewMockTx(ctrl)
+				tx.EXPECT().Execute(gomock.Any(), "UPDATE").Return(nil, nil)
+				tx.EXPECT().Commit(gomock.Any()).Return(nil)
+
+				gomock.InOrder(
+					conn.EXPECT().Begin(gomock.Any()).Return(failed, nil),
+					conn.EXPECT().Begin(gomock.Any()).Return(tx, nil),
+				)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), testTxOptions, execInTx("UPDATE"))
+			},
+			exp: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		"FAILED retries are exhausted": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := _dbMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Times(2).Return(tx, nil)
+				tx.EXPECT().Execute(gomock.Any(), "UPDATE").Times(2).
+					Return(nil, proxerr.New(database.ErrSerializationFailure, ""))
+				tx.EXPECT().Rollback(gomock.Any()).Times(2).Return(nil)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), &database.TxOptions{
+					MaxRetries: 1,
+					MinBackoff: time.Millisecond,
+					MaxBackoff: time.Millisecond,
+				}, execInTx("UPDATE"))
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, database.ErrSerializationFailure)
+			},
+		},
+		"FAILED retries are disabled": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := _dbMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx, nil)
+				tx.EXPECT().Execute(gomock.Any(), "UPDATE").
+					Return(nil, proxerr.New(database.ErrDeadlockDetected, ""))
+				tx.EXPECT().Rollback(gomock.Any()).Return(nil)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), &database.TxOptions{MaxRetries: -1}, execInTx("UPDATE"))
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, database.ErrDeadlockDetected)
+			},
+		},
+		"FAILED the function returns an error": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := _dbMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx, nil)
+				tx.EXPECT().Execute(gomock.Any(), "UPDATE").Return(nil, database.ErrUniqueViolation)
+				tx.EXPECT().Rollback(gomock.Any()).Return(nil)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), testTxOptions, execInTx("UPDATE"))
+			},
+			exp: func(err error) {
+				require.ErrorIs(t, err, database.ErrUniqueViolation)
+			},
+		},
+		"FAILED to begin a transaction": {
+			reg: func(_ *gomock.Controller, conn *_dbMock.MockConn) {
+				conn.EXPECT().Begin(gomock.Any()).Return(nil, errors.New(""))
+			},
+			cmd: func(runner *database.TxRunner) error {
+				return runner.RunInTx(context.Background(), nil, execInTx("UPDATE"))
+			},
+			exp: func(err error) {
+				require.Error(t, err)
+			},
+		},
+		"FAILED panic": {
+			reg: func(ctrl *gomock.Controller, conn *_dbMock.MockConn) {
+				tx := _dbMock.NewMockTx(ctrl)
+				conn.EXPECT().Begin(gomock.Any()).Return(tx, nil)
+				tx.EXPECT().Rollback(gomock.Any()).Return(nil)
+			},
+			cmd: func(runner *database.TxRunner) error {
+				require.Panics(t, func() {
+					_ = runner.RunInTx(context.Background(), nil, func(context.Context) error {
+						panic("")
+					})
+				})
+				return nil
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			runTxRunnerTestCase(t, &tc)
+		})
+	}
+}
+
+func TestConnFromContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn := _dbMock.NewMockConn(ctrl)
+	tx := _dbMock.NewMockTx(ctrl)
+
+	require.Equal(t, database.Conn(conn), database.ConnFromContext(context.Background(), conn))
+	require.Equal(t, database.Conn(tx), database.ConnFromContext(database.ContextWithTx(context.Background(), tx), conn))
+}
+
+// execInTx returns a function, which executes the query
+// within the transaction from the context
+func execInTx(query string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		tx, ok := database.TxFromContext(ctx)
+		if !ok {
+			return errors.New("no transaction in the context")
+		}
+
+		_, err := tx.Execute(ctx, query)
+		return err
+	}
+}
+
+// runTxRunnerTestCase should be called by [testing.T.Run]
+func runTxRunnerTestCase(t *testing.T, tc *txRunnerTestCase) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn := _dbMock.NewMockConn(ctrl)
+	if tc.reg != nil {
+		tc.reg(ctrl, conn)
+	}
+
+	logger := slog.NewLogger(stdslog.New(slogzap.Option{}.NewZapHandler()))
+	runner := database.NewTxRunner(conn, logger)
+
+	var err error
+	if tc.cmd != nil {
+		err = tc.cmd(runner)
+	}
+
+	if tc.exp != nil {
+		tc.exp(err)
+	}
+}
@@ -0,0 +1,20 @@
+package backoff
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Jittered grows exponentially from minBackoff up to maxBackoff and
+// is jittered, so that the concurrent callers don't retry all at once.
+// The result is in [backoff/2, backoff] for the attempt, which starts at 0
+func Jittered(attempt int, minBackoff, maxBackoff time.Duration) time.Duration {
+	// The shift is capped and checked for an overflow, so that
+	// a large attempt doesn't turn the backoff into a negative one
+	backoff := maxBackoff
+	if b := minBackoff << min(attempt, 32); b > 0 && b < maxBackoff {
+		backoff = b
+	}
+
+	return backoff/2 + rand.N(backoff/2+1)
+}
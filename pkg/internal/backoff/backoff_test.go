@@ -0,0 +1,38 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJittered(t *testing.T) {
+	type testCase struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}
+
+	tcs := map[string]testCase{
+		"first attempt":   {attempt: 0, min: 10 * time.Millisecond, max: time.Second},
+		"grows":           {attempt: 3, min: 10 * time.Millisecond, max: 80 * time.Millisecond},
+		"capped":          {attempt: 10, min: 10 * time.Millisecond, max: time.Second},
+		"shift overflows": {attempt: 100, min: time.Second, max: time.Minute},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			exp := min(tc.min<<min(tc.attempt, 32), tc.max)
+			if exp <= 0 {
+				exp = tc.max
+			}
+
+			for range 100 {
+				b := Jittered(tc.attempt, tc.min, tc.max)
+				require.GreaterOrEqual(t, b, exp/2)
+				require.LessOrEqual(t, b, exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadKeySet reads every key in the directory. A key file is named
+// "<kid>.<algorithm>", where the algorithm is one of "hs256", "rs256" or
+// "eddsa". An HS256 file contains the raw secret, the others contain
+// either a private or a public PEM encoded key
+func LoadKeySet(dir, signingKeyID string) (*KeySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		signing      Key
+		verification []Key
+	)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		key, err := LoadKeyFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if key.ID == signingKeyID {
+			signing = key
+		} else {
+			verification = append(verification, key)
+		}
+	}
+
+	if signing.ID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, signingKeyID)
+	}
+
+	return NewKeySet(signing, verification...)
+}
+
+func LoadKeyFile(path string) (Key, error) {
+	name := filepath.Base(path)
+	ext := filepath.Ext(name)
+	id := strings.TrimSuffix(name, ext)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch strings.ToLower(strings.TrimPrefix(ext, ".")) {
+	case strings.ToLower(string(HS256)):
+		return NewHMACKey(id, data)
+	case strings.ToLower(string(RS256)):
+		return NewRSAKeyFromPEM(id, data)
+	case strings.ToLower(string(EdDSA)):
+		return NewEdDSAKeyFromPEM(id, data)
+	default:
+		return Key{}, fmt.Errorf("%w: unknown algorithm of %s", ErrInvalidKey, name)
+	}
+}
@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadKeySet(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateDer, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+
+	publicDer, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "current.eddsa"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDer}))
+	writeFile(t, filepath.Join(dir, "previous.eddsa"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}))
+	writeFile(t, filepath.Join(dir, "legacy.hs256"), []byte("0123456789abcdef0123456789abcdef"))
+
+	keys, err := LoadKeySet(dir, "current")
+	require.NoError(t, err)
+	require.Equal(t, "current", keys.SigningKey().ID)
+	require.Equal(t, EdDSA, keys.SigningKey().Algorithm)
+
+	previous, ok := keys.Key("previous")
+	require.True(t, ok)
+	require.False(t, previous.CanSign())
+
+	legacy, ok := keys.Key("legacy")
+	require.True(t, ok)
+	require.Equal(t, HS256, legacy.Algorithm)
+
+	_, err = LoadKeySet(dir, "unknown")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = LoadKeySet(dir, "previous")
+	require.ErrorIs(t, err, ErrKeyCannotSign)
+
+	writeFile(t, filepath.Join(dir, "unknown.es256"), []byte{})
+	_, err = LoadKeySet(dir, "current")
+	require.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	require.NoError(t, os.WriteFile(path, data, 0600))
+}
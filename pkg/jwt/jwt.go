@@ -0,0 +1,176 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/pkg/proxerr"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// Claims contains the registered claims, which are validated on every
+// verification, and the session id that the token is bound to
+type Claims struct {
+	ID        string
+	Issuer    string
+	Subject   string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+	IssuedAt  time.Time
+	SessionID string
+}
+
+type Config struct {
+	// Issuer is set to the signed tokens and required in the verified ones
+	Issuer string
+
+	// Audience is set to the signed tokens. The verified ones must
+	// contain at least one of the audiences
+	Audience []string
+
+	// Leeway compensates clock skew when validating time based claims
+	Leeway time.Duration
+}
+
+type Manager struct {
+	keys   *KeySet
+	config Config
+	now    func() time.Time
+}
+
+func NewManager(keys *KeySet, config *Config) *Manager {
+	return &Manager{
+		keys:   keys,
+		config: *config,
+		now:    time.Now,
+	}
+}
+
+// Sign returns a token signed with the current signing key. The issuer,
+// audience and issue time are set if they are empty. The expiration time is required
+func (m *Manager) Sign(claims *Claims) (string, error) {
+	if claims.ExpiresAt.IsZero() {
+		return "", fmt.Errorf("%w: expiration time must be set", ErrInvalidToken)
+	}
+
+	if claims.Issuer == "" {
+		claims.Issuer = m.config.Issuer
+	}
+
+	if len(claims.Audience) == 0 {
+		claims.Audience = m.config.Audience
+	}
+
+	if claims.IssuedAt.IsZero() {
+		claims.IssuedAt = m.now()
+	}
+
+	key := m.keys.SigningKey()
+	token := gojwt.NewWithClaims(key.method(), newTokenClaims(claims))
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.signKey)
+}
+
+// Verify checks the signature with the key matching the "kid" header and
+// validates the registered claims. An expired token results in [ErrTokenExpired],
+// any other failure in [ErrInvalidToken]
+func (m *Manager) Verify(token string) (Claims, error) {
+	opts := []gojwt.ParserOption{
+		gojwt.WithExpirationRequired(),
+		gojwt.WithIssuedAt(),
+		gojwt.WithLeeway(m.config.Leeway),
+		gojwt.WithTimeFunc(m.now),
+	}
+	if m.config.Issuer != "" {
+		opts = append(opts, gojwt.WithIssuer(m.config.Issuer))
+	}
+	if len(m.config.Audience) != 0 {
+		opts = append(opts, gojwt.WithAudience(m.config.Audience...))
+	}
+
+	var tc tokenClaims
+	if _, err := gojwt.ParseWithClaims(token, &tc, m.keyFunc, opts...); err != nil {
+		if errors.Is(err, gojwt.ErrTokenExpired) {
+			return Claims{}, proxerr.New(ErrTokenExpired, err.Error())
+		}
+
+		return Claims{}, proxerr.New(ErrInvalidToken, err.Error())
+	}
+
+	if tc.IssuedAt == nil {
+		return Claims{}, proxerr.New(ErrInvalidToken, "token is missing required claim: iat claim is required")
+	}
+
+	return tc.toClaims(), nil
+}
+
+// keyFunc returns the verification key of the token. The algorithm of the
+// token must match the algorithm of the key, so that a public key is never
+// used as an HMAC secret
+func (m *Manager) keyFunc(token *gojwt.Token) (any, error) {
+	id, _ := token.Header["kid"].(string)
+	key, ok := m.keys.Key(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, id)
+	}
+
+	if token.Method.Alg() != string(key.Algorithm) {
+		return nil, fmt.Errorf("unexpected signing method %s of key %q", token.Method.Alg(), id)
+	}
+
+	return key.verifyKey, nil
+}
+
+type tokenClaims struct {
+	gojwt.RegisteredClaims
+	SessionID string `json:"sid,omitempty"`
+}
+
+func newTokenClaims(c *Claims) *tokenClaims {
+	tc := tokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        c.ID,
+			Issuer:    c.Issuer,
+			Subject:   c.Subject,
+			Audience:  c.Audience,
+			ExpiresAt: gojwt.NewNumericDate(c.ExpiresAt),
+			IssuedAt:  gojwt.NewNumericDate(c.IssuedAt),
+		},
+		SessionID: c.SessionID,
+	}
+
+	if !c.NotBefore.IsZero() {
+		tc.NotBefore = gojwt.NewNumericDate(c.NotBefore)
+	}
+
+	return &tc
+}
+
+func (tc *tokenClaims) toClaims() Claims {
+	c := Claims{
+		ID:        tc.ID,
+		Issuer:    tc.Issuer,
+		Subject:   tc.Subject,
+		Audience:  tc.Audience,
+		SessionID: tc.SessionID,
+	}
+
+	if tc.ExpiresAt != nil {
+		c.ExpiresAt = tc.ExpiresAt.Time
+	}
+	if tc.NotBefore != nil {
+		c.NotBefore = tc.NotBefore.Time
+	}
+	if tc.IssuedAt != nil {
+		c.IssuedAt = tc.IssuedAt.Time
+	}
+
+	return c
+}
@@ -0,0 +1,210 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var testConfig = Config{
+	Issuer:   "pocket-ideas",
+	Audience: []string{"pocket-ideas"},
+}
+
+func TestManager_SignVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tcs := map[string]func() (Key, error){
+		"HS256": func() (Key, error) { return NewHMACKey("hs", make([]byte, MinHMACSecretSize)) },
+		"RS256": func() (Key, error) { return NewRSAKey("rs", rsaKey) },
+		"EdDSA": func() (Key, error) { return NewEdDSAKey("ed", edKey) },
+	}
+
+	for name, newKey := range tcs {
+		t.Run(name, func(t *testing.T) {
+			key, err := newKey()
+			require.NoError(t, err)
+
+			m := newTestManager(t, key)
+			token, err := m.Sign(&Claims{
+				Subject:   "user",
+				SessionID: "session",
+				ExpiresAt: time.Now().Add(time.Minute),
+			})
+			require.NoError(t, err)
+
+			claims, err := m.Verify(token)
+			require.NoError(t, err)
+			require.Equal(t, "user", claims.Subject)
+			require.Equal(t, "session", claims.SessionID)
+			require.Equal(t, testConfig.Issuer, claims.Issuer)
+			require.Equal(t, testConfig.Audience, claims.Audience)
+		})
+	}
+}
+
+func TestManager_Verify(t *testing.T) {
+	key, err := NewHMACKey("key", []byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+
+	now := time.Now()
+	valid := Claims{SessionID: "session", ExpiresAt: now.Add(time.Minute)}
+
+	tcs := map[string]struct {
+		claims Claims
+		config Config
+		exp    error
+	}{
+		"SUCCESS": {
+			claims: valid,
+			config: testConfig,
+		},
+		"SUCCESS expired within leeway": {
+			claims: Claims{ExpiresAt: now.Add(-time.Second)},
+			config: Config{Issuer: testConfig.Issuer, Audience: testConfig.Audience, Leeway: time.Minute},
+		},
+		"FAILED expired": {
+			claims: Claims{ExpiresAt: now.Add(-time.Minute)},
+			config: testConfig,
+			exp:    ErrTokenExpired,
+		},
+		"FAILED not valid yet": {
+			claims: Claims{ExpiresAt: now.Add(time.Hour), NotBefore: now.Add(time.Minute)},
+			config: testConfig,
+			exp:    ErrInvalidToken,
+		},
+		"FAILED issued in the future": {
+			claims: Claims{ExpiresAt: now.Add(time.Hour), IssuedAt: now.Add(time.Minute)},
+			config: testConfig,
+			exp:    ErrInvalidToken,
+		},
+		"FAILED invalid issuer": {
+			claims: Claims{Issuer: "other", ExpiresAt: now.Add(time.Minute)},
+			config: testConfig,
+			exp:    ErrInvalidToken,
+		},
+		"FAILED invalid audience": {
+			claims: Claims{Audience: []string{"other"}, ExpiresAt: now.Add(time.Minute)},
+			config: testConfig,
+			exp:    ErrInvalidToken,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			keys, err := NewKeySet(key)
+			require.NoError(t, err)
+
+			m := NewManager(keys, &tc.config)
+			token, err := m.Sign(&tc.claims)
+			require.NoError(t, err)
+
+			_, err = m.Verify(token)
+			if tc.exp == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			require.True(t, errors.Is(err, tc.exp), err.Error())
+		})
+	}
+}
+
+func TestManager_Verify_KeyRotation(t *testing.T) {
+	oldKey, err := NewHMACKey("old", []byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+
+	newKey, err := NewHMACKey("new", []byte("fedcba9876543210fedcba9876543210"))
+	require.NoError(t, err)
+
+	m := newTestManager(t, oldKey)
+	oldToken, err := m.Sign(&Claims{ExpiresAt: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+
+	require.NoError(t, m.keys.Add(newKey))
+	require.NoError(t, m.keys.SetSigningKey(newKey.ID))
+
+	newToken, err := m.Sign(&Claims{ExpiresAt: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+
+	header, err := parseHeader(newToken)
+	require.NoError(t, err)
+	require.Equal(t, "new", header["kid"])
+
+	// Both keys are valid during the overlap
+	_, err = m.Verify(oldToken)
+	require.NoError(t, err)
+	_, err = m.Verify(newToken)
+	require.NoError(t, err)
+
+	require.NoError(t, m.keys.Remove(oldKey.ID))
+	_, err = m.Verify(oldToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	_, err = m.Verify(newToken)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, m.keys.Remove(newKey.ID), ErrSigningKeyInUse)
+}
+
+func TestManager_Verify_AlgorithmMismatch(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	key, err := NewRSAKey("key", rsaKey)
+	require.NoError(t, err)
+	m := newTestManager(t, key)
+
+	// A token signed with HMAC under the id of the RSA key must not be accepted
+	token := gojwt.NewWithClaims(gojwt.SigningMethodHS256, newTokenClaims(&Claims{
+		Issuer:    testConfig.Issuer,
+		Audience:  testConfig.Audience,
+		ExpiresAt: time.Now().Add(time.Minute),
+		IssuedAt:  time.Now(),
+	}))
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+
+	_, err = m.Verify(signed)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestKeySet_SetSigningKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	public, err := NewRSAPublicKey("public", &rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	_, err = NewKeySet(public)
+	require.ErrorIs(t, err, ErrKeyCannotSign)
+
+	_, err = NewHMACKey("short", []byte("secret"))
+	require.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func newTestManager(t *testing.T, key Key) *Manager {
+	keys, err := NewKeySet(key)
+	require.NoError(t, err)
+
+	return NewManager(keys, &testConfig)
+}
+
+func parseHeader(token string) (map[string]any, error) {
+	parsed, _, err := gojwt.NewParser().ParseUnverified(token, &tokenClaims{})
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.Header, nil
+}
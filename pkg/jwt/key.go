@@ -0,0 +1,216 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"sync"
+)
+
+var (
+	ErrKeyNotFound      = errors.New("key not found")
+	ErrKeyAlreadyExists = errors.New("key already exists")
+	ErrKeyCannotSign    = errors.New("key cannot sign")
+	ErrSigningKeyInUse  = errors.New("signing key is in use")
+	ErrInvalidKey       = errors.New("invalid key")
+)
+
+type Algorithm string
+
+const (
+	HS256 Algorithm = "HS256"
+	RS256 Algorithm = "RS256"
+	EdDSA Algorithm = "EdDSA"
+)
+
+// MinHMACSecretSize is the minimal HS256 secret size in bytes, as required by RFC 7518
+const MinHMACSecretSize = 32
+
+// Key is identified by the "kid" header of the tokens it signs. A key
+// without the private part is only able to verify tokens
+type Key struct {
+	ID        string
+	Algorithm Algorithm
+
+	signKey   any
+	verifyKey any
+}
+
+func NewHMACKey(id string, secret []byte) (Key, error) {
+	if len(secret) < MinHMACSecretSize {
+		return Key{}, fmt.Errorf("%w: secret must contain at least %d bytes", ErrInvalidKey, MinHMACSecretSize)
+	}
+
+	return newKey(id, HS256, secret, secret)
+}
+
+func NewRSAKey(id string, key *rsa.PrivateKey) (Key, error) {
+	if key == nil {
+		return Key{}, ErrInvalidKey
+	}
+
+	return newKey(id, RS256, key, &key.PublicKey)
+}
+
+func NewRSAPublicKey(id string, key *rsa.PublicKey) (Key, error) {
+	if key == nil {
+		return Key{}, ErrInvalidKey
+	}
+
+	return newKey(id, RS256, nil, key)
+}
+
+// NewRSAKeyFromPEM parses either a private or a public PEM encoded key
+func NewRSAKeyFromPEM(id string, data []byte) (Key, error) {
+	if private, err := gojwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return NewRSAKey(id, private)
+	}
+
+	public, err := gojwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	return NewRSAPublicKey(id, public)
+}
+
+func NewEdDSAKey(id string, key ed25519.PrivateKey) (Key, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return Key{}, ErrInvalidKey
+	}
+
+	return newKey(id, EdDSA, key, key.Public())
+}
+
+func NewEdDSAPublicKey(id string, key ed25519.PublicKey) (Key, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return Key{}, ErrInvalidKey
+	}
+
+	return newKey(id, EdDSA, nil, key)
+}
+
+// NewEdDSAKeyFromPEM parses either a private or a public PEM encoded key
+func NewEdDSAKeyFromPEM(id string, data []byte) (Key, error) {
+	if private, err := gojwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+		return NewEdDSAKey(id, private.(ed25519.PrivateKey))
+	}
+
+	public, err := gojwt.ParseEdPublicKeyFromPEM(data)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	return NewEdDSAPublicKey(id, public.(ed25519.PublicKey))
+}
+
+func newKey(id string, alg Algorithm, signKey any, verifyKey crypto.PublicKey) (Key, error) {
+	if id == "" {
+		return Key{}, fmt.Errorf("%w: id must not be empty", ErrInvalidKey)
+	}
+
+	return Key{
+		ID:        id,
+		Algorithm: alg,
+		signKey:   signKey,
+		verifyKey: verifyKey,
+	}, nil
+}
+
+func (k Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+func (k Key) method() gojwt.SigningMethod {
+	return gojwt.GetSigningMethod(string(k.Algorithm))
+}
+
+// KeySet signs tokens with a single key and verifies them with any of
+// the keys. To rotate keys, add the new key, make it the signing one,
+// and remove the old key once the tokens it signed are expired
+type KeySet struct {
+	mu        sync.RWMutex
+	keys      map[string]Key
+	signingID string
+}
+
+func NewKeySet(signing Key, verification ...Key) (*KeySet, error) {
+	s := &KeySet{keys: make(map[string]Key, len(verification)+1)}
+
+	for _, k := range append([]Key{signing}, verification...) {
+		if err := s.Add(k); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.SetSigningKey(signing.ID); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *KeySet) Add(key Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.keys[key.ID]; ok {
+		return fmt.Errorf("%w: %s", ErrKeyAlreadyExists, key.ID)
+	}
+
+	s.keys[key.ID] = key
+	return nil
+}
+
+// Remove forbids verifying tokens with the key. The signing key must
+// be replaced before it is removed
+func (s *KeySet) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.keys[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+	}
+
+	if id == s.signingID {
+		return fmt.Errorf("%w: %s", ErrSigningKeyInUse, id)
+	}
+
+	delete(s.keys, id)
+	return nil
+}
+
+func (s *KeySet) SetSigningKey(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+	}
+
+	if !key.CanSign() {
+		return fmt.Errorf("%w: %s", ErrKeyCannotSign, id)
+	}
+
+	s.signingID = id
+	return nil
+}
+
+func (s *KeySet) SigningKey() Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.keys[s.signingID]
+}
+
+func (s *KeySet) Key(id string) (Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[id]
+	return key, ok
+}
@@ -57,7 +57,7 @@ func (l *Logger) With(fields log.Fields) log.Logger {
 	clone := l.clone()
 
 	clone.attrs = slices.Clone(l.attrs)
-	slices.Grow(clone.attrs, len(fields)/2)
+	clone.attrs = slices.Grow(clone.attrs, len(fields)/2)
 
 	computeFields(&clone.attrs, fields)
 	return clone
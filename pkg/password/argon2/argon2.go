@@ -0,0 +1,161 @@
+package argon2
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/adanyl0v/pocket-ideas/pkg/password"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+// Prefix starts every hash in the [PHC string format]:
+//
+//	$argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+//
+// [PHC string format]: https://github.com/P-H-C/phc-string-format/blob/master/phc-sf-spec.md
+const Prefix = "$argon2id$"
+
+const (
+	DefaultMemory      = 64 * 1024
+	DefaultIterations  = 3
+	DefaultParallelism = 2
+	DefaultSaltLength  = 16
+	DefaultKeyLength   = 32
+)
+
+var ErrInvalidParams = errors.New("invalid argon2 params")
+
+type Params struct {
+	// Memory is the amount of memory in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+func DefaultParams() Params {
+	return Params{
+		Memory:      DefaultMemory,
+		Iterations:  DefaultIterations,
+		Parallelism: DefaultParallelism,
+		SaltLength:  DefaultSaltLength,
+		KeyLength:   DefaultKeyLength,
+	}
+}
+
+// Validate rejects the params the hashes can't be computed or verified with
+func (p Params) Validate() error {
+	switch {
+	case p.Memory == 0:
+		return fmt.Errorf("%w: memory must be positive", ErrInvalidParams)
+	case p.Iterations == 0:
+		return fmt.Errorf("%w: iterations must be positive", ErrInvalidParams)
+	case p.Parallelism == 0:
+		return fmt.Errorf("%w: parallelism must be positive", ErrInvalidParams)
+	case p.SaltLength == 0:
+		return fmt.Errorf("%w: salt length must be positive", ErrInvalidParams)
+	case p.KeyLength == 0:
+		return fmt.Errorf("%w: key length must be positive", ErrInvalidParams)
+	}
+
+	return nil
+}
+
+type hasher struct {
+	params Params
+}
+
+func New(params Params) password.Hasher {
+	return &hasher{params: params}
+}
+
+func (h *hasher) Hash(pwd string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(pwd), salt, h.params.Iterations,
+		h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", Prefix, argon2.Version,
+		h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *hasher) Verify(pwd, hash string) (bool, error) {
+	d, err := decode(hash)
+	if err != nil {
+		return false, err
+	}
+
+	key := argon2.IDKey([]byte(pwd), d.salt, d.params.Iterations,
+		d.params.Memory, d.params.Parallelism, d.params.KeyLength)
+
+	return subtle.ConstantTimeCompare(key, d.key) == 1, nil
+}
+
+func (h *hasher) NeedsRehash(hash string) bool {
+	d, err := decode(hash)
+	if err != nil {
+		return true
+	}
+
+	return d.version != argon2.Version ||
+		d.params.Memory != h.params.Memory ||
+		d.params.Iterations != h.params.Iterations ||
+		d.params.Parallelism != h.params.Parallelism ||
+		d.params.SaltLength != h.params.SaltLength ||
+		d.params.KeyLength != h.params.KeyLength
+}
+
+func (h *hasher) Supports(hash string) bool {
+	return strings.HasPrefix(hash, Prefix)
+}
+
+type decodedHash struct {
+	version int
+	params  Params
+	salt    []byte
+	key     []byte
+}
+
+func decode(hash string) (decodedHash, error) {
+	// The leading "$" produces an empty part
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != strings.Trim(Prefix, "$") {
+		return decodedHash{}, password.ErrInvalidHash
+	}
+
+	var d decodedHash
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &d.version); err != nil {
+		return decodedHash{}, password.ErrInvalidHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d",
+		&d.params.Memory, &d.params.Iterations, &d.params.Parallelism); err != nil {
+		return decodedHash{}, password.ErrInvalidHash
+	}
+
+	var err error
+	if d.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return decodedHash{}, password.ErrInvalidHash
+	}
+
+	if d.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return decodedHash{}, password.ErrInvalidHash
+	}
+
+	if d.params.Iterations == 0 || d.params.Parallelism == 0 || len(d.key) == 0 {
+		return decodedHash{}, password.ErrInvalidHash
+	}
+
+	d.params.SaltLength = uint32(len(d.salt))
+	d.params.KeyLength = uint32(len(d.key))
+	return d, nil
+}
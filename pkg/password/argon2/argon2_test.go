@@ -0,0 +1,97 @@
+package argon2
+
+import (
+	"github.com/adanyl0v/pocket-ideas/pkg/password"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+// testParams are cheap enough to keep the tests fast
+var testParams = Params{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  DefaultSaltLength,
+	KeyLength:   DefaultKeyLength,
+}
+
+func TestParams_Validate(t *testing.T) {
+	tcs := map[string]struct {
+		modify func(p *Params)
+		err    error
+	}{
+		"SUCCESS":              {modify: func(*Params) {}},
+		"FAILED no memory":     {modify: func(p *Params) { p.Memory = 0 }, err: ErrInvalidParams},
+		"FAILED no iterations": {modify: func(p *Params) { p.Iterations = 0 }, err: ErrInvalidParams},
+		"FAILED no threads":    {modify: func(p *Params) { p.Parallelism = 0 }, err: ErrInvalidParams},
+		"FAILED no salt":       {modify: func(p *Params) { p.SaltLength = 0 }, err: ErrInvalidParams},
+		"FAILED no key":        {modify: func(p *Params) { p.KeyLength = 0 }, err: ErrInvalidParams},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			params := testParams
+			tc.modify(&params)
+			require.ErrorIs(t, params.Validate(), tc.err)
+		})
+	}
+}
+
+func TestHasher_Hash(t *testing.T) {
+	h := New(testParams)
+
+	hash, err := h.Hash("password")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	require.True(t, h.Supports(hash))
+
+	other, err := h.Hash("password")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other, "salt must be random")
+}
+
+func TestHasher_Verify(t *testing.T) {
+	h := New(testParams)
+	hash, err := h.Hash("password")
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		password string
+		hash     string
+		ok       bool
+		err      error
+	}{
+		"SUCCESS":                       {password: "password", hash: hash, ok: true},
+		"SUCCESS mismatched password":   {password: "invalid", hash: hash},
+		"FAILED not an argon2id hash":   {password: "password", hash: "$2a$04$abc", err: password.ErrInvalidHash},
+		"FAILED invalid params":         {password: "password", hash: "$argon2id$v=19$m=a,t=1,p=1$c2FsdA$a2V5", err: password.ErrInvalidHash},
+		"FAILED invalid salt encoding":  {password: "password", hash: "$argon2id$v=19$m=1024,t=1,p=1$!$a2V5", err: password.ErrInvalidHash},
+		"FAILED missing hash component": {password: "password", hash: "$argon2id$v=19$m=1024,t=1,p=1$c2FsdA", err: password.ErrInvalidHash},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			ok, err := h.Verify(tc.password, tc.hash)
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	hash, err := New(testParams).Hash("password")
+	require.NoError(t, err)
+
+	stronger := testParams
+	stronger.Iterations++
+
+	require.False(t, New(testParams).NeedsRehash(hash))
+	require.True(t, New(stronger).NeedsRehash(hash))
+	require.True(t, New(testParams).NeedsRehash("invalid"))
+
+	// The outdated hash must still be verified by the new hasher
+	ok, err := New(stronger).Verify("password", hash)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
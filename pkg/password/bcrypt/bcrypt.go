@@ -0,0 +1,68 @@
+package bcrypt
+
+import (
+	"errors"
+	"github.com/adanyl0v/pocket-ideas/pkg/password"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+const (
+	MinCost     = bcrypt.MinCost
+	MaxCost     = bcrypt.MaxCost
+	DefaultCost = 12
+)
+
+// prefixes of the bcrypt hash versions. The cost is encoded right after
+// the prefix, so a hash looks like "$2a$12$<salt><hash>"
+var prefixes = []string{"$2a$", "$2b$", "$2y$"}
+
+type hasher struct {
+	cost int
+}
+
+func New(cost int) password.Hasher {
+	return &hasher{cost: cost}
+}
+
+func (h *hasher) Hash(pwd string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(pwd), h.cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (h *hasher) Verify(pwd, hash string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(pwd))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return false, nil
+	case errors.Is(err, bcrypt.ErrHashTooShort):
+		return false, password.ErrInvalidHash
+	default:
+		return false, err
+	}
+}
+
+func (h *hasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return true
+	}
+
+	return cost != h.cost
+}
+
+func (h *hasher) Supports(hash string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(hash, p) {
+			return true
+		}
+	}
+
+	return false
+}
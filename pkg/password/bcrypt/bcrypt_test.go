@@ -0,0 +1,44 @@
+package bcrypt
+
+import (
+	"github.com/adanyl0v/pocket-ideas/pkg/password"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestHasher_Verify(t *testing.T) {
+	h := New(MinCost)
+	hash, err := h.Hash("password")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hash, "$2a$04$"))
+	require.True(t, h.Supports(hash))
+
+	tcs := map[string]struct {
+		password string
+		hash     string
+		ok       bool
+		err      error
+	}{
+		"SUCCESS":                     {password: "password", hash: hash, ok: true},
+		"SUCCESS mismatched password": {password: "invalid", hash: hash},
+		"FAILED invalid hash":         {password: "password", hash: "$2a$", err: password.ErrInvalidHash},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			ok, err := h.Verify(tc.password, tc.hash)
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	hash, err := New(MinCost).Hash("password")
+	require.NoError(t, err)
+
+	require.False(t, New(MinCost).NeedsRehash(hash))
+	require.True(t, New(MinCost+1).NeedsRehash(hash))
+	require.True(t, New(MinCost).NeedsRehash("invalid"))
+}
@@ -0,0 +1,78 @@
+package password
+
+import "errors"
+
+var (
+	ErrInvalidHash          = errors.New("invalid password hash")
+	ErrUnsupportedAlgorithm = errors.New("unsupported password hashing algorithm")
+)
+
+type Hasher interface {
+	// Hash returns the encoded hash, which records the algorithm and its parameters
+	Hash(password string) (string, error)
+
+	// Verify reports whether the password matches the encoded hash
+	Verify(password, hash string) (bool, error)
+
+	// NeedsRehash reports whether the hash was computed with an algorithm
+	// or parameters other than the current ones
+	NeedsRehash(hash string) bool
+
+	// Supports reports whether the hasher is able to verify the hash
+	Supports(hash string) bool
+}
+
+// Chain hashes passwords with the current hasher, but is able to verify
+// the hashes produced by the legacy ones. Such hashes always need a rehash,
+// so the stored hashes are migrated to the current algorithm on the next login
+type Chain struct {
+	current Hasher
+	legacy  []Hasher
+}
+
+func NewChain(current Hasher, legacy ...Hasher) *Chain {
+	return &Chain{
+		current: current,
+		legacy:  legacy,
+	}
+}
+
+func (c *Chain) Hash(password string) (string, error) {
+	return c.current.Hash(password)
+}
+
+func (c *Chain) Verify(password, hash string) (bool, error) {
+	h, ok := c.find(hash)
+	if !ok {
+		return false, ErrUnsupportedAlgorithm
+	}
+
+	return h.Verify(password, hash)
+}
+
+func (c *Chain) NeedsRehash(hash string) bool {
+	if c.current.Supports(hash) {
+		return c.current.NeedsRehash(hash)
+	}
+
+	return true
+}
+
+func (c *Chain) Supports(hash string) bool {
+	_, ok := c.find(hash)
+	return ok
+}
+
+func (c *Chain) find(hash string) (Hasher, bool) {
+	if c.current.Supports(hash) {
+		return c.current, true
+	}
+
+	for _, h := range c.legacy {
+		if h.Supports(hash) {
+			return h, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,48 @@
+package password_test
+
+import (
+	"github.com/adanyl0v/pocket-ideas/pkg/password"
+	"github.com/adanyl0v/pocket-ideas/pkg/password/argon2"
+	"github.com/adanyl0v/pocket-ideas/pkg/password/bcrypt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	argon2Hasher := argon2.New(argon2.Params{
+		Memory:      1024,
+		Iterations:  1,
+		Parallelism: 1,
+		SaltLength:  argon2.DefaultSaltLength,
+		KeyLength:   argon2.DefaultKeyLength,
+	})
+	bcryptHasher := bcrypt.New(bcrypt.MinCost)
+	chain := password.NewChain(argon2Hasher, bcryptHasher)
+
+	legacyHash, err := bcryptHasher.Hash("password")
+	require.NoError(t, err)
+
+	currentHash, err := chain.Hash("password")
+	require.NoError(t, err)
+	require.True(t, argon2Hasher.Supports(currentHash))
+
+	tcs := map[string]struct {
+		hash        string
+		ok          bool
+		needsRehash bool
+		err         error
+	}{
+		"SUCCESS current hash":         {hash: currentHash, ok: true},
+		"SUCCESS legacy hash":          {hash: legacyHash, ok: true, needsRehash: true},
+		"FAILED unsupported algorithm": {hash: "plaintext", needsRehash: true, err: password.ErrUnsupportedAlgorithm},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			ok, err := chain.Verify("password", tc.hash)
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.needsRehash, chain.NeedsRehash(tc.hash))
+		})
+	}
+}